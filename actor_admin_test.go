@@ -0,0 +1,139 @@
+package cherry
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cactor "github.com/cherry-game/cherry/net/actor"
+	cadmin "github.com/cherry-game/cherry/net/admin"
+	ccluster "github.com/cherry-game/cherry/net/cluster"
+	jsoniter "github.com/json-iterator/go"
+)
+
+func httpGet(t *testing.T, url string) (int, string) {
+	rsp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rsp.Body.Close()
+
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rsp.StatusCode, string(body)
+}
+
+// TestAdmin_Probes verifies the probes and the actor tree dump of the admin component.
+func TestAdmin_Probes(t *testing.T) {
+	admin := cadmin.New("127.0.0.1:0")
+
+	app := Configure(writeTestProfile(t, "admin"), "game-1", false, Standalone)
+	app.Register(admin)
+	app.AddActors(&parentActor{failed: make(chan cactor.Directive, 1)})
+	go app.Startup()
+	defer app.Shutdown()
+
+	waitRunning(t, app)
+
+	baseURL := "http://" + admin.Addr().String()
+
+	if code, _ := httpGet(t, baseURL+"/healthz"); code != http.StatusOK {
+		t.Fatalf("expected healthz 200, got %d", code)
+	}
+
+	if code, body := httpGet(t, baseURL+"/readyz"); code != http.StatusOK {
+		t.Fatalf("expected readyz 200, got %d. %s", code, body)
+	}
+
+	code, body := httpGet(t, baseURL+"/components")
+	if code != http.StatusOK || !strings.Contains(body, `"name":"admin_component"`) {
+		t.Fatalf("unexpected components %d. %s", code, body)
+	}
+
+	code, body = httpGet(t, baseURL+"/actors")
+	if code != http.StatusOK {
+		t.Fatalf("expected actors 200, got %d", code)
+	}
+
+	var actors []*cactor.ActorInfo
+	if err := jsoniter.UnmarshalFromString(body, &actors); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(actors) != 1 || actors[0].Path != "game-1.parent" || len(actors[0].Children) != 1 {
+		t.Fatalf("unexpected actor tree %s", body)
+	}
+
+	if !strings.Contains(body, `"state":"running"`) {
+		t.Fatalf("expected the state names in %s", body)
+	}
+
+	if child := actors[0].Children[0]; child.Path != "game-1.parent.child" || len(child.Remote) != 1 || child.Remote[0] != "crash" {
+		t.Fatalf("unexpected child %s", body)
+	}
+
+	// a required node type without discovery is never ready
+	notReady := cadmin.New("127.0.0.1:0", "center")
+	notReady.Set(app)
+	if ready, _ := notReady.Ready(); ready {
+		t.Fatal("expected not ready")
+	}
+}
+
+// TestAdmin_Drain verifies that POST /drain puts the node into draining: it is
+// no longer ready, and routing skips it.
+func TestAdmin_Drain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.json")
+	if err := os.WriteFile(path, []byte(testClusterProfile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	admin := cadmin.New("127.0.0.1:0")
+
+	app := Configure(path, "game-1", false, Cluster)
+	app.SetCluster(ccluster.NewMemory())
+	app.Register(admin)
+
+	defer startApps(t, app)()
+
+	baseURL := "http://" + admin.Addr().String()
+
+	if code, body := httpGet(t, baseURL+"/readyz"); code != http.StatusOK {
+		t.Fatalf("expected readyz 200, got %d. %s", code, body)
+	}
+
+	if code, _ := httpGet(t, baseURL+"/drain"); code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected drain 405 on GET, got %d", code)
+	}
+
+	rsp, err := http.Post(baseURL+"/drain", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("expected drain 200, got %d", rsp.StatusCode)
+	}
+
+	if code, body := httpGet(t, baseURL+"/readyz"); code != http.StatusServiceUnavailable || !strings.Contains(body, "draining") {
+		t.Fatalf("expected readyz 503, got %d. %s", code, body)
+	}
+
+	code, body := httpGet(t, baseURL+"/members")
+	if code != http.StatusOK || !strings.Contains(body, `"nodeId":"game-1","nodeType":"game","address":"","status":"draining"`) {
+		t.Fatalf("unexpected members %d. %s", code, body)
+	}
+
+	for _, member := range app.Discovery().ListByType("game") {
+		if member.GetNodeID() == "game-1" {
+			t.Fatal("draining member should be skipped")
+		}
+	}
+}
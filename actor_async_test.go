@@ -0,0 +1,117 @@
+package cherry
+
+import (
+	"testing"
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cactor "github.com/cherry-game/cherry/net/actor"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+type slowActor struct {
+	cactor.Base
+}
+
+func (*slowActor) AliasID() string {
+	return "slow"
+}
+
+func (p *slowActor) OnInit() {
+	p.Remote().Register("incr", p.incr)
+}
+
+func (p *slowActor) incr(req *cproto.I32) (*cproto.I32, int32) {
+	time.Sleep(200 * time.Millisecond)
+	return &cproto.I32{Value: req.Value + 1}, ccode.OK
+}
+
+// fanActor fans out async calls and keeps serving messages meanwhile.
+type fanActor struct {
+	cactor.Base
+	results chan int32
+	done    int // only touched on the actor goroutine
+}
+
+func (*fanActor) AliasID() string {
+	return "fan"
+}
+
+func (p *fanActor) OnInit() {
+	p.Remote().Register("start", p.start)
+	p.Remote().Register("ping", p.ping)
+}
+
+func (p *fanActor) start(_ *cproto.I32) int32 {
+	for i := int32(1); i <= 2; i++ {
+		reply := &cproto.I32{}
+		p.CallAsync(".slow", "incr", &cproto.I32{Value: i * 10}, reply, func(code int32) {
+			p.done++
+			if ccode.IsFail(code) {
+				p.results <- -code
+				return
+			}
+			p.results <- reply.Value
+		})
+	}
+
+	p.CallAsync(".missing", "incr", &cproto.I32{}, nil, func(code int32) {
+		p.done++
+		p.results <- -code
+	})
+
+	return ccode.OK
+}
+
+func (p *fanActor) ping(_ *cproto.I32) (*cproto.I32, int32) {
+	return &cproto.I32{Value: int32(p.done)}, ccode.OK
+}
+
+// TestCallAsync verifies that async calls do not block the caller and that
+// their callbacks deliver the replies and error codes.
+func TestCallAsync(t *testing.T) {
+	app := Configure(writeTestProfile(t, "async"), "game-1", false, Standalone)
+
+	fan := &fanActor{results: make(chan int32, 3)}
+	app.AddActors(&slowActor{}, fan)
+	go app.Startup()
+	defer app.Shutdown()
+
+	waitRunning(t, app)
+
+	system := app.ActorSystem()
+	if code := system.CallWait(".test", ".fan", "start", &cproto.I32{}, nil); ccode.IsFail(code) {
+		t.Fatalf("start fail. code = %d", code)
+	}
+
+	begin := time.Now()
+	if code := system.CallWait(".test", ".fan", "ping", &cproto.I32{}, &cproto.I32{}); ccode.IsFail(code) {
+		t.Fatalf("ping fail. code = %d", code)
+	}
+
+	if elapsed := time.Since(begin); elapsed > 150*time.Millisecond {
+		t.Fatalf("caller blocked for %v", elapsed)
+	}
+
+	results := map[int32]bool{}
+	for i := 0; i < 3; i++ {
+		select {
+		case value := <-fan.results:
+			results[value] = true
+		case <-time.After(3 * time.Second):
+			t.Fatal("callback not called")
+		}
+	}
+
+	for _, expected := range []int32{11, 21, -ccode.ActorInvokeRemoteError} {
+		if !results[expected] {
+			t.Fatalf("expected %d in %v", expected, results)
+		}
+	}
+
+	reply := &cproto.I32{}
+	system.CallWait(".test", ".fan", "ping", &cproto.I32{}, reply)
+	if reply.Value != 3 {
+		t.Fatalf("expected 3 callbacks, got %d", reply.Value)
+	}
+}
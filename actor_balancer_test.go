@@ -0,0 +1,152 @@
+package cherry
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cfacade "github.com/cherry-game/cherry/facade"
+	cactor "github.com/cherry-game/cherry/net/actor"
+	cbalancer "github.com/cherry-game/cherry/net/balancer"
+	ccluster "github.com/cherry-game/cherry/net/cluster"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+const testBalancerProfile = `{
+  "env": "memory",
+  "print_level": "info",
+  "cluster": {
+    "discovery": {"mode": "default"},
+    "balancer": {"game": "round_robin"}
+  },
+  "node": {
+    "center": [{"node_id": "center-1", "__settings__": {}}],
+    "game": [
+      {"node_id": "game-1", "__settings__": {}},
+      {"node_id": "game-2", "__settings__": {}}
+    ]
+  }
+}`
+
+// hitCounter counts the calls received by each node.
+type hitCounter struct {
+	sync.Mutex
+	hits map[string]int
+}
+
+func (p *hitCounter) get(nodeID string) int {
+	p.Lock()
+	defer p.Unlock()
+	return p.hits[nodeID]
+}
+
+type hitActor struct {
+	cactor.Base
+	counter *hitCounter
+}
+
+func (*hitActor) AliasID() string {
+	return "hit"
+}
+
+func (p *hitActor) OnInit() {
+	p.Remote().Register("hit", p.hit)
+}
+
+func (p *hitActor) hit(_ *cproto.I32) {
+	p.counter.Lock()
+	defer p.counter.Unlock()
+	p.counter.hits[p.App().NodeID()]++
+}
+
+// callerActor calls the hit actor through CallType.
+type callerActor struct {
+	cactor.Base
+}
+
+func (p *callerActor) OnInit() {
+	p.Remote().Register("call", p.call)
+}
+
+func (p *callerActor) call(_ *cproto.I32) {
+	p.CallType("game", "hit", "hit", &cproto.I32{})
+}
+
+// startBalancer starts center-1, game-1 and game-2 with a hit actor, game
+// members are chosen by strategy.
+func startBalancer(t *testing.T, strategy string, counter *hitCounter) ([]*AppBuilder, func()) {
+	path := filepath.Join(t.TempDir(), "balancer.json")
+	profile := strings.Replace(testBalancerProfile, cbalancer.RoundRobin, strategy, 1)
+	if err := os.WriteFile(path, []byte(profile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var apps []*AppBuilder
+	for _, nodeID := range []string{"center-1", "game-1", "game-2"} {
+		app := Configure(path, nodeID, false, Cluster)
+		app.SetCluster(ccluster.NewMemory())
+		app.Register(cbalancer.New())
+		app.AddActors(&hitActor{counter: counter})
+		apps = append(apps, app)
+	}
+
+	return apps, startApps(t, apps...)
+}
+
+// waitHits waits until the game members received count calls.
+func waitHits(t *testing.T, counter *hitCounter, count int) {
+	deadline := time.Now().Add(3 * time.Second)
+	for counter.get("game-1")+counter.get("game-2") < count {
+		if time.Now().After(deadline) {
+			t.Fatalf("calls not received. hits = %v", counter.hits)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+}
+
+// TestCallType_Balancer verifies that CallType calls one member chosen by
+// the strategy the profile selects for the node type.
+func TestCallType_Balancer(t *testing.T) {
+	counter := &hitCounter{hits: make(map[string]int)}
+	apps, stop := startBalancer(t, cbalancer.RoundRobin, counter)
+	defer stop()
+
+	system := apps[0].ActorSystem()
+	for i := 0; i < 4; i++ {
+		system.CallType("game", "hit", "hit", &cproto.I32{})
+	}
+
+	waitHits(t, counter, 4)
+	if counter.get("game-1") != 2 || counter.get("game-2") != 2 {
+		t.Fatalf("expected 2 calls per member, got %v", counter.hits)
+	}
+}
+
+// TestCallType_ActorKey verifies that the CallType of an actor is balanced by
+// the called actor id, whatever actor calls it.
+func TestCallType_ActorKey(t *testing.T) {
+	counter := &hitCounter{hits: make(map[string]int)}
+	apps, stop := startBalancer(t, cbalancer.ConsistentHash, counter)
+	defer stop()
+
+	const callers = 8
+	system := apps[0].ActorSystem()
+	for i := 0; i < callers; i++ {
+		callerID := fmt.Sprintf("caller-%d", i)
+		if _, err := system.CreateActor(callerID, &callerActor{}); err != nil {
+			t.Fatal(err)
+		}
+		system.Call("center-1.test", cfacade.NewPath("center-1", callerID), "call", &cproto.I32{})
+	}
+
+	waitHits(t, counter, callers)
+	if counter.get("game-1") != callers && counter.get("game-2") != callers {
+		t.Fatalf("expected every call on one member, got %v", counter.hits)
+	}
+}
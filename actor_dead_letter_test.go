@@ -0,0 +1,82 @@
+package cherry
+
+import (
+	"testing"
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cactor "github.com/cherry-game/cherry/net/actor"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+// deadLetterActor collects the undeliverable messages.
+type deadLetterActor struct {
+	cactor.Base
+	letters chan *cactor.DeadLetterMessage
+}
+
+func (*deadLetterActor) AliasID() string {
+	return "dead_letter"
+}
+
+func (p *deadLetterActor) OnDeadLetter(letter *cactor.DeadLetterMessage) {
+	p.letters <- letter
+}
+
+type emptyActor struct {
+	cactor.Base
+}
+
+func (*emptyActor) AliasID() string {
+	return "empty"
+}
+
+// TestDeadLetter verifies that undeliverable messages reach the dead-letter
+// actor with their reason, and that waiting callers get an error code.
+func TestDeadLetter(t *testing.T) {
+	app := Configure(writeTestProfile(t, "dead_letter"), "game-1", false, Standalone)
+
+	letters := make(chan *cactor.DeadLetterMessage, 8)
+	app.AddActors(&deadLetterActor{letters: letters}, &emptyActor{})
+	app.ActorSystem().(*cactor.Component).SetDeadLetterActor("dead_letter")
+
+	go app.Startup()
+	defer app.Shutdown()
+
+	waitRunning(t, app)
+
+	tests := []struct {
+		target string
+		code   int32
+		reason string
+	}{
+		{".missing", ccode.ActorInvokeRemoteError, cactor.DeadLetterActorNotFound},
+		{".empty", ccode.ActorFuncNotFound, cactor.DeadLetterFuncNotFound},
+		{".empty.child", ccode.ActorChildIDNotFound, cactor.DeadLetterChildNotFound},
+	}
+
+	system := app.ActorSystem()
+	for _, test := range tests {
+		start := time.Now()
+		code := system.CallWait(".test", test.target, "hello", &cproto.I32{Value: 1}, &cproto.I32{})
+		if code != test.code {
+			t.Fatalf("[%s] expected code %d, got %d", test.target, test.code, code)
+		}
+
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("[%s] expected an immediate reply, got %v", test.target, elapsed)
+		}
+
+		select {
+		case letter := <-letters:
+			if letter.Reason != test.reason || letter.Source != ".test" || letter.FuncName != "hello" {
+				t.Fatalf("[%s] unexpected letter %+v", test.target, letter)
+			}
+			if letter.Message == nil || letter.Message.Target != test.target {
+				t.Fatalf("[%s] expected the original message, got %+v", test.target, letter.Message)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("[%s] expected a dead letter", test.target)
+		}
+	}
+}
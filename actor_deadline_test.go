@@ -0,0 +1,101 @@
+package cherry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cactor "github.com/cherry-game/cherry/net/actor"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+type deadlineActor struct {
+	cactor.Base
+	counted int32
+}
+
+func (*deadlineActor) AliasID() string {
+	return "deadline"
+}
+
+func (p *deadlineActor) OnInit() {
+	p.Remote().Register("sleep", p.sleep)
+	p.Remote().Register("count", p.count)
+	p.Remote().Register("budget", p.budget)
+	p.Remote().Register("relay", p.relay)
+}
+
+func (p *deadlineActor) sleep(req *cproto.I32) {
+	time.Sleep(time.Duration(req.Value) * time.Millisecond)
+}
+
+func (p *deadlineActor) count(_ *cproto.I32) (*cproto.I32, int32) {
+	p.counted++
+	return &cproto.I32{Value: p.counted}, ccode.OK
+}
+
+// budget returns the remaining time of the call in ms.
+func (p *deadlineActor) budget(_ *cproto.I32) (*cproto.I32, int32) {
+	deadline, ok := p.Deadline()
+	if !ok {
+		return &cproto.I32{Value: -1}, ccode.OK
+	}
+	return &cproto.I32{Value: int32(time.Until(deadline).Milliseconds())}, ccode.OK
+}
+
+// relay asks the budget of another actor, inheriting the deadline of this call.
+func (p *deadlineActor) relay(_ *cproto.I32) (*cproto.I32, int32) {
+	reply := &cproto.I32{}
+	code := p.CallWait(".budget", "budget", &cproto.I32{}, reply)
+	return reply, code
+}
+
+type budgetActor struct {
+	deadlineActor
+}
+
+func (*budgetActor) AliasID() string {
+	return "budget"
+}
+
+// TestCallDeadline verifies that deadlines reach the handlers, propagate to
+// nested calls and that expired messages are dropped before invocation.
+func TestCallDeadline(t *testing.T) {
+	app := Configure(writeTestProfile(t, "deadline"), "game-1", false, Standalone)
+	app.AddActors(&deadlineActor{}, &budgetActor{})
+	go app.Startup()
+	defer app.Shutdown()
+
+	waitRunning(t, app)
+
+	system := app.ActorSystem()
+	callWait := func(timeout time.Duration, target, funcName string, reply *cproto.I32) int32 {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return system.CallWaitContext(ctx, ".test", target, funcName, &cproto.I32{}, reply)
+	}
+
+	for _, funcName := range []string{"budget", "relay"} {
+		reply := &cproto.I32{}
+		if code := callWait(500*time.Millisecond, ".deadline", funcName, reply); ccode.IsFail(code) {
+			t.Fatalf("[%s] call fail. code = %d", funcName, code)
+		}
+
+		if reply.Value <= 0 || reply.Value > 500 {
+			t.Fatalf("[%s] expected budget in (0, 500], got %d", funcName, reply.Value)
+		}
+	}
+
+	// keep the actor busy until the next call expires
+	system.Call(".test", ".deadline", "sleep", &cproto.I32{Value: 300})
+
+	if code := callWait(100*time.Millisecond, ".deadline", "count", &cproto.I32{}); code != ccode.ActorCallTimeout {
+		t.Fatalf("expected timeout, got %d", code)
+	}
+
+	reply := &cproto.I32{}
+	if code := callWait(time.Second, ".deadline", "count", reply); ccode.IsFail(code) || reply.Value != 1 {
+		t.Fatalf("expected the expired call to be dropped, got %d. code = %d", reply.Value, code)
+	}
+}
@@ -0,0 +1,100 @@
+package cherry
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cerror "github.com/cherry-game/cherry/error"
+	cactor "github.com/cherry-game/cherry/net/actor"
+	ccluster "github.com/cherry-game/cherry/net/cluster"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+const itemNotEnough int32 = 1001
+
+type shopActor struct {
+	cactor.Base
+}
+
+func (*shopActor) AliasID() string {
+	return "shop"
+}
+
+func (p *shopActor) OnInit() {
+	p.Remote().Register("buy", p.buy)
+	p.Remote().Register("sell", p.sell)
+}
+
+func (p *shopActor) buy(req *cproto.I32) (*cproto.I32, error) {
+	if req.Value > 1 {
+		return nil, cerror.NewCode(itemNotEnough).WithDetail("item", "sword")
+	}
+	return &cproto.I32{Value: req.Value}, nil
+}
+
+func (p *shopActor) sell(_ *cproto.I32) error {
+	return errors.New("shop closed")
+}
+
+// TestCallWaitError verifies that the code, message and details of an error
+// returned by a handler reach same-node and cross-node callers.
+func TestCallWaitError(t *testing.T) {
+	ccode.Register(itemNotEnough, "item not enough")
+
+	path := filepath.Join(t.TempDir(), "memory.json")
+	if err := os.WriteFile(path, []byte(testClusterProfile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var apps []*AppBuilder
+	for _, nodeID := range []string{"center-1", "game-1"} {
+		app := Configure(path, nodeID, false, Cluster)
+		app.SetCluster(ccluster.NewMemory())
+		app.AddActors(&shopActor{})
+		apps = append(apps, app)
+		go app.Startup()
+	}
+
+	defer func() {
+		for _, app := range apps {
+			app.Shutdown()
+		}
+	}()
+
+	waitRunning(t, apps...)
+
+	system := apps[0].ActorSystem()
+	for _, target := range []string{".shop", "game-1.shop"} {
+		reply := &cproto.I32{}
+		if err := system.CallWaitError("center-1.test", target, "buy", &cproto.I32{Value: 1}, reply); err != nil || reply.Value != 1 {
+			t.Fatalf("[%s] expected success, got %v. reply = %d", target, err, reply.Value)
+		}
+
+		err := system.CallWaitError("center-1.test", target, "buy", &cproto.I32{Value: 2}, reply)
+		var codeErr *cerror.CodeError
+		if !errors.As(err, &codeErr) {
+			t.Fatalf("[%s] expected CodeError, got %v", target, err)
+		}
+
+		if codeErr.Code != itemNotEnough || codeErr.Message != "item not enough" || codeErr.Details["item"] != "sword" {
+			t.Fatalf("[%s] unexpected error %v", target, codeErr)
+		}
+
+		err = system.CallWaitError("center-1.test", target, "sell", &cproto.I32{}, nil)
+		if cerror.CodeOf(err) != ccode.RPCRemoteExecuteError || cerror.AsCode(err).Message != "shop closed" {
+			t.Fatalf("[%s] unexpected error %v", target, err)
+		}
+
+		if code := system.CallWait("center-1.test", target, "buy", &cproto.I32{Value: 2}, reply); code != itemNotEnough {
+			t.Fatalf("[%s] expected code %d, got %d", target, itemNotEnough, code)
+		}
+	}
+
+	err := system.CallWaitError("center-1.test", ".missing", "buy", &cproto.I32{}, nil)
+	if codeErr := cerror.AsCode(err); codeErr.Code != ccode.ActorInvokeRemoteError || codeErr.Message != ccode.Desc(ccode.ActorInvokeRemoteError) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package cherry
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cfacade "github.com/cherry-game/cherry/facade"
+	cbalancer "github.com/cherry-game/cherry/net/balancer"
+	ccluster "github.com/cherry-game/cherry/net/cluster"
+	cload "github.com/cherry-game/cherry/net/load"
+)
+
+// testLoadProfile runs the node under a nodeID of its own, the tests before
+// may not have released game-1 from the memory cluster yet.
+const testLoadProfile = `{
+  "env": "memory",
+  "print_level": "info",
+  "cluster": {
+    "discovery": {"mode": "default"}
+  },
+  "node": {
+    "game": [{"node_id": "load-1", "__settings__": {}}]
+  }
+}`
+
+// TestLoad_Report verifies that the load component publishes the loads into
+// the member settings, and publishes again only when a value moved by more
+// than the threshold.
+func TestLoad_Report(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.json")
+	if err := os.WriteFile(path, []byte(testLoadProfile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := Configure(path, "load-1", false, Cluster)
+	app.SetCluster(ccluster.NewMemory())
+	app.AddActors(&shopActor{})
+
+	load := cload.New(10 * time.Millisecond)
+	load.SetThreshold(1e9)
+	load.SetLoadFunc(func(stats cload.Stats) float64 {
+		return float64(stats.Actors)
+	})
+	app.Register(load)
+
+	defer startApps(t, app)()
+
+	// the loads were published once by OnAfterInit
+	var updates atomic.Int32
+	app.Discovery().OnUpdateMember(func(member cfacade.IMember) {
+		if member.GetNodeID() == "load-1" {
+			updates.Add(1)
+		}
+	})
+
+	time.Sleep(100 * time.Millisecond)
+
+	member, _ := app.Discovery().GetMember("load-1")
+	settings := member.GetSettings()
+	for _, key := range []string{cload.SettingAgents, cload.SettingActors, cload.SettingMailbox,
+		cload.SettingGoroutines, cload.SettingCPU, cload.SettingHeap, cbalancer.SettingLoad} {
+		if _, found := settings[key]; !found {
+			t.Fatalf("setting %s not published. settings = %v", key, settings)
+		}
+	}
+
+	if actors, _ := strconv.Atoi(settings[cload.SettingActors]); actors < 1 || settings[cbalancer.SettingLoad] != settings[cload.SettingActors] {
+		t.Fatalf("unexpected settings %v", settings)
+	}
+
+	if n := updates.Load(); n != 0 {
+		t.Fatalf("expected no update below the threshold, got %d", n)
+	}
+}
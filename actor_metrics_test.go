@@ -0,0 +1,75 @@
+package cherry
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cmetrics "github.com/cherry-game/cherry/net/metrics"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+// TestMetrics_Actor verifies that the actor system reports its metrics at
+// /metrics, labeled with the handler types of the actors.
+func TestMetrics_Actor(t *testing.T) {
+	body := scrapeActorMetrics(t, false)
+
+	for _, line := range []string{
+		`cherry_actor_invoke_seconds_count{actor="cherry.backActor",mailbox="remote",func="echo"} 1`,
+		`cherry_actor_count{kind="actor"} 1`,
+		`cherry_actor_mailbox_depth{actor="cherry.backActor",mailbox="remote"} 0`,
+		`cherry_timer_active `,
+	} {
+		if !strings.Contains(body, line) {
+			t.Fatalf("expected %q in\n%s", line, body)
+		}
+	}
+}
+
+// TestMetrics_ActorByID verifies that SetMetricsByID labels the metrics with actor ids.
+func TestMetrics_ActorByID(t *testing.T) {
+	body := scrapeActorMetrics(t, true)
+
+	for _, line := range []string{
+		`cherry_actor_invoke_seconds_count{actor="back",mailbox="remote",func="echo"} 1`,
+		`cherry_actor_mailbox_depth{actor="back",mailbox="remote"} 0`,
+	} {
+		if !strings.Contains(body, line) {
+			t.Fatalf("expected %q in\n%s", line, body)
+		}
+	}
+}
+
+// scrapeActorMetrics calls the back actor once and returns the /metrics page.
+func scrapeActorMetrics(t *testing.T, byID bool) string {
+	metrics := cmetrics.New("127.0.0.1:0")
+
+	app := Configure(writeTestProfile(t, "metrics"), "game-1", false, Standalone)
+	app.Register(metrics)
+	app.AddActors(&backActor{})
+	app.ActorSystem().SetMetricsByID(byID)
+	go app.Startup()
+	defer app.Shutdown()
+
+	waitRunning(t, app)
+
+	reply := &cproto.I32{}
+	if code := app.ActorSystem().CallWait(".test", ".back", "echo", &cproto.I32{Value: 1}, reply); ccode.IsFail(code) {
+		t.Fatalf("call fail. code = %d", code)
+	}
+
+	rsp, err := http.Get("http://" + metrics.Addr().String() + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rsp.Body.Close()
+
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(body)
+}
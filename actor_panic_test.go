@@ -0,0 +1,71 @@
+package cherry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cactor "github.com/cherry-game/cherry/net/actor"
+	ccluster "github.com/cherry-game/cherry/net/cluster"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+type panicActor struct {
+	cactor.Base
+}
+
+func (*panicActor) AliasID() string {
+	return "panic"
+}
+
+func (p *panicActor) OnInit() {
+	p.Remote().Register("boom", p.boom)
+	cactor.RegisterRemote(p.Remote(), "typedBoom", p.boom)
+}
+
+func (p *panicActor) boom(_ *cproto.I32) (*cproto.I32, int32) {
+	panic("boom")
+}
+
+// TestCallWait_HandlerPanic verifies that a panic in a remote handler answers
+// same-node and cross-node callers immediately with ActorInvokeRemoteError.
+func TestCallWait_HandlerPanic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.json")
+	if err := os.WriteFile(path, []byte(testClusterProfile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var apps []*AppBuilder
+	for _, nodeID := range []string{"center-1", "game-1"} {
+		app := Configure(path, nodeID, false, Cluster)
+		app.SetCluster(ccluster.NewMemory())
+		app.AddActors(&panicActor{})
+		apps = append(apps, app)
+		go app.Startup()
+	}
+
+	defer func() {
+		for _, app := range apps {
+			app.Shutdown()
+		}
+	}()
+
+	waitRunning(t, apps...)
+
+	system := apps[0].ActorSystem()
+	for _, target := range []string{".panic", "game-1.panic"} {
+		for _, funcName := range []string{"boom", "typedBoom"} {
+			start := time.Now()
+			code := system.CallWait("center-1.test", target, funcName, &cproto.I32{}, &cproto.I32{})
+			if code != ccode.ActorInvokeRemoteError {
+				t.Fatalf("[%s -> %s] expected code %d, got %d", target, funcName, ccode.ActorInvokeRemoteError, code)
+			}
+
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("[%s -> %s] expected an immediate reply, got %v", target, funcName, elapsed)
+			}
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package cherry
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cfacade "github.com/cherry-game/cherry/facade"
+	cactor "github.com/cherry-game/cherry/net/actor"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+// playerStore persists player counters across passivation.
+type playerStore struct {
+	sync.Mutex
+	values      map[string]int32
+	passivated  int
+	activations int
+}
+
+func (p *playerStore) load(id string) int32 {
+	p.Lock()
+	defer p.Unlock()
+	p.activations++
+	return p.values[id]
+}
+
+func (p *playerStore) save(id string, value int32) {
+	p.Lock()
+	defer p.Unlock()
+	p.passivated++
+	p.values[id] = value
+}
+
+type playerActor struct {
+	cactor.Base
+	store *playerStore
+	value int32
+}
+
+func (p *playerActor) OnInit() {
+	p.value = p.store.load(p.ActorID())
+	p.SetIdleTTL(100 * time.Millisecond)
+	p.Remote().Register("incr", p.incr)
+}
+
+func (p *playerActor) OnPassivate() {
+	p.store.save(p.ActorID(), p.value)
+}
+
+func (p *playerActor) incr(_ *cproto.I32) (*cproto.I32, int32) {
+	p.value++
+	return &cproto.I32{Value: p.value}, ccode.OK
+}
+
+type playersActor struct {
+	cactor.Base
+	store *playerStore
+}
+
+func (*playersActor) AliasID() string {
+	return "players"
+}
+
+func (p *playersActor) OnInit() {
+	p.Child().SetFactory(func(id string) (cfacade.IActorHandler, bool) {
+		return &playerActor{store: p.store}, true
+	})
+}
+
+// TestActorPassivation verifies that idle actors are passivated and that the
+// factories recreate them with their persisted state on the next message.
+func TestActorPassivation(t *testing.T) {
+	app := Configure(writeTestProfile(t, "passivation"), "game-1", false, Standalone)
+
+	store := &playerStore{values: map[string]int32{}}
+	app.AddActors(&playersActor{store: store})
+	app.ActorSystem().SetFactory(func(id string) (cfacade.IActorHandler, bool) {
+		if id != "room" {
+			return nil, false
+		}
+		return &playerActor{store: store}, true
+	})
+
+	go app.Startup()
+	defer app.Shutdown()
+
+	waitRunning(t, app)
+
+	system := app.ActorSystem()
+	incr := func(target string, expected int32) {
+		reply := &cproto.I32{}
+		code := system.CallWait(".test", target, "incr", &cproto.I32{}, reply)
+		if ccode.IsFail(code) || reply.Value != expected {
+			t.Fatalf("[%s] expected %d, got %d. code = %d", target, expected, reply.Value, code)
+		}
+	}
+
+	waitPassivated := func(path string) {
+		deadline := time.Now().Add(3 * time.Second)
+		for {
+			if _, found := app.ActorSystem().(*cactor.Component).GetActorWithPath(path); !found {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("[%s] expected actor to be passivated", path)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+
+	for _, target := range []string{".players.u1", ".room"} {
+		incr(target, 1)
+		incr(target, 2)
+
+		waitPassivated(target)
+
+		incr(target, 3)
+	}
+
+	store.Lock()
+	defer store.Unlock()
+
+	if store.activations != 4 || store.passivated < 2 {
+		t.Fatalf("expected 4 activations and 2 passivations, got %d and %d", store.activations, store.passivated)
+	}
+}
+
+// TestActorPassivation_Stopping verifies that a message sent while an actor
+// is being passivated does not wait for it to stop and reaches the actor the
+// factory creates again.
+func TestActorPassivation_Stopping(t *testing.T) {
+	app := Configure(writeTestProfile(t, "passivation-stopping"), "game-1", false, Standalone)
+
+	store := &playerStore{values: map[string]int32{}}
+	app.ActorSystem().SetFactory(func(id string) (cfacade.IActorHandler, bool) {
+		return &playerActor{store: store}, id == "room"
+	})
+
+	go app.Startup()
+	defer app.Shutdown()
+
+	waitRunning(t, app)
+
+	system := app.ActorSystem()
+	incr := func(expected int32) {
+		reply := &cproto.I32{}
+		code := system.CallWait(".test", ".room", "incr", &cproto.I32{}, reply)
+		if ccode.IsFail(code) || reply.Value != expected {
+			t.Fatalf("expected %d, got %d. code = %d", expected, reply.Value, code)
+		}
+	}
+
+	incr(1)
+
+	room, found := system.(*cactor.Component).GetActorWithPath(".room")
+	if !found {
+		t.Fatal("room actor not found")
+	}
+
+	// keep the passivated actor in the system while the next call is sent
+	passivating := make(chan struct{})
+	room.Passivate(func() {
+		close(passivating)
+		time.Sleep(100 * time.Millisecond)
+	})
+	<-passivating
+
+	start := time.Now()
+	if code := system.Call(".test", ".room", "incr", &cproto.I32{}); ccode.IsFail(code) {
+		t.Fatalf("expected the message to be parked, got code = %d", code)
+	}
+
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("the sender waited %v for the passivation", elapsed)
+	}
+
+	incr(3)
+}
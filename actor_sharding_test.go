@@ -0,0 +1,237 @@
+package cherry
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cfacade "github.com/cherry-game/cherry/facade"
+	cactor "github.com/cherry-game/cherry/net/actor"
+	ccluster "github.com/cherry-game/cherry/net/cluster"
+	cproto "github.com/cherry-game/cherry/net/proto"
+	csharding "github.com/cherry-game/cherry/net/sharding"
+)
+
+// entityLog records where the sharded entities were activated and passivated.
+type entityLog struct {
+	sync.Mutex
+	events map[string]int // "nodeID/entityID/init|passivate" → count
+}
+
+func (p *entityLog) add(nodeID, entityID, event string) {
+	p.Lock()
+	defer p.Unlock()
+	p.events[nodeID+"/"+entityID+"/"+event]++
+}
+
+func (p *entityLog) count(nodeID, entityID, event string) int {
+	p.Lock()
+	defer p.Unlock()
+	return p.events[nodeID+"/"+entityID+"/"+event]
+}
+
+type entityActor struct {
+	cactor.Base
+	log *entityLog
+}
+
+func (p *entityActor) OnInit() {
+	p.log.add(p.App().NodeID(), p.Path().ChildID, "init")
+	p.Remote().Register("where", p.where)
+	p.Remote().Register("add", p.add)
+	p.Remote().Register("sleep", p.sleep)
+}
+
+func (p *entityActor) OnPassivate() {
+	p.log.add(p.App().NodeID(), p.Path().ChildID, "passivate")
+}
+
+func (p *entityActor) where(_ *cproto.I32) (*cproto.NodeID, int32) {
+	return &cproto.NodeID{Value: p.App().NodeID()}, ccode.OK
+}
+
+func (p *entityActor) add(_ *cproto.I32) int32 {
+	p.log.add(p.App().NodeID(), p.Path().ChildID, "add")
+	return ccode.OK
+}
+
+func (p *entityActor) sleep(_ *cproto.I32) int32 {
+	time.Sleep(100 * time.Millisecond)
+	return ccode.OK
+}
+
+// shardingCluster runs center-1, game-1 and game-2 with a "player" region on
+// the game nodes. entityID is owned by game-2 while every member is known.
+type shardingCluster struct {
+	apps     []*AppBuilder
+	regions  []*csharding.Region
+	log      *entityLog
+	entityID string
+	game2    cfacade.IMember
+}
+
+func startSharding(t *testing.T) (*shardingCluster, func()) {
+	path := filepath.Join(t.TempDir(), "memory.json")
+	if err := os.WriteFile(path, []byte(testClusterProfile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &shardingCluster{log: &entityLog{events: make(map[string]int)}}
+	for _, nodeID := range []string{"center-1", "game-1", "game-2"} {
+		app := Configure(path, nodeID, false, Cluster)
+		app.SetCluster(ccluster.NewMemory())
+
+		sharding := csharding.New()
+		c.regions = append(c.regions, sharding.Register("player", "game", 16, func(entityID string) cfacade.IActorHandler {
+			return &entityActor{log: c.log}
+		}))
+		app.Register(sharding)
+
+		c.apps = append(c.apps, app)
+	}
+
+	stop := startApps(t, c.apps...)
+
+	for i := 1; i <= 100 && c.entityID == ""; i++ {
+		id := strconv.Itoa(i)
+		owner, _ := c.regions[0].Owner(id)
+		for _, region := range c.regions[1:] {
+			if nodeID, _ := region.Owner(id); nodeID != owner {
+				t.Fatalf("[entityID = %s] owner mismatch. %s != %s", id, nodeID, owner)
+			}
+		}
+
+		if owner == "game-2" {
+			c.entityID = id
+		}
+	}
+
+	if c.entityID == "" {
+		t.Fatal("no entity owned by game-2")
+	}
+
+	c.game2, _ = c.apps[0].Discovery().GetMember("game-2")
+	return c, stop
+}
+
+type shardingMembers interface {
+	AddMember(member cfacade.IMember)
+	RemoveMember(nodeID string)
+}
+
+// leave removes game-2 from the discovery of center-1 and game-1.
+func (c *shardingCluster) leave() {
+	for _, app := range c.apps[:2] {
+		app.Discovery().(shardingMembers).RemoveMember("game-2")
+	}
+}
+
+// join adds game-2 back to the discovery of center-1 and game-1.
+func (c *shardingCluster) join() {
+	for _, app := range c.apps[:2] {
+		app.Discovery().(shardingMembers).AddMember(c.game2)
+	}
+}
+
+// where returns the node running the entity, calling it through path when set.
+func (c *shardingCluster) where(t *testing.T, path string) string {
+	reply := &cproto.NodeID{}
+
+	var code int32
+	if path == "" {
+		code = c.regions[0].CallWait("center-1.test", c.entityID, "where", &cproto.I32{}, reply)
+	} else {
+		code = c.apps[0].ActorSystem().CallWait("center-1.test", path, "where", &cproto.I32{}, reply)
+	}
+
+	if code != ccode.OK {
+		t.Fatalf("call entity fail. code = %d", code)
+	}
+	return reply.Value
+}
+
+// waitHandoff waits for the entity to move from game-1 to game-2.
+func (c *shardingCluster) waitHandoff(t *testing.T) {
+	deadline := time.Now().Add(3 * time.Second)
+	for c.log.count("game-1", c.entityID, "passivate") < 1 || c.log.count("game-2", c.entityID, "init") < 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("entity not handed off. events = %v", c.log.events)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestSharding_Rebalance verifies that every node computes the same shard
+// owners, that calls by entity id reach the owner, and that a member joining
+// moves its entities from the old owner to the new one.
+func TestSharding_Rebalance(t *testing.T) {
+	c, stop := startSharding(t)
+	defer stop()
+
+	if nodeID := c.where(t, ""); nodeID != "game-2" {
+		t.Fatalf("expected game-2, got %s", nodeID)
+	}
+
+	// game-2 leaves, the entity is created on game-1
+	c.leave()
+
+	if nodeID := c.where(t, ""); nodeID != "game-1" {
+		t.Fatalf("expected game-1, got %s", nodeID)
+	}
+
+	// game-2 joins again, the entity moves back without a new message
+	c.join()
+	c.waitHandoff(t)
+
+	if nodeID := c.where(t, ""); nodeID != "game-2" {
+		t.Fatalf("expected game-2, got %s", nodeID)
+	}
+}
+
+// TestSharding_HandoffMessages verifies that the messages queued by a handed
+// off entity, and the messages sent to its old path, reach the new owner.
+func TestSharding_HandoffMessages(t *testing.T) {
+	c, stop := startSharding(t)
+	defer stop()
+
+	c.leave()
+
+	oldPath := "game-1.player." + c.entityID
+	if nodeID := c.where(t, oldPath); nodeID != "game-1" {
+		t.Fatalf("expected game-1, got %s", nodeID)
+	}
+
+	// keep the entity busy so the next messages are still queued when it moves
+	system := c.apps[0].ActorSystem()
+	system.Call("center-1.test", oldPath, "sleep", &cproto.I32{})
+
+	const sent = 10
+	for i := 0; i < sent/2; i++ {
+		system.Call("center-1.test", oldPath, "add", &cproto.I32{})
+	}
+
+	c.join()
+
+	for i := sent / 2; i < sent; i++ {
+		system.Call("center-1.test", oldPath, "add", &cproto.I32{})
+	}
+
+	c.waitHandoff(t)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for c.log.count("game-1", c.entityID, "add")+c.log.count("game-2", c.entityID, "add") < sent {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d messages to arrive. events = %v", sent, c.log.events)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// a caller waiting on the old path gets the reply of the new owner
+	if nodeID := c.where(t, oldPath); nodeID != "game-2" {
+		t.Fatalf("expected game-2, got %s", nodeID)
+	}
+}
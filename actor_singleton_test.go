@@ -0,0 +1,282 @@
+package cherry
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cfacade "github.com/cherry-game/cherry/facade"
+	cactor "github.com/cherry-game/cherry/net/actor"
+	ccluster "github.com/cherry-game/cherry/net/cluster"
+	cproto "github.com/cherry-game/cherry/net/proto"
+	csingleton "github.com/cherry-game/cherry/net/singleton"
+)
+
+type bossActor struct {
+	cactor.Base
+	added *atomic.Int32
+}
+
+func (p *bossActor) OnInit() {
+	p.Remote().Register("where", p.where)
+	p.Remote().Register("add", p.add)
+	p.Remote().Register("sleep", p.sleep)
+}
+
+func (p *bossActor) where(_ *cproto.I32) (*cproto.NodeID, int32) {
+	return &cproto.NodeID{Value: p.App().NodeID()}, ccode.OK
+}
+
+func (p *bossActor) add(_ *cproto.I32) int32 {
+	p.added.Add(1)
+	return ccode.OK
+}
+
+func (p *bossActor) sleep(_ *cproto.I32) int32 {
+	time.Sleep(200 * time.Millisecond)
+	return ccode.OK
+}
+
+// singletonCluster runs center-1, game-1 and game-2 with a "boss" singleton
+// hosted by the game nodes.
+type singletonCluster struct {
+	apps       []*AppBuilder
+	singletons []*csingleton.Singleton
+	added      atomic.Int32 // "add" calls received by every instance of the boss
+	host       string       // host while every member is known
+	standby    string       // the other game node
+}
+
+func startSingleton(t *testing.T) (*singletonCluster, func()) {
+	path := filepath.Join(t.TempDir(), "memory.json")
+	if err := os.WriteFile(path, []byte(testClusterProfile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &singletonCluster{}
+	for _, nodeID := range []string{"center-1", "game-1", "game-2"} {
+		app := Configure(path, nodeID, false, Cluster)
+		app.SetCluster(ccluster.NewMemory())
+
+		component := csingleton.New()
+		c.singletons = append(c.singletons, component.Register("boss", "game", func() cfacade.IActorHandler {
+			return &bossActor{added: &c.added}
+		}))
+		app.Register(component)
+
+		c.apps = append(c.apps, app)
+	}
+
+	stop := startApps(t, c.apps...)
+	c.waitElected(t)
+
+	c.host, _ = c.singletons[0].Host()
+	c.standby = "game-1"
+	if c.host == c.standby {
+		c.standby = "game-2"
+	}
+
+	return c, stop
+}
+
+// waitElected waits until every node knows both game nodes serving and
+// elected the same host.
+func (c *singletonCluster) waitElected(t *testing.T) {
+	deadline := time.Now().Add(3 * time.Second)
+	for !c.elected() {
+		if time.Now().After(deadline) {
+			t.Fatal("singleton host not elected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (c *singletonCluster) elected() bool {
+	for _, app := range c.apps {
+		for _, nodeID := range []string{"game-1", "game-2"} {
+			member, found := app.Discovery().GetMember(nodeID)
+			if !found || member.GetStatus() != cfacade.MemberServing {
+				return false
+			}
+		}
+	}
+
+	host, found := c.singletons[0].Host()
+	for _, singleton := range c.singletons[1:] {
+		if nodeID, _ := singleton.Host(); !found || nodeID != host {
+			return false
+		}
+	}
+	return true
+}
+
+// where returns the node the boss answers from.
+func (c *singletonCluster) where(t *testing.T) string {
+	reply := &cproto.NodeID{}
+	if code := c.apps[0].ActorSystem().CallWait("center-1.test", csingleton.Path("boss"), "where", &cproto.I32{}, reply); code != ccode.OK {
+		t.Fatalf("call singleton fail. code = %d", code)
+	}
+	return reply.Value
+}
+
+type singletonMembers interface {
+	AddMember(member cfacade.IMember)
+	RemoveMember(nodeID string)
+}
+
+// leave removes the host from the discovery of the other nodes.
+func (c *singletonCluster) leave() {
+	for _, app := range c.apps {
+		if app.NodeID() != c.host {
+			app.Discovery().(singletonMembers).RemoveMember(c.host)
+		}
+	}
+}
+
+// join adds the host to the discovery of the other nodes again.
+func (c *singletonCluster) join() {
+	member, _ := findApp(c.apps, c.host).Discovery().GetMember(c.host)
+	for _, app := range c.apps {
+		if app.NodeID() != c.host {
+			app.Discovery().(singletonMembers).AddMember(member)
+		}
+	}
+}
+
+// wait waits until the boss on nodeID, nil if it does not run there, satisfies ok.
+func (c *singletonCluster) wait(t *testing.T, nodeID string, ok func(boss cfacade.IActor) bool) cfacade.IActor {
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		boss, found := findApp(c.apps, nodeID).ActorSystem().GetIActor("boss")
+		if !found {
+			boss = nil
+		}
+		if ok(boss) {
+			return boss
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("singleton on %s not in the expected state", nodeID)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// waitCreated waits until nodeID runs a boss other than old.
+func (c *singletonCluster) waitCreated(t *testing.T, nodeID string, old cfacade.IActor) cfacade.IActor {
+	return c.wait(t, nodeID, func(boss cfacade.IActor) bool {
+		return boss != nil && boss != old
+	})
+}
+
+// waitQueued waits until the boss on nodeID has queued remote messages.
+func (c *singletonCluster) waitQueued(t *testing.T, nodeID string, queued int32) {
+	c.wait(t, nodeID, func(boss cfacade.IActor) bool {
+		if boss == nil {
+			return false
+		}
+		_, remote, _ := boss.(*cactor.Actor).QueueDepth()
+		return remote == queued
+	})
+}
+
+// waitStopped waits until nodeID runs no boss.
+func (c *singletonCluster) waitStopped(t *testing.T, nodeID string) {
+	c.wait(t, nodeID, func(boss cfacade.IActor) bool {
+		return boss == nil
+	})
+}
+
+// TestSingleton_Failover verifies that a singleton runs on one host chosen
+// alike by every node, is reached through its logical path, and moves to
+// another member when its host leaves discovery.
+func TestSingleton_Failover(t *testing.T) {
+	c, stop := startSingleton(t)
+	defer stop()
+
+	if nodeID := c.where(t); nodeID != c.host {
+		t.Fatalf("expected %s, got %s", c.host, nodeID)
+	}
+
+	// the host leaves, the standby creates the singleton
+	c.leave()
+
+	if nodeID := c.where(t); nodeID != c.standby {
+		t.Fatalf("expected %s, got %s", c.standby, nodeID)
+	}
+
+	// the host joins again, the standby stops its singleton
+	c.join()
+	c.waitStopped(t, c.standby)
+
+	if nodeID := c.where(t); nodeID != c.host {
+		t.Fatalf("expected %s, got %s", c.host, nodeID)
+	}
+}
+
+// TestSingleton_HandoffMessages verifies that the messages queued by the old
+// host reach the singleton on the new host.
+func TestSingleton_HandoffMessages(t *testing.T) {
+	c, stop := startSingleton(t)
+	defer stop()
+
+	c.leave()
+	c.waitCreated(t, c.standby, nil)
+
+	// keep the boss busy so the next messages are still queued when it moves
+	system := c.apps[0].ActorSystem()
+	system.Call("center-1.test", csingleton.Path("boss"), "sleep", &cproto.I32{})
+
+	const sent = 20
+	for i := 0; i < sent; i++ {
+		system.Call("center-1.test", csingleton.Path("boss"), "add", &cproto.I32{})
+	}
+	c.waitQueued(t, c.standby, sent)
+
+	c.join()
+	c.waitStopped(t, c.standby)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for c.added.Load() < sent {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d messages to arrive, got %d", sent, c.added.Load())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestSingleton_ElectedBack verifies that a host elected again before its old
+// instance has stopped creates the singleton once that instance has exited.
+func TestSingleton_ElectedBack(t *testing.T) {
+	c, stop := startSingleton(t)
+	defer stop()
+
+	c.leave()
+	old := c.waitCreated(t, c.standby, nil)
+
+	// the standby is still busy stopping the boss when it is elected back
+	system := c.apps[0].ActorSystem()
+	system.Call("center-1.test", csingleton.Path("boss"), "sleep", &cproto.I32{})
+	system.Call("center-1.test", csingleton.Path("boss"), "add", &cproto.I32{})
+	c.waitQueued(t, c.standby, 1)
+
+	c.join()
+	c.leave()
+
+	c.waitCreated(t, c.standby, old)
+
+	if nodeID := c.where(t); nodeID != c.standby {
+		t.Fatalf("expected %s, got %s", c.standby, nodeID)
+	}
+}
+
+func findApp(apps []*AppBuilder, nodeID string) *AppBuilder {
+	for _, app := range apps {
+		if app.NodeID() == nodeID {
+			return app
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package cherry
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cfacade "github.com/cherry-game/cherry/facade"
+	cactor "github.com/cherry-game/cherry/net/actor"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+// counterActor keeps a counter in memory and panics on demand.
+type counterActor struct {
+	cactor.Base
+	inits *atomic.Int32
+	value int32
+}
+
+func (*counterActor) AliasID() string {
+	return "counter"
+}
+
+func (p *counterActor) Supervisor() *cactor.Supervisor {
+	return cactor.NewSupervisor(cactor.Restart, 2, time.Minute)
+}
+
+func (p *counterActor) NewHandler() cfacade.IActorHandler {
+	return &counterActor{inits: p.inits}
+}
+
+func (p *counterActor) OnInit() {
+	p.inits.Add(1)
+	p.Remote().Register("incr", p.incr)
+	p.Remote().Register("crash", p.crash)
+}
+
+func (p *counterActor) incr(_ *cproto.I32) (*cproto.I32, int32) {
+	p.value++
+	return &cproto.I32{Value: p.value}, ccode.OK
+}
+
+func (p *counterActor) crash(_ *cproto.I32) (*cproto.I32, int32) {
+	panic("crash")
+}
+
+// parentActor stops failed children and records their failures.
+type parentActor struct {
+	cactor.Base
+	failed chan cactor.Directive
+}
+
+func (*parentActor) AliasID() string {
+	return "parent"
+}
+
+func (p *parentActor) Supervisor() *cactor.Supervisor {
+	return cactor.NewSupervisor(cactor.Stop, 0, 0)
+}
+
+func (p *parentActor) OnInit() {
+	p.Child().Create("child", &childActor{})
+}
+
+func (p *parentActor) OnChildFailed(childID string, _ any, directive cactor.Directive) {
+	if childID == "child" {
+		p.failed <- directive
+	}
+}
+
+type childActor struct {
+	cactor.Base
+}
+
+func (p *childActor) OnInit() {
+	p.Remote().Register("crash", p.crash)
+}
+
+func (p *childActor) crash(_ *cproto.I32) (*cproto.I32, int32) {
+	panic("crash")
+}
+
+// TestActorSupervisor_Restart verifies that a failed actor is restarted on a
+// fresh handler until its restart limit is exceeded.
+func TestActorSupervisor_Restart(t *testing.T) {
+	app := Configure(writeTestProfile(t, "supervisor"), "game-1", false, Standalone)
+
+	inits := &atomic.Int32{}
+	app.AddActors(&counterActor{inits: inits})
+	go app.Startup()
+	defer app.Shutdown()
+
+	waitRunning(t, app)
+
+	system := app.ActorSystem()
+	call := func(funcName string) (int32, int32) {
+		reply := &cproto.I32{}
+		code := system.CallWait(".test", ".counter", funcName, &cproto.I32{}, reply)
+		return reply.Value, code
+	}
+
+	for i := 0; i < 2; i++ {
+		if value, code := call("incr"); ccode.IsFail(code) || value != 1 {
+			t.Fatalf("expected 1, got %d. code = %d", value, code)
+		}
+
+		if _, code := call("crash"); ccode.IsOK(code) {
+			t.Fatal("expected crash to fail")
+		}
+	}
+
+	if n := inits.Load(); n != 3 {
+		t.Fatalf("expected 3 inits, got %d", n)
+	}
+
+	// third failure exceeds the restart limit
+	call("crash")
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		if _, found := system.GetIActor("counter"); !found {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected actor to stop")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestActorSupervisor_ChildFailed verifies that a child inherits the
+// supervisor of its parent and that the parent is notified of the failure.
+func TestActorSupervisor_ChildFailed(t *testing.T) {
+	app := Configure(writeTestProfile(t, "supervisor"), "game-1", false, Standalone)
+
+	parent := &parentActor{failed: make(chan cactor.Directive, 1)}
+	app.AddActors(parent)
+	go app.Startup()
+	defer app.Shutdown()
+
+	waitRunning(t, app)
+
+	app.ActorSystem().Call(".test", ".parent.child", "crash", &cproto.I32{})
+
+	select {
+	case directive := <-parent.failed:
+		if directive != cactor.Stop {
+			t.Fatalf("expected stop, got %s", directive)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("parent not notified")
+	}
+}
@@ -0,0 +1,125 @@
+package cherry
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cfacade "github.com/cherry-game/cherry/facade"
+	cactor "github.com/cherry-game/cherry/net/actor"
+	cproto "github.com/cherry-game/cherry/net/proto"
+	ctrace "github.com/cherry-game/cherry/net/trace"
+)
+
+// memoryExporter keeps the exported spans in memory.
+type memoryExporter struct {
+	mu    sync.Mutex
+	spans map[string]*ctrace.Span // key:span name
+}
+
+func (p *memoryExporter) Export(span *ctrace.Span) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.spans[span.Name] = span
+}
+
+func (p *memoryExporter) Close() error {
+	return nil
+}
+
+func (p *memoryExporter) get(name string) *ctrace.Span {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.spans[name]
+}
+
+type frontActor struct {
+	cactor.Base
+}
+
+func (*frontActor) AliasID() string {
+	return "front"
+}
+
+func (p *frontActor) OnInit() {
+	p.Remote().Register("relay", p.relay)
+}
+
+func (p *frontActor) relay(req *cproto.I32) (*cproto.I32, int32) {
+	reply := &cproto.I32{}
+	code := p.CallWait(".back", "echo", req, reply)
+	return reply, code
+}
+
+type backActor struct {
+	cactor.Base
+}
+
+func (*backActor) AliasID() string {
+	return "back"
+}
+
+func (p *backActor) OnInit() {
+	p.Remote().Register("echo", func(req *cproto.I32) (*cproto.I32, int32) {
+		return req, ccode.OK
+	})
+}
+
+// TestTrace_ActorSpans verifies that actor invocations are recorded as spans
+// and that nested calls are linked to the span of the calling invocation.
+func TestTrace_ActorSpans(t *testing.T) {
+	exporter := &memoryExporter{spans: map[string]*ctrace.Span{}}
+
+	app := Configure(writeTestProfile(t, "trace"), "game-1", false, Standalone)
+	app.Register(ctrace.New(exporter))
+	app.AddActors(&frontActor{}, &backActor{})
+	go app.Startup()
+	defer app.Shutdown()
+
+	waitRunning(t, app)
+
+	root := ctrace.Find(app).Start("", "", "test")
+
+	m := cfacade.GetMessage()
+	m.Source = ".test"
+	m.Target = ".front"
+	m.FuncName = "relay"
+	m.Args = &cproto.I32{Value: 7}
+	m.TraceID = root.TraceID
+	m.SpanID = root.SpanID
+	m.ChanResult = make(chan interface{}, 1)
+	chanResult := m.ChanResult
+
+	if !app.ActorSystem().PostRemote(m) {
+		t.Fatal("post fail")
+	}
+
+	select {
+	case <-chanResult:
+	case <-time.After(3 * time.Second):
+		t.Fatal("relay timeout")
+	}
+
+	var relay, echo *ctrace.Span
+	deadline := time.Now().Add(3 * time.Second)
+	for relay == nil || echo == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("spans not exported")
+		}
+		time.Sleep(10 * time.Millisecond)
+		relay, echo = exporter.get("remote:relay"), exporter.get("remote:echo")
+	}
+
+	if relay.TraceID != root.TraceID || relay.ParentID != root.SpanID {
+		t.Fatalf("relay not linked to root. %+v", relay)
+	}
+
+	if echo.TraceID != root.TraceID || echo.ParentID != relay.SpanID {
+		t.Fatalf("echo not linked to relay. %+v", echo)
+	}
+
+	if echo.NodeID != "game-1" || echo.Attrs["actor"] != "game-1.back" {
+		t.Fatalf("unexpected echo span %+v", echo)
+	}
+}
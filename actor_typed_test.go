@@ -0,0 +1,90 @@
+package cherry
+
+import (
+	"testing"
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cerror "github.com/cherry-game/cherry/error"
+	cfacade "github.com/cherry-game/cherry/facade"
+	cactor "github.com/cherry-game/cherry/net/actor"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+// typedActor registers its handlers without reflection.
+type typedActor struct {
+	cactor.Base
+	logins chan int64
+}
+
+func (*typedActor) AliasID() string {
+	return "typed"
+}
+
+func (p *typedActor) OnInit() {
+	cactor.RegisterLocal(p.Local(), "login", p.login)
+	cactor.RegisterRemote(p.Remote(), "double", p.double)
+	cactor.RegisterRemoteError(p.Remote(), "half", p.half)
+}
+
+func (p *typedActor) login(session *cproto.Session, req *cproto.I32) {
+	p.logins <- session.Uid + int64(req.Value)
+}
+
+func (p *typedActor) double(req *cproto.I32) (*cproto.I32, int32) {
+	return &cproto.I32{Value: req.Value * 2}, ccode.OK
+}
+
+func (p *typedActor) half(req *cproto.I32) (*cproto.I32, error) {
+	if req.Value%2 != 0 {
+		return nil, cerror.NewCode(ccode.RPCRemoteExecuteError, "odd value").WithDetail("value", "odd")
+	}
+	return &cproto.I32{Value: req.Value / 2}, nil
+}
+
+// TestTypedHandlers verifies that handlers registered with the generic
+// helpers receive local and remote messages.
+func TestTypedHandlers(t *testing.T) {
+	app := Configure(writeTestProfile(t, "typed"), "game-1", false, Standalone)
+
+	actor := &typedActor{logins: make(chan int64, 1)}
+	app.AddActors(actor)
+	go app.Startup()
+	defer app.Shutdown()
+
+	waitRunning(t, app)
+
+	reply := &cproto.I32{}
+	code := app.ActorSystem().CallWait(".test", ".typed", "double", &cproto.I32{Value: 21}, reply)
+	if ccode.IsFail(code) || reply.Value != 42 {
+		t.Fatalf("expected 42, got %d. code = %d", reply.Value, code)
+	}
+
+	if err := app.ActorSystem().CallWaitError(".test", ".typed", "half", &cproto.I32{Value: 42}, reply); err != nil || reply.Value != 21 {
+		t.Fatalf("expected 21, got %d. err = %v", reply.Value, err)
+	}
+
+	err := app.ActorSystem().CallWaitError(".test", ".typed", "half", &cproto.I32{Value: 3}, reply)
+	if codeErr := cerror.AsCode(err); codeErr.Code != ccode.RPCRemoteExecuteError || codeErr.Message != "odd value" || codeErr.Details["value"] != "odd" {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	args, _ := app.Serializer().Marshal(&cproto.I32{Value: 1})
+
+	m := cfacade.GetMessage()
+	m.Source = ".test"
+	m.Target = ".typed"
+	m.FuncName = "login"
+	m.Session = &cproto.Session{Uid: 100}
+	m.Args = args
+	app.ActorSystem().PostLocal(m)
+
+	select {
+	case value := <-actor.logins:
+		if value != 101 {
+			t.Fatalf("expected 101, got %d", value)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("local handler not called")
+	}
+}
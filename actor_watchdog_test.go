@@ -0,0 +1,80 @@
+package cherry
+
+import (
+	"testing"
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cfacade "github.com/cherry-game/cherry/facade"
+	cactor "github.com/cherry-game/cherry/net/actor"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+// blockingActor blocks in "block" until release is closed.
+type blockingActor struct {
+	cactor.Base
+	release    chan struct{}
+	generation int32
+}
+
+func (*blockingActor) AliasID() string {
+	return "blocking"
+}
+
+func (p *blockingActor) OnInit() {
+	p.Remote().Register("block", p.block)
+	p.Remote().Register("generation", p.getGeneration)
+}
+
+func (p *blockingActor) NewHandler() cfacade.IActorHandler {
+	return &blockingActor{release: p.release, generation: p.generation + 1}
+}
+
+func (p *blockingActor) block() {
+	<-p.release
+}
+
+func (p *blockingActor) getGeneration(_ *cproto.I32) (*cproto.I32, int32) {
+	return &cproto.I32{Value: p.generation}, ccode.OK
+}
+
+// TestWatchdog_Restart verifies that the watchdog replaces an actor whose
+// handler is stuck, and that the stuck goroutine does not block shutdown.
+func TestWatchdog_Restart(t *testing.T) {
+	app := Configure(writeTestProfile(t, "watchdog"), "game-1", false, Standalone)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	app.AddActors(&blockingActor{release: release, generation: 1})
+	app.ActorSystem().(*cactor.Component).SetWatchdog(100*time.Millisecond, cactor.WatchdogRestart)
+
+	go app.Startup()
+	defer app.Shutdown()
+
+	waitRunning(t, app)
+
+	system := app.ActorSystem().(*cactor.Component)
+	stuck, _ := system.GetActor("blocking")
+
+	if code := system.Call(".test", ".blocking", "block", nil); ccode.IsFail(code) {
+		t.Fatalf("call fail. code = %d", code)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		if current, found := system.GetActor("blocking"); found && current != stuck {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the stuck actor to be replaced")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	reply := &cproto.I32{}
+	code := system.CallWait(".test", ".blocking", "generation", &cproto.I32{}, reply)
+	if ccode.IsFail(code) || reply.Value != 2 {
+		t.Fatalf("expected restarted actor. generation = %d, code = %d", reply.Value, code)
+	}
+}
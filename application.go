@@ -1,12 +1,15 @@
 package cherry
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"sync/atomic"
 	"syscall"
+	"time"
 
 	cconst "github.com/cherry-game/cherry/const"
+	cerror "github.com/cherry-game/cherry/error"
 	ctime "github.com/cherry-game/cherry/extend/time"
 	cutils "github.com/cherry-game/cherry/extend/utils"
 	cfacade "github.com/cherry-game/cherry/facade"
@@ -38,26 +41,45 @@ type (
 		cluster      cfacade.IClusterComponent   // cluster component
 		actorSystem  *cactor.Component           // actor system
 		netParser    cfacade.INetParser          // net packet parser
+		profile      cfacade.IProfile            // profile config
+		logManager   *clog.Manager               // logger registry of this application
+		logger       *clog.CherryLogger          // node logger of this application
 	}
 )
 
-// NewApp create new application instance
+// NewApp create new application instance.
+// Every call loads its own profile, so several applications can run in one
+// process. The first loaded profile also becomes the cprofile package default.
 func NewApp(profileFilePath, nodeID string, isFrontend bool, mode NodeMode) *Application {
-	node, err := cprofile.Init(profileFilePath, nodeID)
+	profile, node, err := cprofile.Load(profileFilePath, nodeID)
 	if err != nil {
 		panic(err)
 	}
 
-	return NewAppNode(node, isFrontend, mode)
+	if !cprofile.Default().Loaded() {
+		cprofile.SetDefault(profile)
+	}
+
+	return NewAppProfile(profile, node, isFrontend, mode)
 }
 
+// NewAppNode create new application instance with the cprofile package default profile.
 func NewAppNode(node cfacade.INode, isFrontend bool, mode NodeMode) *Application {
-	// set logger
-	clog.SetPrintLevel(clog.GetLevel(cprofile.PrintLevel()))
-	clog.SetNodeLogger(node)
+	return NewAppProfile(cprofile.Default(), node, isFrontend, mode)
+}
 
-	// print version info
-	clog.Info(cconst.GetLOGO())
+// NewAppProfile create new application instance with the given profile.
+func NewAppProfile(profile cfacade.IProfile, node cfacade.INode, isFrontend bool, mode NodeMode) *Application {
+	var logManager *clog.Manager
+	if profile == cfacade.IProfile(cprofile.Default()) {
+		// the default profile drives the package-level logger, share its writers
+		clog.SetPrintLevel(clog.GetLevel(profile.PrintLevel()))
+		clog.SetNodeLogger(node)
+		logManager = clog.DefaultManager()
+	} else {
+		logManager = clog.NewManager(clog.WithLoggerConfig(profile.GetConfig("logger")))
+		logManager.SetPrintLevel(clog.GetLevel(profile.PrintLevel()))
+	}
 
 	app := &Application{
 		INode:       node,
@@ -68,8 +90,14 @@ func NewAppNode(node cfacade.INode, isFrontend bool, mode NodeMode) *Application
 		running:     0,
 		dieChan:     make(chan bool),
 		actorSystem: cactor.New(),
+		profile:     profile,
+		logManager:  logManager,
+		logger:      logManager.NodeLogger(node),
 	}
 
+	// print version info
+	app.Logger().Info(cconst.GetLOGO())
+
 	return app
 }
 
@@ -82,7 +110,7 @@ func (a *Application) NodeMode() NodeMode {
 }
 
 func (a *Application) Running() bool {
-	return a.running > 0
+	return atomic.LoadInt32(&a.running) > 0
 }
 
 func (a *Application) DieChan() chan bool {
@@ -96,13 +124,13 @@ func (a *Application) Register(components ...cfacade.IComponent) {
 
 	for _, c := range components {
 		if c == nil || c.Name() == "" {
-			clog.Errorf("[component = %T] name is nil", c)
+			a.Logger().Errorf("[component = %T] name is nil", c)
 			return
 		}
 
 		result := a.Find(c.Name())
 		if result != nil {
-			clog.Errorf("[component name = %s] is duplicate.", c.Name())
+			a.Logger().Errorf("[component name = %s] is duplicate.", c.Name())
 			return
 		}
 
@@ -152,17 +180,17 @@ func (a *Application) OnShutdown(fn ...func()) {
 func (a *Application) Startup() {
 	defer func() {
 		if r := recover(); r != nil {
-			clog.Error(r)
+			a.Logger().Error(r)
 		}
 	}()
 
 	if a.Running() {
-		clog.Error("Application has running.")
+		a.Logger().Error("Application has running.")
 		return
 	}
 
 	defer func() {
-		clog.Flush()
+		a.flush()
 	}()
 
 	// add connector component
@@ -172,102 +200,192 @@ func (a *Application) Startup() {
 		}
 	}
 
-	clog.Info("-------------------------------------------------")
-	clog.Infof("[nodeID      = %s] application is starting...", a.NodeID())
-	clog.Infof("[nodeType    = %s]", a.NodeType())
-	clog.Infof("[pid         = %d]", os.Getpid())
-	clog.Infof("[startTime   = %s]", a.StartTime())
-	clog.Infof("[profilePath = %s]", cprofile.Path())
-	clog.Infof("[profileName = %s]", cprofile.Name())
-	clog.Infof("[env         = %s]", cprofile.Env())
-	clog.Infof("[debug       = %v]", cprofile.Debug())
-	clog.Infof("[printLevel  = %s]", cprofile.PrintLevel())
-	clog.Infof("[logLevel    = %s]", clog.DefaultLogger.LogLevel)
-	clog.Infof("[stackLevel  = %s]", clog.DefaultLogger.StackLevel)
-	clog.Infof("[writeFile   = %v]", clog.DefaultLogger.EnableWriteFile)
-	clog.Infof("[serializer  = %s]", a.serializer.Name())
-	clog.Info("-------------------------------------------------")
+	a.Logger().Info("-------------------------------------------------")
+	a.Logger().Infof("[nodeID      = %s] application is starting...", a.NodeID())
+	a.Logger().Infof("[nodeType    = %s]", a.NodeType())
+	a.Logger().Infof("[pid         = %d]", os.Getpid())
+	a.Logger().Infof("[startTime   = %s]", a.StartTime())
+	a.Logger().Infof("[profilePath = %s]", a.profile.Path())
+	a.Logger().Infof("[profileName = %s]", a.profile.Name())
+	a.Logger().Infof("[env         = %s]", a.profile.Env())
+	a.Logger().Infof("[debug       = %v]", a.profile.Debug())
+	a.Logger().Infof("[printLevel  = %s]", a.profile.PrintLevel())
+	a.Logger().Infof("[logLevel    = %s]", a.logger.LogLevel)
+	a.Logger().Infof("[stackLevel  = %s]", a.logger.StackLevel)
+	a.Logger().Infof("[writeFile   = %v]", a.logger.EnableWriteFile)
+	a.Logger().Infof("[serializer  = %s]", a.serializer.Name())
+	a.Logger().Info("-------------------------------------------------")
+
+	if err := a.initComponents(); err != nil {
+		a.Logger().Errorf("Application startup fail. %v", err)
+		a.flush()
+		os.Exit(1)
+	}
+
+	a.Logger().Info("-------------------------------------------------")
+	a.Logger().Infof("[spend time = %dms] application is running.", a.startTime.NowDiffMillisecond())
+	a.Logger().Info("-------------------------------------------------")
+
+	// set application is running
+	atomic.AddInt32(&a.running, 1)
+	a.setStatus(cfacade.MemberServing)
+
+	sg := make(chan os.Signal, 1)
+	signal.Notify(sg, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
+
+	select {
+	case <-a.dieChan:
+		a.Logger().Info("invoke shutdown().")
+	case s := <-sg:
+		a.Logger().Infof("receive shutdown signal = %v.", s)
+	}
+
+	// stop status
+	atomic.StoreInt32(&a.running, 0)
+	a.setStatus(cfacade.MemberLeaving)
+
+	a.Logger().Info("------- application will shutdown -------")
+
+	a.drain(a.profile.ShutdownTimeout())
+
+	if a.onShutdownFn != nil {
+		for _, f := range a.onShutdownFn {
+			cutils.Try(func() {
+				f()
+			}, func(errString string) {
+				a.Logger().Warnf("[onShutdownFn] error = %s", errString)
+			})
+		}
+	}
+
+	// all components in reverse dependency order
+	stopComponents(a.Logger(), a.components)
+
+	a.Logger().Info("------- application has been shutdown... -------")
+}
+
+// initComponents sorts the registered components, runs Init and OnAfterInit on
+// each of them and loads the net packet parser. If any step fails, the
+// components whose Init already succeeded are stopped in reverse order and the
+// error is returned.
+func (a *Application) initComponents() error {
+	// sort components by dependencies
+	sorted, err := sortComponents(a.components)
+	if err != nil {
+		return err
+	}
+	a.components = sorted
 
 	// component list
 	for _, c := range a.components {
 		c.Set(a)
-		clog.Infof("[component = %s] is added.", c.Name())
+		if deps := dependencies(c); len(deps) > 0 {
+			a.Logger().Infof("[component = %s] is added. depends on %v", c.Name(), deps)
+		} else {
+			a.Logger().Infof("[component = %s] is added.", c.Name())
+		}
 	}
-	clog.Info("-------------------------------------------------")
+	a.Logger().Info("-------------------------------------------------")
 
 	// execute Init()
+	var initialized []cfacade.IComponent
 	for _, c := range a.components {
-		clog.Infof("[component = %s] -> OnInit().", c.Name())
-		c.Init()
+		a.Logger().Infof("[component = %s] -> OnInit().", c.Name())
+		if err = initComponent(c); err != nil {
+			a.rollback(initialized)
+			return err
+		}
+		initialized = append(initialized, c)
 	}
-	clog.Info("-------------------------------------------------")
+	a.Logger().Info("-------------------------------------------------")
 
 	// execute OnAfterInit()
 	for _, c := range a.components {
-		clog.Infof("[component = %s] -> OnAfterInit().", c.Name())
-		c.OnAfterInit()
+		a.Logger().Infof("[component = %s] -> OnAfterInit().", c.Name())
+		if err = afterInitComponent(c); err != nil {
+			a.rollback(initialized)
+			return err
+		}
 	}
 
 	// load net packet parser
 	if a.isFrontend {
 		if a.netParser == nil {
-			clog.Panic("net packet parser is nil.")
+			a.rollback(initialized)
+			return cerror.Error("net packet parser is nil.")
 		}
 		a.netParser.Load(a)
 	}
 
-	clog.Info("-------------------------------------------------")
-	clog.Infof("[spend time = %dms] application is running.", a.startTime.NowDiffMillisecond())
-	clog.Info("-------------------------------------------------")
+	return nil
+}
 
-	// set application is running
-	atomic.AddInt32(&a.running, 1)
+// rollback stops the components that were initialized before a startup failure.
+func (a *Application) rollback(initialized []cfacade.IComponent) {
+	if len(initialized) < 1 {
+		return
+	}
 
-	sg := make(chan os.Signal, 1)
-	signal.Notify(sg, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
+	a.Logger().Info("------- application startup rollback -------")
+	stopComponents(a.Logger(), initialized)
+}
 
-	select {
-	case <-a.dieChan:
-		clog.Info("invoke shutdown().")
-	case s := <-sg:
-		clog.Infof("receive shutdown signal = %v.", s)
+// setStatus sets the discovery status of this node, a no-op in standalone mode.
+func (a *Application) setStatus(status int32) {
+	if a.discovery != nil {
+		a.discovery.SetStatus(status)
 	}
+}
 
-	// stop status
-	atomic.StoreInt32(&a.running, 0)
+// drain runs the OnDrain hooks within the shutdown deadline: connectors stop
+// accepting first, then the net parser closes its idle clients right away and
+// the busy ones once they are idle or the deadline is reached, then the other
+// components (actor system included) finish their in-flight work in reverse
+// order. A timeout of 0 skips the drain phase.
+func (a *Application) drain(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
 
-	clog.Info("------- application will shutdown -------")
+	a.Logger().Infof("------- application drain. [timeout = %s] -------", timeout)
 
-	if a.onShutdownFn != nil {
-		for _, f := range a.onShutdownFn {
-			cutils.Try(func() {
-				f()
-			}, func(errString string) {
-				clog.Warnf("[onShutdownFn] error = %s", errString)
-			})
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var drains []cfacade.IComponentDrain
+	for i := len(a.components) - 1; i >= 0; i-- {
+		if _, ok := a.components[i].(cfacade.IConnector); !ok {
+			continue
+		}
+		if d, ok := a.components[i].(cfacade.IComponentDrain); ok {
+			drains = append(drains, d)
 		}
 	}
 
-	//all components in reverse order
-	for i := len(a.components) - 1; i >= 0; i-- {
-		cutils.Try(func() {
-			clog.Infof("[component = %s] -> OnBeforeStop().", a.components[i].Name())
-			a.components[i].OnBeforeStop()
-		}, func(errString string) {
-			clog.Warnf("[component = %s] -> OnBeforeStop(). error = %s", a.components[i].Name(), errString)
-		})
+	if d, ok := a.netParser.(cfacade.IComponentDrain); ok {
+		drains = append(drains, d)
 	}
 
 	for i := len(a.components) - 1; i >= 0; i-- {
+		if _, ok := a.components[i].(cfacade.IConnector); ok {
+			continue
+		}
+		if d, ok := a.components[i].(cfacade.IComponentDrain); ok {
+			drains = append(drains, d)
+		}
+	}
+
+	for _, d := range drains {
 		cutils.Try(func() {
-			clog.Infof("[component = %s] -> OnStop().", a.components[i].Name())
-			a.components[i].OnStop()
+			d.OnDrain(ctx)
 		}, func(errString string) {
-			clog.Warnf("[component = %s] -> OnStop(). error = %s", a.components[i].Name(), errString)
+			a.Logger().Warnf("[%T] -> OnDrain(). error = %s", d, errString)
 		})
 	}
 
-	clog.Info("------- application has been shutdown... -------")
+	if ctx.Err() != nil {
+		a.Logger().Warnf("------- application drain deadline exceeded. [timeout = %s] -------", timeout)
+	}
 }
 
 func (a *Application) Shutdown() {
@@ -290,8 +408,29 @@ func (a *Application) ActorSystem() cfacade.IActorSystem {
 	return a.actorSystem
 }
 
-func (p *AppBuilder) NetParser() cfacade.INetParser {
-	return p.netParser
+func (a *Application) Profile() cfacade.IProfile {
+	return a.profile
+}
+
+// Logger returns the node logger of this application, or the package default
+// logger if the application was not created by NewApp.
+func (a *Application) Logger() cfacade.ILogger {
+	if a.logger == nil {
+		return clog.DefaultLogger
+	}
+	return a.logger
+}
+
+// flush syncs the loggers of this application and the package default loggers.
+func (a *Application) flush() {
+	if a.logManager != nil && a.logManager != clog.DefaultManager() {
+		a.logManager.Sync()
+	}
+	clog.Flush()
+}
+
+func (a *Application) NetParser() cfacade.INetParser {
+	return a.netParser
 }
 
 func (a *Application) StartTime() string {
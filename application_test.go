@@ -0,0 +1,232 @@
+package cherry
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cactor "github.com/cherry-game/cherry/net/actor"
+	ccluster "github.com/cherry-game/cherry/net/cluster"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+const testProfile = `{
+  "env": "%s",
+  "print_level": "info",
+  "node": {
+    "gate": [{"node_id": "gate-1", "__settings__": {}}],
+    "center": [{"node_id": "center-1", "__settings__": {}}],
+    "game": [{"node_id": ["game-1", "game-2"], "__settings__": {}}]
+  }
+}`
+
+// echoActor replies with the env of the profile its application was loaded from.
+type echoActor struct {
+	cactor.Base
+}
+
+func (*echoActor) AliasID() string {
+	return "echo"
+}
+
+func (p *echoActor) OnInit() {
+	p.Remote().Register("env", p.env)
+}
+
+func (p *echoActor) env(req *cproto.NodeID) (*cproto.NodeID, int32) {
+	return &cproto.NodeID{Value: p.App().NodeID() + "@" + p.App().Profile().Env()}, ccode.OK
+}
+
+func writeTestProfile(t *testing.T, env string) string {
+	path := filepath.Join(t.TempDir(), env+".json")
+	content := []byte(fmt.Sprintf(testProfile, env))
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func waitRunning(t *testing.T, apps ...*AppBuilder) {
+	for _, app := range apps {
+		deadline := time.Now().Add(5 * time.Second)
+		for !app.Running() {
+			if time.Now().After(deadline) {
+				t.Fatalf("[nodeID = %s] startup timeout", app.NodeID())
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+// startApps starts apps and waits until they run. The returned function shuts
+// them down and waits until they stopped, so later tests can reuse the nodeIDs.
+func startApps(t *testing.T, apps ...*AppBuilder) func() {
+	var wg sync.WaitGroup
+	for _, app := range apps {
+		wg.Add(1)
+		go func(app *AppBuilder) {
+			defer wg.Done()
+			app.Startup()
+		}(app)
+	}
+
+	waitRunning(t, apps...)
+
+	return func() {
+		for _, app := range apps {
+			app.Shutdown()
+		}
+		wg.Wait()
+	}
+}
+
+// TestMultipleApplications verifies that several applications boot side by
+// side in one process, each with its own profile and actor system.
+func TestMultipleApplications(t *testing.T) {
+	gatePath := writeTestProfile(t, "gate")
+	clusterPath := writeTestProfile(t, "cluster")
+
+	apps := []*AppBuilder{
+		Configure(gatePath, "gate-1", false, Standalone),
+		Configure(clusterPath, "center-1", false, Standalone),
+		Configure(clusterPath, "game-1", false, Standalone),
+		Configure(clusterPath, "game-2", false, Standalone),
+	}
+
+	for _, app := range apps {
+		app.AddActors(&echoActor{})
+	}
+
+	stop := startApps(t, apps...)
+	defer stop()
+
+	expected := []string{"gate-1@gate", "center-1@cluster", "game-1@cluster", "game-2@cluster"}
+	for i, app := range apps {
+		reply := &cproto.NodeID{}
+		code := app.ActorSystem().CallWait(".test", ".echo", "env", &cproto.NodeID{}, reply)
+		if ccode.IsFail(code) {
+			t.Fatalf("[nodeID = %s] call fail. code = %d", app.NodeID(), code)
+		}
+
+		if reply.Value != expected[i] {
+			t.Fatalf("expected %s, got %s", expected[i], reply.Value)
+		}
+	}
+}
+
+const testClusterProfile = `{
+  "env": "memory",
+  "print_level": "info",
+  "cluster": {
+    "discovery": {"mode": "default"}
+  },
+  "node": {
+    "center": [{"node_id": "center-1", "__settings__": {}}],
+    "game": [
+      {"node_id": "game-1", "__settings__": {}},
+      {"node_id": "game-2", "__settings__": {}}
+    ]
+  }
+}`
+
+// TestMultipleApplications_MemoryCluster verifies that nodes started in one
+// process reach each other through the memory cluster transport.
+func TestMultipleApplications_MemoryCluster(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.json")
+	if err := os.WriteFile(path, []byte(testClusterProfile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var apps []*AppBuilder
+	for _, nodeID := range []string{"center-1", "game-1", "game-2"} {
+		app := Configure(path, nodeID, false, Cluster)
+		app.SetCluster(ccluster.NewMemory())
+		app.AddActors(&echoActor{})
+		apps = append(apps, app)
+		go app.Startup()
+	}
+
+	waitRunning(t, apps...)
+
+	center := apps[0]
+	for _, nodeID := range []string{"game-1", "game-2"} {
+		reply := &cproto.NodeID{}
+		code := center.ActorSystem().CallWait("center-1.test", nodeID+".echo", "env", &cproto.NodeID{}, reply)
+		if ccode.IsFail(code) {
+			t.Fatalf("[nodeID = %s] call fail. code = %d", nodeID, code)
+		}
+
+		if expected := nodeID + "@memory"; reply.Value != expected {
+			t.Fatalf("expected %s, got %s", expected, reply.Value)
+		}
+	}
+
+	for _, app := range apps {
+		app.Shutdown()
+	}
+}
+
+const testTCPProfile = `{
+  "env": "tcp",
+  "print_level": "info",
+  "cluster": {
+    "discovery": {"mode": "default"}
+  },
+  "node": {
+    "center": [{"node_id": "center-1", "rpc_address": "%s", "__settings__": {}}],
+    "game": [{"node_id": "game-1", "rpc_address": "%s", "__settings__": {}}]
+  }
+}`
+
+func freeAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+// TestMultipleApplications_TCPCluster verifies that nodes reach each other
+// over direct tcp links in both directions.
+func TestMultipleApplications_TCPCluster(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tcp.json")
+	content := fmt.Sprintf(testTCPProfile, freeAddress(t), freeAddress(t))
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var apps []*AppBuilder
+	for _, nodeID := range []string{"center-1", "game-1"} {
+		app := Configure(path, nodeID, false, Cluster)
+		app.SetCluster(ccluster.NewTCP())
+		app.AddActors(&echoActor{})
+		apps = append(apps, app)
+		go app.Startup()
+	}
+
+	waitRunning(t, apps...)
+
+	for i, app := range apps {
+		target := apps[1-i].NodeID()
+
+		reply := &cproto.NodeID{}
+		code := app.ActorSystem().CallWait(app.NodeID()+".test", target+".echo", "env", &cproto.NodeID{}, reply)
+		if ccode.IsFail(code) {
+			t.Fatalf("[nodeID = %s] call fail. code = %d", target, code)
+		}
+
+		if expected := target + "@tcp"; reply.Value != expected {
+			t.Fatalf("expected %s, got %s", expected, reply.Value)
+		}
+	}
+
+	for _, app := range apps {
+		app.Shutdown()
+	}
+}
@@ -2,15 +2,16 @@ package cherry
 
 import (
 	cfacade "github.com/cherry-game/cherry/facade"
+	clog "github.com/cherry-game/cherry/logger"
 	ccluster "github.com/cherry-game/cherry/net/cluster"
 	cdiscovery "github.com/cherry-game/cherry/net/discovery"
-	cherryDiscovery "github.com/cherry-game/cherry/net/discovery"
 )
 
 type (
 	AppBuilder struct {
 		*Application
-		components []cfacade.IComponent
+		components  []cfacade.IComponent
+		discoveries map[string]cfacade.IDiscoveryComponent // discovery components set on this builder, keyed by mode
 	}
 )
 
@@ -18,6 +19,17 @@ func Configure(profileFilePath, nodeID string, isFrontend bool, mode NodeMode) *
 	appBuilder := &AppBuilder{
 		Application: NewApp(profileFilePath, nodeID, isFrontend, mode),
 		components:  make([]cfacade.IComponent, 0),
+		discoveries: make(map[string]cfacade.IDiscoveryComponent),
+	}
+
+	return appBuilder
+}
+
+func ConfigureProfile(profile cfacade.IProfile, node cfacade.INode, isFrontend bool, mode NodeMode) *AppBuilder {
+	appBuilder := &AppBuilder{
+		Application: NewAppProfile(profile, node, isFrontend, mode),
+		components:  make([]cfacade.IComponent, 0),
+		discoveries: make(map[string]cfacade.IDiscoveryComponent),
 	}
 
 	return appBuilder
@@ -27,6 +39,7 @@ func ConfigureNode(node cfacade.INode, isFrontend bool, mode NodeMode) *AppBuild
 	appBuilder := &AppBuilder{
 		Application: NewAppNode(node, isFrontend, mode),
 		components:  make([]cfacade.IComponent, 0),
+		discoveries: make(map[string]cfacade.IDiscoveryComponent),
 	}
 
 	return appBuilder
@@ -43,7 +56,7 @@ func (p *AppBuilder) Startup() {
 		app.Register(app.cluster)
 
 		// Obtain the discovery service according to the configured mode
-		app.discovery = cdiscovery.New()
+		app.discovery = p.newDiscovery()
 		app.Register(app.discovery)
 	}
 
@@ -73,12 +86,30 @@ func (p *AppBuilder) SetSerializer(serializer cfacade.ISerializer) {
 	p.serializer = serializer
 }
 
+// SetDiscovery sets a discovery component for this application only. It is
+// used when its Mode() matches the profile "cluster.discovery.mode".
 func (p *AppBuilder) SetDiscovery(discovery cfacade.IDiscoveryComponent) {
 	if discovery == nil {
 		return
 	}
 
-	cherryDiscovery.Register(discovery)
+	p.discoveries[discovery.Mode()] = discovery
+}
+
+// newDiscovery returns the discovery component set on this builder for the
+// profile mode, or a new instance from the cdiscovery registry.
+func (p *AppBuilder) newDiscovery() cfacade.IDiscoveryComponent {
+	mode, err := cdiscovery.GetModeWithProfile(p.Profile())
+	if err != nil {
+		clog.Fatal(err)
+		return nil
+	}
+
+	if discovery, found := p.discoveries[mode]; found {
+		return discovery
+	}
+
+	return cdiscovery.NewWithProfile(p.Profile())
 }
 
 func (a *AppBuilder) SetCluster(cluster cfacade.IClusterComponent) {
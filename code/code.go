@@ -8,6 +8,7 @@ const (
 	SessionUIDNotBind     int32 = 10 // session UID not bound
 	DiscoveryNotFoundNode int32 = 11 // target node not found in discovery
 	NodeRequestError      int32 = 12 // node request failed
+	NodeShutdown          int32 = 13 // node is shutting down
 
 	// RPC transport
 	RPCNetError           int32 = 20 // network error
@@ -30,6 +31,8 @@ const (
 	ActorNotFound           int32 = 35 // Actor not found
 	ActorInvokeRemoteError  int32 = 36 // remote invoke error
 	ActorResponseIsError    int32 = 37 // response is an error
+	ActorMailboxFull        int32 = 38 // target mailbox is full
+	ActorFuncNotFound       int32 = 39 // function not registered on the target
 )
 
 // IsOK returns true if code equals OK (0).
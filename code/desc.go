@@ -0,0 +1,61 @@
+package cherryCode
+
+import "sync"
+
+var (
+	descLock sync.RWMutex
+	descMap  = map[int32]string{
+		OK:                      "success",
+		SessionUIDNotBind:       "session uid not bound",
+		DiscoveryNotFoundNode:   "node not found",
+		NodeRequestError:        "node request failed",
+		NodeShutdown:            "node is shutting down",
+		RPCNetError:             "network error",
+		RPCUnmarshalError:       "unmarshal error",
+		RPCMarshalError:         "marshal error",
+		RPCRemoteExecuteError:   "remote execute error",
+		ActorPathIsNil:          "actor path is nil",
+		ActorFuncNameError:      "function name invalid",
+		ActorConvertPathError:   "actor path error",
+		ActorMarshalError:       "arg marshal error",
+		ActorUnmarshalError:     "arg unmarshal error",
+		ActorInvokeResultIsNil:  "invoke result is nil",
+		ActorSourceEqualTarget:  "source equals target",
+		ActorPublishRemoteError: "remote publish error",
+		ActorChildIDNotFound:    "child actor not found",
+		ActorCallTimeout:        "call timeout",
+		ActorIDIsNil:            "actor id is nil",
+		ActorNotFound:           "actor not found",
+		ActorInvokeRemoteError:  "remote invoke error",
+		ActorResponseIsError:    "response is an error",
+		ActorMailboxFull:        "mailbox is full",
+		ActorFuncNotFound:       "function not found",
+	}
+)
+
+// Register sets the description of code. Applications register their own
+// codes, usually at startup; a registered code is overwritten.
+func Register(code int32, desc string) {
+	descLock.Lock()
+	defer descLock.Unlock()
+
+	descMap[code] = desc
+}
+
+// Registers sets the descriptions of several codes.
+func Registers(descs map[int32]string) {
+	descLock.Lock()
+	defer descLock.Unlock()
+
+	for code, desc := range descs {
+		descMap[code] = desc
+	}
+}
+
+// Desc returns the description of code, or an empty string if it is not registered.
+func Desc(code int32) string {
+	descLock.RLock()
+	defer descLock.RUnlock()
+
+	return descMap[code]
+}
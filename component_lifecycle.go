@@ -0,0 +1,88 @@
+package cherry
+
+import (
+	"fmt"
+
+	cerror "github.com/cherry-game/cherry/error"
+	cutils "github.com/cherry-game/cherry/extend/utils"
+	cfacade "github.com/cherry-game/cherry/facade"
+)
+
+// ComponentError reports the component and lifecycle step that failed during startup.
+type ComponentError struct {
+	Name  string // component name
+	Phase string // lifecycle step, "Init" or "OnAfterInit"
+	Err   error  // error returned (or panic recovered) by the component
+}
+
+func (e *ComponentError) Error() string {
+	return fmt.Sprintf("[component = %s] -> %s() fail. err = %v", e.Name, e.Phase, e.Err)
+}
+
+func (e *ComponentError) Unwrap() error {
+	return e.Err
+}
+
+// initComponent calls InitE if the component implements cfacade.IComponentInitE,
+// otherwise Init. A panic is converted into an error.
+func initComponent(c cfacade.IComponent) error {
+	var err error
+
+	cutils.Try(func() {
+		if initE, ok := c.(cfacade.IComponentInitE); ok {
+			err = initE.InitE()
+		} else {
+			c.Init()
+		}
+	}, func(errString string) {
+		err = cerror.Error(errString)
+	})
+
+	if err != nil {
+		return &ComponentError{Name: c.Name(), Phase: "Init", Err: err}
+	}
+	return nil
+}
+
+// afterInitComponent calls OnAfterInitE if the component implements
+// cfacade.IComponentAfterInitE, otherwise OnAfterInit. A panic is converted into an error.
+func afterInitComponent(c cfacade.IComponent) error {
+	var err error
+
+	cutils.Try(func() {
+		if afterInitE, ok := c.(cfacade.IComponentAfterInitE); ok {
+			err = afterInitE.OnAfterInitE()
+		} else {
+			c.OnAfterInit()
+		}
+	}, func(errString string) {
+		err = cerror.Error(errString)
+	})
+
+	if err != nil {
+		return &ComponentError{Name: c.Name(), Phase: "OnAfterInit", Err: err}
+	}
+	return nil
+}
+
+// stopComponents runs OnBeforeStop and then OnStop on the components in reverse order.
+// A panic in one component is logged and does not prevent the others from stopping.
+func stopComponents(logger cfacade.ILogger, components []cfacade.IComponent) {
+	for i := len(components) - 1; i >= 0; i-- {
+		cutils.Try(func() {
+			logger.Infof("[component = %s] -> OnBeforeStop().", components[i].Name())
+			components[i].OnBeforeStop()
+		}, func(errString string) {
+			logger.Warnf("[component = %s] -> OnBeforeStop(). error = %s", components[i].Name(), errString)
+		})
+	}
+
+	for i := len(components) - 1; i >= 0; i-- {
+		cutils.Try(func() {
+			logger.Infof("[component = %s] -> OnStop().", components[i].Name())
+			components[i].OnStop()
+		}, func(errString string) {
+			logger.Warnf("[component = %s] -> OnStop(). error = %s", components[i].Name(), errString)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package cherry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	cerror "github.com/cherry-game/cherry/error"
+	cfacade "github.com/cherry-game/cherry/facade"
+)
+
+// lifecycleComponent records the lifecycle hooks invoked on it into a shared trace.
+type lifecycleComponent struct {
+	cfacade.Component
+	name      string
+	trace     *[]string
+	initErr   error
+	afterErr  error
+	initPanic bool
+}
+
+func (c *lifecycleComponent) Name() string {
+	return c.name
+}
+
+func (c *lifecycleComponent) InitE() error {
+	*c.trace = append(*c.trace, c.name+".Init")
+	if c.initPanic {
+		panic("init panic")
+	}
+	return c.initErr
+}
+
+func (c *lifecycleComponent) OnAfterInitE() error {
+	*c.trace = append(*c.trace, c.name+".OnAfterInit")
+	return c.afterErr
+}
+
+func (c *lifecycleComponent) OnBeforeStop() {
+	*c.trace = append(*c.trace, c.name+".OnBeforeStop")
+}
+
+func (c *lifecycleComponent) OnStop() {
+	*c.trace = append(*c.trace, c.name+".OnStop")
+}
+
+// TestInitComponents_InitFail verifies that a failing InitE aborts startup and
+// stops only the components that were already initialized, in reverse order.
+func TestInitComponents_InitFail(t *testing.T) {
+	var trace []string
+	app := &Application{}
+	app.Register(
+		&lifecycleComponent{name: "a", trace: &trace},
+		&lifecycleComponent{name: "b", trace: &trace},
+		&lifecycleComponent{name: "c", trace: &trace, initErr: cerror.Error("open db fail")},
+		&lifecycleComponent{name: "d", trace: &trace},
+	)
+
+	err := app.initComponents()
+
+	var componentErr *ComponentError
+	if !errors.As(err, &componentErr) {
+		t.Fatalf("expected *ComponentError, got %v", err)
+	}
+	if componentErr.Name != "c" || componentErr.Phase != "Init" {
+		t.Fatalf("expected c Init failure, got %s %s", componentErr.Name, componentErr.Phase)
+	}
+
+	expected := "a.Init,b.Init,c.Init,b.OnBeforeStop,a.OnBeforeStop,b.OnStop,a.OnStop"
+	if got := strings.Join(trace, ","); got != expected {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+// TestInitComponents_InitPanic verifies that a panic in Init is reported as a
+// startup failure of that component.
+func TestInitComponents_InitPanic(t *testing.T) {
+	var trace []string
+	app := &Application{}
+	app.Register(
+		&lifecycleComponent{name: "a", trace: &trace, initPanic: true},
+	)
+
+	err := app.initComponents()
+	if err == nil || !strings.Contains(err.Error(), "[component = a]") {
+		t.Fatalf("expected component a failure, got %v", err)
+	}
+}
+
+// TestInitComponents_AfterInitFail verifies that a failing OnAfterInitE stops
+// every initialized component.
+func TestInitComponents_AfterInitFail(t *testing.T) {
+	var trace []string
+	app := &Application{}
+	app.Register(
+		&lifecycleComponent{name: "a", trace: &trace, afterErr: cerror.Error("load fail")},
+		&lifecycleComponent{name: "b", trace: &trace},
+	)
+
+	err := app.initComponents()
+	if err == nil {
+		t.Fatal("expected OnAfterInit failure")
+	}
+
+	expected := "a.Init,b.Init,a.OnAfterInit,b.OnBeforeStop,a.OnBeforeStop,b.OnStop,a.OnStop"
+	if got := strings.Join(trace, ","); got != expected {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func (c *lifecycleComponent) OnDrain(_ context.Context) {
+	*c.trace = append(*c.trace, c.name+".OnDrain")
+}
+
+// drainConnector is a connector that records its drain into a shared trace.
+type drainConnector struct {
+	lifecycleComponent
+}
+
+func (*drainConnector) Start()                            {}
+func (*drainConnector) Stop()                             {}
+func (*drainConnector) OnConnect(_ cfacade.OnConnectFunc) {}
+
+// drainParser is a net parser that records its drain into a shared trace.
+type drainParser struct {
+	trace *[]string
+}
+
+func (*drainParser) Load(_ cfacade.IApplication)       {}
+func (*drainParser) AddConnector(_ cfacade.IConnector) {}
+func (*drainParser) Connectors() []cfacade.IConnector  { return nil }
+func (p *drainParser) OnDrain(_ context.Context)       { *p.trace = append(*p.trace, "parser.OnDrain") }
+
+// TestDrain_Order verifies that connectors drain first, then the net parser,
+// then the other components in reverse order.
+func TestDrain_Order(t *testing.T) {
+	var trace []string
+	app := &Application{netParser: &drainParser{trace: &trace}}
+	app.Register(
+		&lifecycleComponent{name: "a", trace: &trace},
+		&lifecycleComponent{name: "b", trace: &trace},
+		&drainConnector{lifecycleComponent{name: "tcp", trace: &trace}},
+	)
+
+	app.drain(time.Second)
+
+	expected := "tcp.OnDrain,parser.OnDrain,b.OnDrain,a.OnDrain"
+	if got := strings.Join(trace, ","); got != expected {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+// TestDrain_Disabled verifies that a zero shutdown timeout skips the drain phase.
+func TestDrain_Disabled(t *testing.T) {
+	var trace []string
+	app := &Application{}
+	app.Register(&lifecycleComponent{name: "a", trace: &trace})
+
+	app.drain(0)
+
+	if len(trace) > 0 {
+		t.Fatalf("expected no drain, got %v", trace)
+	}
+}
@@ -0,0 +1,114 @@
+package cherry
+
+import (
+	"strings"
+
+	cerror "github.com/cherry-game/cherry/error"
+	cfacade "github.com/cherry-game/cherry/facade"
+)
+
+// dependencies returns the component names that c declares through
+// cfacade.IComponentDependency, or nil if it declares none.
+func dependencies(c cfacade.IComponent) []string {
+	if d, ok := c.(cfacade.IComponentDependency); ok {
+		return d.Dependencies()
+	}
+	return nil
+}
+
+// sortComponents orders components so that each one comes after all of its
+// dependencies. Components without an ordering constraint between them keep
+// their registration order, so an application that declares no dependencies
+// starts exactly as before.
+//
+// Returns an error naming the offending components if a dependency is not
+// registered or the dependencies form a cycle.
+func sortComponents(components []cfacade.IComponent) ([]cfacade.IComponent, error) {
+	index := make(map[string]int, len(components))
+	for i, c := range components {
+		index[c.Name()] = i
+	}
+
+	for _, c := range components {
+		for _, dep := range dependencies(c) {
+			if _, found := index[dep]; !found {
+				return nil, cerror.Errorf("[component = %s] depends on [component = %s], which is not registered", c.Name(), dep)
+			}
+		}
+	}
+
+	var (
+		sorted = make([]cfacade.IComponent, 0, len(components))
+		placed = make([]bool, len(components))
+	)
+
+	for len(sorted) < len(components) {
+		progress := false
+
+		for i, c := range components {
+			if placed[i] || !dependenciesPlaced(c, index, placed) {
+				continue
+			}
+
+			placed[i] = true
+			sorted = append(sorted, c)
+			progress = true
+			break // restart from the first component to keep registration order
+		}
+
+		if !progress {
+			return nil, cerror.Errorf("component dependency cycle: %s", findCycle(components, index, placed))
+		}
+	}
+
+	return sorted, nil
+}
+
+func dependenciesPlaced(c cfacade.IComponent, index map[string]int, placed []bool) bool {
+	for _, dep := range dependencies(c) {
+		if !placed[index[dep]] {
+			return false
+		}
+	}
+	return true
+}
+
+// findCycle walks the dependency edges of the unplaced components until a
+// component repeats, and returns the cycle as "a -> b -> a".
+func findCycle(components []cfacade.IComponent, index map[string]int, placed []bool) string {
+	start := -1
+	for i := range components {
+		if !placed[i] {
+			start = i
+			break
+		}
+	}
+
+	if start < 0 {
+		return ""
+	}
+
+	var (
+		path    []string
+		visited = make(map[int]int) // component index -> position in path
+		current = start
+	)
+
+	for {
+		if pos, found := visited[current]; found {
+			cycle := append(path[pos:], components[current].Name())
+			return strings.Join(cycle, " -> ")
+		}
+
+		visited[current] = len(path)
+		path = append(path, components[current].Name())
+
+		// every unplaced component has at least one unplaced dependency
+		for _, dep := range dependencies(components[current]) {
+			if i := index[dep]; !placed[i] {
+				current = i
+				break
+			}
+		}
+	}
+}
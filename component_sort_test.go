@@ -0,0 +1,110 @@
+package cherry
+
+import (
+	"strings"
+	"testing"
+
+	cfacade "github.com/cherry-game/cherry/facade"
+)
+
+type testComponent struct {
+	cfacade.Component
+	name string
+	deps []string
+}
+
+func (c *testComponent) Name() string {
+	return c.name
+}
+
+func (c *testComponent) Dependencies() []string {
+	return c.deps
+}
+
+func newTestComponent(name string, deps ...string) *testComponent {
+	return &testComponent{name: name, deps: deps}
+}
+
+func componentNames(components []cfacade.IComponent) string {
+	var names []string
+	for _, c := range components {
+		names = append(names, c.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+// TestSortComponents_KeepRegistrationOrder verifies that components without
+// dependencies keep their registration order.
+func TestSortComponents_KeepRegistrationOrder(t *testing.T) {
+	components := []cfacade.IComponent{
+		newTestComponent("a"),
+		newTestComponent("b"),
+		newTestComponent("c"),
+	}
+
+	sorted, err := sortComponents(components)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := componentNames(sorted); got != "a,b,c" {
+		t.Fatalf("expected a,b,c, got %s", got)
+	}
+}
+
+// TestSortComponents_Dependencies verifies that a component registered before
+// its dependency is moved after it.
+func TestSortComponents_Dependencies(t *testing.T) {
+	components := []cfacade.IComponent{
+		newTestComponent("player_cache", "db"),
+		newTestComponent("actor"),
+		newTestComponent("db", "config"),
+		newTestComponent("config"),
+	}
+
+	sorted, err := sortComponents(components)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := componentNames(sorted); got != "actor,config,db,player_cache" {
+		t.Fatalf("expected actor,config,db,player_cache, got %s", got)
+	}
+}
+
+// TestSortComponents_MissingDependency verifies that a dependency on an
+// unregistered component is reported.
+func TestSortComponents_MissingDependency(t *testing.T) {
+	components := []cfacade.IComponent{
+		newTestComponent("player_cache", "db"),
+	}
+
+	_, err := sortComponents(components)
+	if err == nil {
+		t.Fatal("expected missing dependency error")
+	}
+
+	if !strings.Contains(err.Error(), "db") {
+		t.Fatalf("expected error to name the missing component, got %v", err)
+	}
+}
+
+// TestSortComponents_Cycle verifies that a dependency cycle is reported with
+// the components taking part in it.
+func TestSortComponents_Cycle(t *testing.T) {
+	components := []cfacade.IComponent{
+		newTestComponent("log"),
+		newTestComponent("a", "b"),
+		newTestComponent("b", "c"),
+		newTestComponent("c", "a"),
+	}
+
+	_, err := sortComponents(components)
+	if err == nil {
+		t.Fatal("expected dependency cycle error")
+	}
+
+	if !strings.Contains(err.Error(), "a -> b -> c -> a") {
+		t.Fatalf("expected cycle a -> b -> c -> a, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package cherryError
+
+import (
+	"errors"
+	"fmt"
+
+	ccode "github.com/cherry-game/cherry/code"
+)
+
+// CodeError is an error carrying a cherryCode, a message and optional
+// key/value details. Remote handlers return it in place of the int32 code,
+// and it reaches callers across the cluster and clients through Response.
+type CodeError struct {
+	Code    int32             // cherryCode or an application code
+	Message string            // error message, the code description if empty
+	Details map[string]string // optional details
+}
+
+// NewCode creates a CodeError. The message defaults to the description of code.
+func NewCode(code int32, message ...string) *CodeError {
+	err := &CodeError{Code: code}
+	if len(message) > 0 {
+		err.Message = message[0]
+	} else {
+		err.Message = ccode.Desc(code)
+	}
+
+	return err
+}
+
+// NewCodef creates a CodeError with a formatted message.
+func NewCodef(code int32, format string, a ...any) *CodeError {
+	return &CodeError{Code: code, Message: fmt.Sprintf(format, a...)}
+}
+
+// WithDetail sets the detail key to value and returns the error.
+func (p *CodeError) WithDetail(key, value string) *CodeError {
+	if p.Details == nil {
+		p.Details = make(map[string]string)
+	}
+
+	p.Details[key] = value
+	return p
+}
+
+func (p *CodeError) Error() string {
+	message := p.Message
+	if message == "" {
+		message = ccode.Desc(p.Code)
+	}
+
+	if len(p.Details) < 1 {
+		return fmt.Sprintf("code = %d, message = %s", p.Code, message)
+	}
+
+	return fmt.Sprintf("code = %d, message = %s, details = %v", p.Code, message, p.Details)
+}
+
+// CodeOf returns the code of err: OK for nil, the code of a wrapped
+// CodeError, and RPCRemoteExecuteError for any other error.
+func CodeOf(err error) int32 {
+	if err == nil {
+		return ccode.OK
+	}
+
+	var codeErr *CodeError
+	if errors.As(err, &codeErr) {
+		return codeErr.Code
+	}
+
+	return ccode.RPCRemoteExecuteError
+}
+
+// AsCode converts err to a CodeError, nil for nil. Other errors get
+// RPCRemoteExecuteError and their text as the message.
+func AsCode(err error) *CodeError {
+	if err == nil {
+		return nil
+	}
+
+	var codeErr *CodeError
+	if errors.As(err, &codeErr) {
+		return codeErr
+	}
+
+	return &CodeError{Code: ccode.RPCRemoteExecuteError, Message: err.Error()}
+}
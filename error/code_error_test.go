@@ -0,0 +1,74 @@
+package cherryError
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	ccode "github.com/cherry-game/cherry/code"
+)
+
+// TestNewCode verifies that the message defaults to the code description.
+func TestNewCode(t *testing.T) {
+	err := NewCode(ccode.ActorCallTimeout)
+	if err.Code != ccode.ActorCallTimeout || err.Message != ccode.Desc(ccode.ActorCallTimeout) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if err = NewCode(1001, "not enough gold"); err.Message != "not enough gold" {
+		t.Fatalf("unexpected message %s", err.Message)
+	}
+
+	if err = NewCodef(1001, "need %d gold", 10); err.Message != "need 10 gold" {
+		t.Fatalf("unexpected message %s", err.Message)
+	}
+}
+
+// TestCodeError_Error verifies the text of the error with and without details.
+func TestCodeError_Error(t *testing.T) {
+	err := NewCode(1001, "not enough gold")
+	if text := err.Error(); text != "code = 1001, message = not enough gold" {
+		t.Fatalf("unexpected text %s", text)
+	}
+
+	err.WithDetail("need", "10")
+	if text := err.Error(); text != "code = 1001, message = not enough gold, details = map[need:10]" {
+		t.Fatalf("unexpected text %s", text)
+	}
+
+	if text := (&CodeError{Code: ccode.OK}).Error(); text != fmt.Sprintf("code = 0, message = %s", ccode.Desc(ccode.OK)) {
+		t.Fatalf("unexpected text %s", text)
+	}
+}
+
+// TestCodeOf verifies the code of nil, wrapped CodeError and other errors.
+func TestCodeOf(t *testing.T) {
+	if code := CodeOf(nil); code != ccode.OK {
+		t.Fatalf("expected OK, got %d", code)
+	}
+
+	if code := CodeOf(fmt.Errorf("buy: %w", NewCode(1001))); code != 1001 {
+		t.Fatalf("expected 1001, got %d", code)
+	}
+
+	if code := CodeOf(errors.New("boom")); code != ccode.RPCRemoteExecuteError {
+		t.Fatalf("expected RPCRemoteExecuteError, got %d", code)
+	}
+}
+
+// TestAsCode verifies that AsCode unwraps a CodeError and converts other errors.
+func TestAsCode(t *testing.T) {
+	if err := AsCode(nil); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	codeErr := NewCode(1001, "not enough gold")
+	if err := AsCode(fmt.Errorf("buy: %w", codeErr)); err != codeErr {
+		t.Fatalf("expected the wrapped error, got %v", err)
+	}
+
+	err := AsCode(errors.New("boom"))
+	if err.Code != ccode.RPCRemoteExecuteError || err.Message != "boom" {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -74,6 +74,7 @@ var (
 	ClusterRequestFail            = Error("Cluster request failed")
 	ClusterNodeTypeIsNil          = Error("Cluster node type is nil")
 	ClusterNodeTypeMemberNotFound = Error("Cluster node type member not found")
+	ClusterSubjectNotSupported    = Error("Cluster transport does not support raw subjects")
 )
 
 // discovery
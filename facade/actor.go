@@ -9,6 +9,7 @@
 package cherryFacade
 
 import (
+	"context"
 	"time"
 
 	creflect "github.com/cherry-game/cherry/extend/reflect"
@@ -29,7 +30,9 @@ type (
 		PostEvent(data IEventData)                                             // broadcast an event to all subscribers matching data.Name()
 		Call(source, target, funcName string, arg any) int32                   // async RPC to target actor, returns cherryCode status code
 		CallWait(source, target, funcName string, arg, reply any) int32         // sync RPC to target actor with reply, returns cherryCode status code
-		CallType(nodeType, actorID, funcName string, arg any) int32             // call a random Actor of the given node type, returns cherryCode status code
+		CallWaitContext(ctx context.Context, source, target, funcName string, arg, reply any) int32 // CallWait bounded by the deadline and cancellation of ctx
+		CallWaitError(source, target, funcName string, arg, reply any) error    // CallWait returning nil, or a *cherryError.CodeError with the replied message and details
+		CallType(nodeType, actorID, funcName string, arg any) int32             // call the Actor on the member chosen by the balancer, or on every member of the node type, returns cherryCode status code
 		SetLocalInvoke(invoke InvokeFunc)                                      // set the low-level dispatch hook for local messages
 		SetRemoteInvoke(invoke InvokeFunc)                                     // set the low-level dispatch hook for remote messages
 		SetCallTimeout(d time.Duration)                                        // set RPC call timeout (default 3s)
@@ -37,6 +40,10 @@ type (
 		SetExecutionTimeout(t int64)                                           // set handler execution timeout in ms (default 100ms)
 		SetTimerTick(d time.Duration)                                          // set time wheel tick (default 10ms, before startup)
 		SetTimerHint(n int)                                                    // set time wheel nodeMap pre-alloc hint
+		SetMetricsByID(enable bool)                                            // label actor metrics with actor ids instead of handler types (before startup)
+		SetFactory(factory ActorFactory)                                       // create unknown Actors on their first message
+		AddInterceptor(interceptors ...Interceptor)                            // append interceptors around every Actor invocation (before startup)
+		AddResolver(resolvers ...PathResolver)                                 // translate logical target paths of Call/CallWait into Actor paths (before startup)
 	}
 
 	// InvokeFunc is the low-level dispatch hook called when a message arrives at an Actor.
@@ -48,6 +55,24 @@ type (
 	// deserializes args, and calls the registered function.
 	InvokeFunc func(app IApplication, fi *creflect.FuncInfo, m *Message)
 
+	// Interceptor wraps the invocation of a local or remote Actor function.
+	// It runs before and after the handler by calling next, and short-circuits
+	// by returning a failure cherryCode without calling next. Remote callers
+	// waiting for a reply then receive that code; local (client) messages are
+	// answered by the interceptor itself, e.g. with pomelo.ResponseCode.
+	// m.Session may be modified before calling next.
+	Interceptor func(m *Message, fi *creflect.FuncInfo, next func() int32) int32
+
+	// PathResolver translates a logical target path, such as the path of a
+	// cluster singleton, into the path of the Actor currently serving it.
+	// Return false when target is not a logical path of this resolver.
+	PathResolver func(target string) (path string, found bool)
+
+	// ActorFactory creates the handler of an Actor that does not exist yet,
+	// such as a passivated Actor receiving a new message. Return false when
+	// the id is unknown.
+	ActorFactory func(id string) (IActorHandler, bool)
+
 	// IActor is a single Actor instance running on its own goroutine.
 	// All handlers registered in OnInit are invoked serially on that goroutine —
 	// no locks are needed for Actor-local state.
@@ -60,10 +85,14 @@ type (
 		Path() *ActorPath                                                    // parsed "nodeID.actorID" path
 		Call(targetPath, funcName string, arg any) int32                     // async RPC to another Actor, returns cherryCode status code
 		CallWait(targetPath, funcName string, arg, reply any) int32           // sync RPC with reply, returns cherryCode status code
-		CallType(nodeType, actorID, funcName string, arg any) int32           // call a random Actor of the given node type, returns cherryCode status code
+		CallWaitError(targetPath, funcName string, arg, reply any) error      // CallWait returning nil, or a *cherryError.CodeError with the replied message and details
+		CallAsync(targetPath, funcName string, arg, reply any, callback func(code int32)) // RPC with reply, callback runs on this Actor's goroutine
+		Context() context.Context                                            // deadline of the message being processed; calls made by this Actor inherit it
+		CallType(nodeType, actorID, funcName string, arg any) int32           // call the Actor on the member chosen by the balancer, or on every member of the node type, returns cherryCode status code
 		PostRemote(m *Message)                                               // fire-and-forget to a remote Actor
 		PostLocal(m *Message)                                                // fire-and-forget to a local Actor
 		LastAt() int64                                                       // last activity timestamp in ms, updated on each message
+		QueueDepth() (local, remote, event int32)                            // number of messages waiting in each queue
 		Exit()                                                               // stop this Actor; cannot be restarted
 	}
 
@@ -93,6 +122,7 @@ type (
 		Each(fn func(i IActor))                                     // iterate all children (the callback must not mutate the child set)
 		Call(childID, funcName string, arg any)                     // call a handler on a child Actor, returns cherryCode status code
 		CallWait(childID, funcName string, arg, reply any) int32     // call a handler on a child and wait for reply, returns cherryCode status code
+		SetFactory(factory ActorFactory)                            // create unknown children on their first message
 	}
 )
 
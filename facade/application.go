@@ -3,6 +3,8 @@
 // This file defines application-level abstractions:
 //   - INode: node identity and configuration
 //   - IApplication: the application container — component registry, lifecycle, service accessors
+//   - IProfile: the profile config file loaded by an application
+//   - ILogger: the logger of an application
 //   - ProfileJSON: typed profile/config reader
 package cherryFacade
 
@@ -18,7 +20,7 @@ type (
 		NodeID() string        // globally unique node ID
 		NodeType() string      // node type (e.g. "game", "gate", "map")
 		Address() string       // public listen address (for frontend nodes)
-		RpcAddress() string    // RPC listen address (reserved)
+		RpcAddress() string    // RPC listen address (used by the tcp cluster transport)
 		Settings() ProfileJSON // node settings from profile
 		Enabled() bool         // whether this node is enabled
 	}
@@ -39,6 +41,40 @@ type (
 		Discovery() IDiscovery             // node discovery service
 		Cluster() ICluster                 // cluster messaging service
 		ActorSystem() IActorSystem         // Actor system
+		NetParser() INetParser             // net packet parser, nil on backend nodes
+		Profile() IProfile                 // profile config of this application
+		Logger() ILogger                   // node logger of this application
+	}
+
+	// ILogger is the logger of an application, implemented by
+	// *cherryLogger.CherryLogger. Framework code logs through
+	// IApplication.Logger(), so each application in a process writes with its
+	// own node fields and files.
+	ILogger interface {
+		Debug(args ...any)
+		Info(args ...any)
+		Warn(args ...any)
+		Error(args ...any)
+		Panic(args ...any)
+		Fatal(args ...any)
+		Debugf(template string, args ...any)
+		Infof(template string, args ...any)
+		Warnf(template string, args ...any)
+		Errorf(template string, args ...any)
+		Panicf(template string, args ...any)
+		Fatalf(template string, args ...any)
+	}
+
+	// IProfile is a loaded profile config file. Each Application owns one, so
+	// several applications in one process can run with different configs.
+	IProfile interface {
+		Path() string                      // profile config directory
+		Name() string                      // profile config filename
+		Env() string                       // environment name
+		Debug() bool                       // debug mode flag
+		PrintLevel() string                // cherry log output level
+		ShutdownTimeout() time.Duration    // shutdown drain deadline, 0 disables the drain phase
+		GetConfig(path ...any) ProfileJSON // sub-config at the given path
 	}
 
 	// ProfileJSON is a typed profile/config reader backed by jsoniter.
@@ -7,6 +7,8 @@ package cherryFacade
 
 import (
 	"time"
+
+	cproto "github.com/cherry-game/cherry/net/proto"
 )
 
 type (
@@ -28,11 +30,12 @@ type (
 	IDiscovery interface {
 		Mode() string                                                 // discovery mode name (e.g. "default", "nats", "etcd")
 		Map() map[string]IMember                                      // snapshot of all known members keyed by nodeID
-		ListByType(nodeType string, filterNodeID ...string) []IMember // members of a given node type, excluding filterNodeID
-		Random(nodeType string) (IMember, bool)                       // random member of the given node type; nil, false if none exist
+		ListByType(nodeType string, filterNodeID ...string) []IMember // serving members of a given node type, excluding filterNodeID
+		Random(nodeType string) (IMember, bool)                       // random serving member of the given node type; nil, false if none exist
 		GetMember(nodeID string) (member IMember, found bool)         // lookup a member by nodeID; nil, false if not found
 		UpdateSetting(key, value string)                              // update a single setting on THIS node and sync to other nodes
 		UpdateSettings(settings map[string]string)                    // update multiple settings on THIS node and sync to other nodes
+		SetStatus(status int32)                                       // update the status of THIS node (e.g. MemberDraining) and sync to other nodes
 		OnAddMember(listener MemberListener)                          // register callback invoked after a member is added
 		OnUpdateMember(listener MemberListener)                       // register callback invoked after a member is updated
 		OnRemoveMember(listener MemberListener)                       // register callback invoked after a member is removed
@@ -46,6 +49,7 @@ type (
 		GetNodeType() string            // node type (e.g. "game", "gate", "map")
 		GetAddress() string             // RPC address for cross-node communication
 		GetSettings() map[string]string // arbitrary key-value metadata for this node
+		GetStatus() int32               // lifecycle status, see MemberServing
 	}
 
 	// MemberListener is called after a member is added, updated, or removed
@@ -53,6 +57,17 @@ type (
 	MemberListener func(member IMember)
 )
 
+// Member lifecycle statuses returned by IMember.GetStatus(). Only serving
+// members are returned by IDiscovery.ListByType() and IDiscovery.Random().
+// Serving is the zero value, so members that never report a status (e.g. the
+// nodes of the profile file) stay routable.
+const (
+	MemberServing  int32 = 0 // accepts new traffic
+	MemberJoining  int32 = 1 // started, not running yet
+	MemberDraining int32 = 2 // finishing its work before a deploy, receives no new traffic
+	MemberLeaving  int32 = 3 // shutting down
+)
+
 type (
 	// IClusterComponent combines the component lifecycle with cluster messaging capabilities.
 	IClusterComponent interface {
@@ -69,7 +84,7 @@ type (
 	// Publish methods are fire-and-forget. RequestRemote sends a message and
 	// waits for a response, returning the raw payload and an error code.
 	ICluster interface {
-		Mode() string // cluster transport mode (e.g. "nats")
+		Mode() string // cluster transport mode (e.g. "nats", "memory")
 
 		// PublishLocal delivers a message to an actor on the local node.
 		// The message is NOT serialized — it is passed in-process.
@@ -116,4 +131,13 @@ type (
 		// services that subscribe to a well-known subject.
 		RawRequest(subject string, data []byte, timeout ...time.Duration) ([]byte, error)
 	}
+
+	// IClusterResponse is optionally implemented by an ICluster to return the
+	// whole response of a request, including the message and details of an
+	// error response that RequestRemote drops.
+	IClusterResponse interface {
+		// RequestResponse is RequestRemote returning the response. The response
+		// is nil when the request fails before the remote node answers.
+		RequestResponse(nodeID string, msg *Message, timeout ...time.Duration) (*cproto.Response, int32)
+	}
 )
@@ -3,12 +3,18 @@
 // This file defines the component system:
 //   - IComponent: named pluggable unit with lifecycle hooks
 //   - IComponentLifecycle: Set → Init → OnAfterInit (startup), OnBeforeStop → OnStop (shutdown)
+//   - IComponentDependency: optional declaration of the components that must start first
+//   - IComponentInitE / IComponentAfterInitE: optional error-returning startup hooks
+//   - IComponentDrain: optional drain hook run before shutdown
 //   - Component: embeddable base with no-op lifecycle stubs
 package cherryFacade
 
+import "context"
+
 type (
 	// IComponent is a named, lifecycle-aware unit registered with the Application.
-	// Components are initialized in registration order and stopped in reverse order.
+	// Components are initialized in dependency order (registration order breaks ties)
+	// and stopped in reverse order.
 	IComponent interface {
 		Name() string            // unique name within the Application
 		App() IApplication       // the owning Application
@@ -25,6 +31,40 @@ type (
 		OnBeforeStop()        // called before shutdown begins
 		OnStop()              // release resources
 	}
+
+	// IComponentDependency is optionally implemented by components that must be
+	// initialized after other components. The Application sorts its components
+	// topologically at startup: every dependency is initialized before and stopped
+	// after the components depending on it. A missing dependency or a dependency
+	// cycle aborts startup.
+	IComponentDependency interface {
+		Dependencies() []string // names of the components this component depends on
+	}
+
+	// IComponentInitE is optionally implemented by components whose initialization
+	// can fail. When implemented, InitE is called instead of Init. A non-nil error
+	// (or a panic) aborts startup: the components already initialized are stopped
+	// in reverse order and the process exits with a non-zero status.
+	IComponentInitE interface {
+		InitE() error // initialise internal state, returns an error on failure
+	}
+
+	// IComponentAfterInitE is the error-returning variant of OnAfterInit.
+	// When implemented, OnAfterInitE is called instead of OnAfterInit, with the
+	// same failure handling as IComponentInitE.
+	IComponentAfterInitE interface {
+		OnAfterInitE() error // called after ALL components have been Init'd, returns an error on failure
+	}
+
+	// IComponentDrain is optionally implemented by components and net parsers
+	// that have in-flight work to finish before shutdown. When the profile sets
+	// "shutdown_timeout", the Application calls OnDrain after the shutdown signal
+	// and before OnBeforeStop: connectors first, then the net parser, then the
+	// remaining components in reverse order. ctx expires at the shutdown deadline;
+	// work still pending at that point should be dropped and reported.
+	IComponentDrain interface {
+		OnDrain(ctx context.Context) // finish in-flight work, return when done or ctx is done
+	}
 )
 
 // Component is an embeddable base that provides no-op lifecycle methods.
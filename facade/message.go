@@ -31,16 +31,20 @@ type (
 	// For cross-process transfer, use Marshal/Unmarshal which internally uses ClusterPacket proto.
 	//
 	// Field groups:
-	//   Common: BuildTime, Source, Target, FuncName, Args
+	//   Common: BuildTime, Deadline, TraceID, SpanID, Source, Target, FuncName, Hops, Args
 	//   Local (client->Actor, set by parser): Session
 	//   Remote (Actor->Actor, set by System.Call/CallWait/CallType): ReqID, Reply, ChanResult
 	Message struct {
 		// --- Common fields ---
 		refs      int32       // reference count, see Recycle()
 		BuildTime int64       // message build time(ms)
+		Deadline  int64       // unix time(ms) after which the message is dropped, 0 means no deadline
+		TraceID   string      // trace id, empty if the message is not traced
+		SpanID    string      // span id of the sender, parent of the spans recorded by the receiver
 		Source    string      // source actor path
 		Target    string      // target actor path (node.actor or node.actor.child)
 		FuncName  string      // target function name
+		Hops      int32       // number of times the message was forwarded
 		Args      interface{} // payload: same-node=decoded object, cross-node=[]byte (pending decode)
 
 		// --- Local only (client->Actor, set by parser) ---
@@ -76,9 +80,13 @@ func (p *Message) Marshal() ([]byte, error) {
 	defer cp.Recycle()
 
 	cp.BuildTime = p.BuildTime
+	cp.Deadline = p.Deadline
+	cp.TraceId = p.TraceID
+	cp.SpanId = p.SpanID
 	cp.SourcePath = p.Source
 	cp.TargetPath = p.Target
 	cp.FuncName = p.FuncName
+	cp.Hops = p.Hops
 	cp.Session = p.Session
 
 	if argBytes, ok := p.Args.([]byte); ok {
@@ -98,9 +106,13 @@ func (p *Message) Unmarshal(data []byte) error {
 	}
 
 	p.BuildTime = cp.BuildTime
+	p.Deadline = cp.Deadline
+	p.TraceID = cp.TraceId
+	p.SpanID = cp.SpanId
 	p.Source = cp.SourcePath
 	p.Target = cp.TargetPath
 	p.FuncName = cp.FuncName
+	p.Hops = cp.Hops
 	p.Args = cp.ArgBytes // keep as []byte, decoded on-demand by Actor
 	p.Session = cp.Session
 
@@ -112,9 +124,13 @@ func (p *Message) Unmarshal(data []byte) error {
 func (p *Message) Clone() *Message {
 	clone := GetMessage()
 	clone.BuildTime = p.BuildTime
+	clone.Deadline = p.Deadline
+	clone.TraceID = p.TraceID
+	clone.SpanID = p.SpanID
 	clone.Source = p.Source
 	clone.Target = p.Target
 	clone.FuncName = p.FuncName
+	clone.Hops = p.Hops
 	clone.Args = p.Args       // shared (same-node=object, cross-node=read-only []byte)
 	clone.Session = p.Session // shared (Session must persist across forwarding)
 	clone.ReqID = p.ReqID
@@ -139,9 +155,13 @@ func (p *Message) Recycle() {
 
 	p.refs = 0
 	p.BuildTime = 0
+	p.Deadline = 0
+	p.TraceID = ""
+	p.SpanID = ""
 	p.Source = ""
 	p.Target = ""
 	p.FuncName = ""
+	p.Hops = 0
 	p.Session = nil
 	p.Args = nil
 	p.ReqID = ""
@@ -2,6 +2,7 @@
 //
 // This file defines the frontend network parser abstraction:
 //   - INetParser: protocol codec + connector assembly + agent Actor loading
+//   - ISessionCounter: optional, the number of clients connected to the node
 package cherryFacade
 
 type (
@@ -20,4 +21,10 @@ type (
 		AddConnector(connector IConnector)   // attach a network connector to this parser
 		Connectors() []IConnector            // return all attached connectors
 	}
+
+	// ISessionCounter is implemented by the parsers able to count the clients
+	// connected to the node, the load component publishes it as load.agents.
+	ISessionCounter interface {
+		SessionCount() int // clients connected to this node
+	}
 )
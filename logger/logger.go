@@ -33,19 +33,25 @@ type CherryLogger struct {
 // name, and replaces DefaultLogger with a fully configured instance (file
 // writer, common fields, fileName vars) from the profile config.
 func SetNodeLogger(node cfacade.INode) {
-	refLoggerName := node.Settings().Get("ref_logger").ToString()
-	if refLoggerName == "" {
+	if node.Settings().Get("ref_logger").ToString() == "" {
 		DefaultLogger.Warnf("RefLoggerName not found, used default console logger.")
 		return
 	}
 
+	// the package-level loggers created later also carry the node fields
 	defaultManager.SetFileNameVar(KEY_NODE_TYPE, node.NodeType())
 	defaultManager.SetFileNameVar(KEY_NODE_ID, node.NodeID())
 
 	defaultManager.SetCommonField(KEY_NODE_TYPE, node.NodeType())
 	defaultManager.SetCommonField(KEY_NODE_ID, node.NodeID())
 
-	DefaultLogger = defaultManager.GetOrCreateLogger(refLoggerName, zap.AddCallerSkip(1))
+	DefaultLogger = defaultManager.NodeLogger(node, zap.AddCallerSkip(1))
+}
+
+// DefaultManager returns the manager backing DefaultLogger and the
+// package-level functions.
+func DefaultManager() *Manager {
+	return defaultManager
 }
 
 // SetFileNameVar sets a template variable on the default manager. Keys such as
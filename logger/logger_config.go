@@ -101,7 +101,12 @@ func NewConfig(jsonConfig cfacade.ProfileJSON) (*Config, error) {
 // NewConfigWithName looks up a named logger definition under the "logger" key
 // in the global profile and returns its Config.
 func NewConfigWithName(refLoggerName string) (*Config, error) {
-	loggerConfig := cprofile.GetConfig("logger")
+	return NewConfigWithProfile(cprofile.GetConfig("logger"), refLoggerName)
+}
+
+// NewConfigWithProfile looks up a named logger definition in loggerConfig (the
+// "logger" section of a profile) and returns its Config.
+func NewConfigWithProfile(loggerConfig cfacade.ProfileJSON, refLoggerName string) (*Config, error) {
 	if loggerConfig.LastError() != nil {
 		return nil, loggerConfig.LastError()
 	}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	ctime "github.com/cherry-game/cherry/extend/time"
+	cfacade "github.com/cherry-game/cherry/facade"
+	cprofile "github.com/cherry-game/cherry/profile"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -178,6 +180,60 @@ func TestManager_Sync_NoPanic(t *testing.T) {
 	mgr.Sync()
 }
 
+type testNode struct {
+	cfacade.INode
+	nodeID string
+}
+
+func (n testNode) NodeID() string   { return n.nodeID }
+func (n testNode) NodeType() string { return "game" }
+func (n testNode) Settings() cfacade.ProfileJSON {
+	return cprofile.Wrap(map[string]any{"ref_logger": "game_log"})
+}
+
+func TestManager_NodeLogger_PerNode(t *testing.T) {
+	dir := filepath.Join(os.TempDir(), "cherry_logger_node_test")
+	os.MkdirAll(dir, 0755)
+	defer os.RemoveAll(dir)
+
+	mgr := NewManager(WithLoggerConfig(cprofile.Wrap(map[string]any{
+		"game_log": map[string]any{
+			"level":             "debug",
+			"enable_console":    false,
+			"enable_write_file": true,
+			"file_link_path":    filepath.Join(dir, "%nodeid.log"),
+			"file_path_format":  filepath.Join(dir, "%nodeid_%Y%m%d%H%M.log"),
+		},
+	})))
+
+	log1 := mgr.NodeLogger(testNode{nodeID: "game-1"})
+	log2 := mgr.NodeLogger(testNode{nodeID: "game-2"})
+	if log1 == log2 {
+		t.Fatal("nodes sharing a manager should not share a logger")
+	}
+	if mgr.NodeLogger(testNode{nodeID: "game-1"}) != log1 {
+		t.Error("the logger of a node should be cached")
+	}
+	if _, found := mgr.CommonFields()[KEY_NODE_ID]; found {
+		t.Error("NodeLogger should not set the manager's common fields")
+	}
+
+	log1.Info("from game-1")
+	log2.Info("from game-2")
+	_ = log1.Sync()
+	_ = log2.Sync()
+
+	for _, nodeID := range []string{"game-1", "game-2"} {
+		data, err := os.ReadFile(filepath.Join(dir, nodeID+".log"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "from "+nodeID) || !strings.Contains(string(data), KEY_NODE_ID+"="+nodeID) {
+			t.Errorf("%s.log = %q", nodeID, data)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Wrapper
 // ---------------------------------------------------------------------------
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"sync"
 
+	cfacade "github.com/cherry-game/cherry/facade"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -31,6 +32,7 @@ type Manager struct {
 	commonFields map[string]string
 	fileNameVars map[string]string
 	printLevel   zapcore.Level
+	loggerConfig cfacade.ProfileJSON // "logger" profile section, nil reads the global profile
 }
 
 // ManagerOption is a functional option for NewManager.
@@ -45,6 +47,14 @@ func WithCommonFields(fields map[string]string) ManagerOption {
 	}
 }
 
+// WithLoggerConfig sets the "logger" profile section used to build named
+// loggers. Without it the Manager reads the global profile.
+func WithLoggerConfig(loggerConfig cfacade.ProfileJSON) ManagerOption {
+	return func(m *Manager) {
+		m.loggerConfig = loggerConfig
+	}
+}
+
 // NewManager creates a Manager with an initial console-only default logger.
 func NewManager(opts ...ManagerOption) *Manager {
 	m := &Manager{
@@ -89,27 +99,102 @@ func (m *Manager) GetOrCreateLogger(refLoggerName string, opts ...zap.Option) *C
 		return logger
 	}
 
-	config, err := NewConfigWithName(refLoggerName)
+	logger := m.buildWithConfig(m.newConfig(refLoggerName), m.commonFields, m.fileNameVars, opts...)
+	m.loggers[refLoggerName] = logger
+	return logger
+}
+
+// NodeLogger returns the logger referenced by the node's "ref_logger" setting,
+// with the node type and ID as common fields and file name vars. Node loggers
+// are cached by node ID and logger name, so nodes sharing a Manager never get
+// the logger of one another and the Manager's own fields are left untouched.
+// Returns a console logger if the node references none.
+func (m *Manager) NodeLogger(node cfacade.INode, opts ...zap.Option) *CherryLogger {
+	refLoggerName := node.Settings().Get("ref_logger").ToString()
+	if refLoggerName == "" {
+		// the default logger skips one frame for the package-level functions
+		opts = append([]zap.Option{zap.AddCallerSkip(-1)}, opts...)
+		return &CherryLogger{
+			Config:        m.defaultLogger.Config,
+			SugaredLogger: m.defaultLogger.WithOptions(opts...),
+		}
+	}
+
+	logger := m.nodeLogger(node, refLoggerName)
+
+	// the cached logger is shared, options only apply to the returned copy
+	if len(opts) == 0 {
+		return logger
+	}
+
+	return &CherryLogger{
+		Config:        logger.Config,
+		SugaredLogger: logger.WithOptions(opts...),
+	}
+}
+
+// nodeLogger returns the cached logger of the node, building it with the
+// node fields on top of the Manager's fields.
+func (m *Manager) nodeLogger(node cfacade.INode, refLoggerName string) *CherryLogger {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := refLoggerName + "@" + node.NodeID()
+	if logger, found := m.loggers[key]; found {
+		return logger
+	}
+
+	nodeFields := map[string]string{
+		KEY_NODE_TYPE: node.NodeType(),
+		KEY_NODE_ID:   node.NodeID(),
+	}
+
+	logger := m.buildWithConfig(m.newConfig(refLoggerName), withFields(m.commonFields, nodeFields), withFields(m.fileNameVars, nodeFields))
+	m.loggers[key] = logger
+	return logger
+}
+
+// newConfig reads the config of the named logger from the "logger" profile section.
+func (m *Manager) newConfig(refLoggerName string) *Config {
+	var (
+		config *Config
+		err    error
+	)
+	if m.loggerConfig != nil {
+		config, err = NewConfigWithProfile(m.loggerConfig, refLoggerName)
+	} else {
+		config, err = NewConfigWithName(refLoggerName)
+	}
 	if err != nil {
 		Panicf("New Config fail. err = %v", err)
 	}
 
-	logger := m.buildWithConfig(config, opts...)
-	m.loggers[refLoggerName] = logger
-	return logger
+	return config
 }
 
 // buildWithConfig injects fileName vars, common fields, and wrappers,
 // then delegates to the shared buildLoggerCore. Caller must hold m.mu.
-func (m *Manager) buildWithConfig(config *Config, opts ...zap.Option) *CherryLogger {
+func (m *Manager) buildWithConfig(config *Config, commonFields, fileNameVars map[string]string, opts ...zap.Option) *CherryLogger {
 	if config.EnableWriteFile {
-		for key, value := range m.fileNameVars {
+		for key, value := range fileNameVars {
 			config.FileLinkPath = strings.ReplaceAll(config.FileLinkPath, "%"+key, value)
 			config.FilePathFormat = strings.ReplaceAll(config.FilePathFormat, "%"+key, value)
 		}
 	}
 
-	return NewConfigLogger(*config, m.commonFields, m.wrappers, opts...)
+	return NewConfigLogger(*config, commonFields, m.wrappers, opts...)
+}
+
+// withFields returns a copy of fields with extra set on top.
+func withFields(fields, extra map[string]string) map[string]string {
+	out := make(map[string]string, len(fields)+len(extra))
+	for k, v := range fields {
+		out[k] = v
+	}
+	for k, v := range extra {
+		out[k] = v
+	}
+	return out
 }
 
 // SetCommonField sets a single key-value pair applied to every log line.
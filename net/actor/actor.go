@@ -1,10 +1,15 @@
 package cherryActor
 
 import (
+	"context"
+	"fmt"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	ccode "github.com/cherry-game/cherry/code"
+	cerror "github.com/cherry-game/cherry/error"
 	cutils "github.com/cherry-game/cherry/extend/utils"
 	cfacade "github.com/cherry-game/cherry/facade"
 	clog "github.com/cherry-game/cherry/logger"
@@ -43,11 +48,31 @@ type (
 		state            atomic.Int32          // actor state (State)
 		close            chan struct{}         // close flag
 		handler          cfacade.IActorHandler // actor handler
+		factory          IActorFactory         // handler factory the actor was created with, nil if none, used by the watchdog
+		kind             string                // handler type, the metrics label of the actor
 		localMail        *mailbox              // local message mailbox
 		remoteMail       *mailbox              // remote message mailbox
 		event            *actorEvent           // event handle
 		timer            *actorTimer           // timer handle
 		child            *actorChild           // child actor
+		tasks            *queue                // funcs run on the actor goroutine (child failures, async call callbacks)
+		parentSupervisor *Supervisor           // supervisor inherited from the parent actor
+		restarts         []time.Time           // restart times within the supervisor window
+		stopped          bool                  // stopped by the supervisor or passivation
+		idleTTL          time.Duration         // passivate after being idle for idleTTL, 0 disables
+		idleTimer        ITimerHandle          // idle check timer
+		passivated       atomic.Bool           // stopped by passivation, queued messages are redelivered
+		handoff          string                // path the queued messages of a passivated actor are forwarded to, "" redelivers them here
+		parkMu           sync.Mutex            // orders the messages parked while passivating against redeliver
+		redelivered      bool                  // the queued messages were redelivered, guarded by parkMu
+		exited           chan struct{}         // closed once the actor has left the system
+		interceptors     []cfacade.Interceptor // run around the invocations of this actor
+		origin           callOrigin            // deadline and trace of the message being processed
+		inflight         inflight              // message being processed, checked by the watchdog
+		goid             atomic.Int64          // goroutine id, used by the watchdog to capture the stack
+		abandoned        atomic.Bool           // detached by the watchdog while stuck
+		ctx              context.Context       // lazily created by Context() for the message being processed
+		cancel           context.CancelFunc    // releases ctx once the message is processed
 		lastAt           int64                 // last process time (ms)
 		arrivalElapsed   int64                 // arrival elapsed for message
 		executionElapsed int64                 // execution elapsed for message
@@ -55,6 +80,7 @@ type (
 )
 
 func (p *Actor) run() {
+	p.goid.Store(currentGoroutineID())
 	p.onInit()
 	defer p.onStop()
 
@@ -66,7 +92,16 @@ func (p *Actor) run() {
 }
 
 func (p *Actor) loop() bool {
+	if p.stopped || p.abandoned.Load() {
+		return true
+	}
+
 	if p.State() == StopState {
+		// drain deadline exceeded, drop the remaining messages
+		if p.system.dropping.Load() {
+			return true
+		}
+
 		if p.localMail.Count() < 1 &&
 			p.remoteMail.Count() < 1 &&
 			p.event.Count() < 1 {
@@ -91,6 +126,10 @@ func (p *Actor) loop() bool {
 		{
 			p.processTimer()
 		}
+	case <-p.tasks.C:
+		{
+			p.processTask()
+		}
 	case <-p.close:
 		{
 			p.setState(StopState)
@@ -100,6 +139,21 @@ func (p *Actor) loop() bool {
 	return false
 }
 
+// queued returns the number of messages waiting in the local, remote and
+// event queues of this actor and its children.
+func (p *Actor) queued() int64 {
+	count := int64(p.localMail.Count()) + int64(p.remoteMail.Count()) + int64(p.event.Count())
+
+	p.child.childActors.Range(func(key, value any) bool {
+		if childActor, ok := value.(*Actor); ok {
+			count += childActor.queued()
+		}
+		return true
+	})
+
+	return count
+}
+
 // processLocal pops and processes one message from the local mailbox.
 //
 // Message lifecycle: each Actor pops a message and recycles it via defer.
@@ -112,6 +166,11 @@ func (p *Actor) processLocal() {
 	}
 	defer m.Recycle()
 
+	if p.watched() {
+		p.inflight.begin(m.FuncName, m.Source)
+		defer p.inflight.end()
+	}
+
 	p.lastAt = time.Now().UnixMilli()
 
 	next, invoke := p.handler.OnLocalReceived(m)
@@ -130,7 +189,8 @@ func (p *Actor) processLocal() {
 			if childActor, foundChild := p.findChildActor(m); foundChild {
 				childActor.PostLocal(m)
 			} else {
-				clog.Warnf("child actor not found. target=%s", m.Target)
+				p.logger().Warnf("child actor not found. target=%s", m.Target)
+				p.system.undeliveredMessage(m, DeadLetterChildNotFound, ccode.ActorChildIDNotFound)
 			}
 		}
 	} else {
@@ -148,6 +208,11 @@ func (p *Actor) processRemote() {
 	}
 	defer m.Recycle()
 
+	if p.watched() {
+		p.inflight.begin(m.FuncName, m.Source)
+		defer p.inflight.end()
+	}
+
 	p.lastAt = time.Now().UnixMilli()
 
 	next, invoke := p.handler.OnRemoteReceived(m)
@@ -166,7 +231,8 @@ func (p *Actor) processRemote() {
 			if childActor, foundChild := p.findChildActor(m); foundChild {
 				childActor.PostRemote(m)
 			} else {
-				clog.Warnf("child actor not found. target=%s", m.Target)
+				p.logger().Warnf("child actor not found. target=%s", m.Target)
+				p.system.undeliveredMessage(m, DeadLetterChildNotFound, ccode.ActorChildIDNotFound)
 			}
 		}
 	} else {
@@ -181,33 +247,86 @@ func (p *Actor) processEvent() {
 	}
 
 	p.lastAt = time.Now().UnixMilli()
+
+	if p.watched() {
+		p.inflight.begin(eventData.Name(), "event")
+		defer p.inflight.end()
+	}
+
 	p.event.invokeFunc(eventData)
 }
 
+func (p *Actor) processTask() {
+	task, ok := p.tasks.Pop().(func())
+	if !ok {
+		return
+	}
+
+	if p.watched() {
+		p.inflight.begin("task", "")
+		defer p.inflight.end()
+	}
+
+	cutils.Try(task, func(errString string) {
+		p.logger().Errorf("[%s] task invoke error. err = %s", p.path, errString)
+	})
+}
+
+// runTask runs fn on the actor goroutine.
+func (p *Actor) runTask(fn func()) {
+	p.tasks.Push(fn)
+}
+
 func (p *Actor) processTimer() {
 	timerID := p.timer.Pop()
 	if timerID < 1 {
 		return
 	}
 
+	if p.watched() {
+		p.inflight.begin("timer", "")
+		defer p.inflight.end()
+	}
+
 	p.timer.invokeFunc(timerID)
 }
 
 func (p *Actor) invokeFunc(mb *mailbox, app cfacade.IApplication, fn cfacade.InvokeFunc, m *cfacade.Message) {
 	funcInfo, found := mb.funcMap[m.FuncName]
 	if !found {
-		clog.Warnf("[%s] function not found. source=%s target=%s func=%s",
+		p.logger().Warnf("[%s] function not found. source=%s target=%s func=%s",
 			mb.name,
 			m.Source,
 			m.Target,
 			m.FuncName,
 		)
+		p.system.undeliveredMessage(m, DeadLetterFuncNotFound, ccode.ActorFuncNotFound)
 		return
 	}
 
+	if m.Deadline > 0 {
+		if p.lastAt > m.Deadline {
+			p.logger().Warnf("[%s] message expired %dms ago. source=%s target=%s func=%s",
+				mb.name,
+				p.lastAt-m.Deadline,
+				m.Source,
+				m.Target,
+				m.FuncName,
+			)
+			replyCode(app, m, ccode.ActorCallTimeout)
+			return
+		}
+
+		p.origin.deadline = m.Deadline
+	}
+	defer p.resetOrigin()
+
+	span := p.startSpan(mb, m)
+	start := time.Now()
+
 	p.arrivalElapsed = p.lastAt - m.BuildTime
 	if p.arrivalElapsed > p.system.arrivalTimeOut {
-		clog.Warnf("[%s] message arrived in %dms (limit=%dms) source=%s target=%s func=%s",
+		p.logger().Warnf("[%s] message arrived in %dms (limit=%dms) source=%s target=%s func=%s",
 			mb.name,
 			p.arrivalElapsed,
 			p.system.arrivalTimeOut,
@@ -218,9 +337,11 @@ func (p *Actor) invokeFunc(mb *mailbox, app cfacade.IApplication, fn cfacade.Inv
 	}
 
 	defer func() {
+		p.system.metrics.Load().invoked(p, mb, m.FuncName, p.arrivalElapsed, start)
+
 		p.executionElapsed = time.Now().UnixMilli() - p.lastAt
 		if p.executionElapsed > p.system.executionTimeout {
-			clog.Warnf("[%s] message executed in %dms (limit=%dms) source=%s target=%s func=%s",
+			p.logger().Warnf("[%s] message executed in %dms (limit=%dms) source=%s target=%s func=%s",
 				mb.name,
 				p.executionElapsed,
 				p.system.executionTimeout,
@@ -231,23 +352,36 @@ func (p *Actor) invokeFunc(mb *mailbox, app cfacade.IApplication, fn cfacade.Inv
 		}
 
 		if rev := recover(); rev != nil {
-			clog.Errorf("[%s] invoke panic. source=%s target=%s func=%s type=%v",
+			p.logger().Errorf("[%s] invoke panic. source=%s target=%s func=%s type=%v",
 				mb.name,
 				m.Source,
 				m.Target,
 				m.FuncName,
 				funcInfo.InArgs,
 			)
+			span.Finish(ccode.RPCRemoteExecuteError)
+			// fail fast instead of letting the caller wait for the call timeout
+			replyError(app, m, ccode.ActorInvokeRemoteError, fmt.Sprint(rev))
+			p.onFailure(rev, Resume)
 		}
 	}()
 
-	fn(app, funcInfo, m)
+	if typed, ok := mb.typedMap[m.FuncName]; ok {
+		span.Finish(p.intercept(m, funcInfo, func() {
+			typed(app, m)
+		}))
+		return
+	}
+
+	span.Finish(p.intercept(m, funcInfo, func() {
+		fn(app, funcInfo, m)
+	}))
 }
 
 func (p *Actor) findChildActor(m *cfacade.Message) (*Actor, bool) {
 	// If current actor is already a child, stop message processing.
 	if p.path.IsChild() {
-		clog.Warnf("[findChildActor] cannot create child from child. target=%s func=%s",
+		p.logger().Warnf("[findChildActor] cannot create child from child. target=%s func=%s",
 			m.Target,
 			m.FuncName,
 		)
@@ -260,6 +394,10 @@ func (p *Actor) findChildActor(m *cfacade.Message) (*Actor, bool) {
 		childActor, found = p.handler.OnFindChild(m)
 	}
 
+	if !found {
+		childActor, found = p.child.activate(m.TargetPath().ChildID)
+	}
+
 	if found {
 		if cActor, ok := childActor.(*Actor); ok {
 			return cActor, true
@@ -270,14 +408,32 @@ func (p *Actor) findChildActor(m *cfacade.Message) (*Actor, bool) {
 }
 
 func (p *Actor) onInit() {
-	p.handler.OnInit()
+	if rev := p.initHandler(); rev != nil {
+		p.logger().Errorf("[%s] init panic. err=%v", p.path, rev)
+		p.onFailure(rev, Stop)
+	}
 	p.setState(WorkerState)
 }
 
+// initHandler runs handler.OnInit and returns the recovered panic value.
+func (p *Actor) initHandler() (rev any) {
+	defer func() {
+		rev = recover()
+	}()
+
+	p.handler.OnInit()
+	return nil
+}
+
 func (p *Actor) onStop() {
-	cutils.Try(func() {
-		close(p.close)
+	defer close(p.exited)
 
+	if p.abandoned.Load() {
+		p.onAbandonedStop()
+		return
+	}
+
+	cutils.Try(func() {
 		if p.path.IsParent() {
 			p.system.removeActor(p.ActorID())
 			p.child.onStop()
@@ -290,15 +446,35 @@ func (p *Actor) onStop() {
 		p.handler.OnStop()
 		p.timer.onStop()
 		p.event.onStop()
+
+		// the actor has left the system, the next message creates it again
+		if p.passivated.Load() {
+			p.redeliver()
+		}
+
 		p.localMail.onStop()
 		p.remoteMail.onStop()
 	}, func(errString string) {
-		clog.Error(errString)
+		p.logger().Error(errString)
 	})
 
 	p.system.wg.Done()
 }
 
+func (s State) String() string {
+	switch s {
+	case InitState:
+		return "init"
+	case WorkerState:
+		return "running"
+	case FreeState:
+		return "free"
+	case StopState:
+		return "stopping"
+	}
+	return "unknown"
+}
+
 func (p *Actor) State() State {
 	return State(p.state.Load())
 }
@@ -311,6 +487,10 @@ func (p *Actor) App() cfacade.IApplication {
 	return p.system.app
 }
 
+func (p *Actor) logger() cfacade.ILogger {
+	return p.system.logger()
+}
+
 func (p *Actor) ActorID() string {
 	if p.path.IsChild() {
 		return p.path.ChildID
@@ -327,16 +507,80 @@ func (p *Actor) PathString() string {
 	return p.path.String()
 }
 
+// Call sends a message without reply. It inherits the deadline and trace of the message being processed.
 func (p *Actor) Call(targetPath, funcName string, arg any) int32 {
-	return p.system.Call(p.path.String(), targetPath, funcName, arg)
+	return p.system.call(p.origin, p.path.String(), targetPath, funcName, arg)
 }
 
+// CallWait sends a message and waits for reply. It inherits the deadline and trace of the message being processed.
 func (p *Actor) CallWait(targetPath, funcName string, arg, reply any) int32 {
-	return p.system.CallWait(p.path.String(), targetPath, funcName, arg, reply)
+	return p.system.callWait(context.Background(), p.origin, p.path.String(), targetPath, funcName, arg, reply, nil)
+}
+
+// CallWaitError is CallWait returning nil on success, otherwise a
+// *cherryError.CodeError with the message and details replied by the target.
+func (p *Actor) CallWaitError(targetPath, funcName string, arg, reply any) error {
+	failure := &cerror.CodeError{}
+	code := p.system.callWait(context.Background(), p.origin, p.path.String(), targetPath, funcName, arg, reply, failure)
+	return callError(code, failure)
+}
+
+// Context returns a context that expires at the deadline of the message being
+// processed, or context.Background() when the message has no deadline.
+// It must only be used while the message is processed.
+func (p *Actor) Context() context.Context {
+	if p.origin.deadline <= 0 {
+		return context.Background()
+	}
+
+	if p.ctx == nil {
+		p.ctx, p.cancel = context.WithDeadline(context.Background(), time.UnixMilli(p.origin.deadline))
+	}
+
+	return p.ctx
+}
+
+// Deadline returns the deadline of the message being processed.
+// ok is false when the message has no deadline.
+func (p *Actor) Deadline() (deadline time.Time, ok bool) {
+	if p.origin.deadline <= 0 {
+		return time.Time{}, false
+	}
+
+	return time.UnixMilli(p.origin.deadline), true
 }
 
+func (p *Actor) resetOrigin() {
+	p.origin = callOrigin{}
+
+	if p.cancel != nil {
+		p.cancel()
+		p.ctx = nil
+		p.cancel = nil
+	}
+}
+
+// CallAsync sends a request without blocking this actor. When the call
+// completes, reply is filled and callback runs on this actor's goroutine with
+// the cherryCode of the call, e.g. ActorCallTimeout. arg and reply must not be
+// used until callback runs.
+func (p *Actor) CallAsync(targetPath, funcName string, arg, reply any, callback func(code int32)) {
+	source := p.path.String()
+	origin := p.origin
+
+	go func() {
+		code := p.system.callWait(context.Background(), origin, source, targetPath, funcName, arg, reply, nil)
+		if callback != nil {
+			p.runTask(func() {
+				callback(code)
+			})
+		}
+	}()
+}
+
+// CallType is System.CallType sent by this actor, actorID is the balancing key.
 func (p *Actor) CallType(nodeType, actorID, funcName string, arg any) int32 {
-	return p.system.CallType(nodeType, actorID, funcName, arg)
+	return p.system.callType(p.origin, p.path.String(), actorID, nodeType, actorID, funcName, arg)
 }
 
 // LastAt second
@@ -344,11 +588,16 @@ func (p *Actor) LastAt() int64 {
 	return p.lastAt
 }
 
+// Exit stops the actor once it has processed the queued messages. It does not
+// block, one pending exit is enough and an exit after the stop is ignored.
 func (p *Actor) Exit() {
-	p.close <- struct{}{}
+	select {
+	case p.close <- struct{}{}:
+	default:
+	}
 
 	if clog.PrintLevel(zapcore.DebugLevel) {
-		clog.Debugf("[Exit] path=%s", p.path)
+		p.logger().Debugf("[Exit] path=%s", p.path)
 	}
 }
 
@@ -377,13 +626,53 @@ func (p *Actor) Timer() ITimer {
 }
 
 func (p *Actor) PostRemote(m *cfacade.Message) {
-	m.AddRef()
-	p.remoteMail.Push(m)
+	p.post(p.remoteMail, m)
 }
 
 func (p *Actor) PostLocal(m *cfacade.Message) {
+	p.post(p.localMail, m)
+}
+
+// QueueDepth returns the number of messages waiting in the local, remote and event queues.
+func (p *Actor) QueueDepth() (local, remote, event int32) {
+	return p.localMail.Count(), p.remoteMail.Count(), p.event.Count()
+}
+
+// post pushes m into the mailbox. It returns false when m is rejected by a full mailbox.
+func (p *Actor) post(mb *mailbox, m *cfacade.Message) bool {
 	m.AddRef()
-	p.localMail.Push(m)
+
+	value, policy, overflow := mb.offer(m)
+	if !overflow {
+		return true
+	}
+
+	if dropped, ok := value.(*cfacade.Message); ok {
+		p.onOverflow(mb.name, dropped, policy)
+	}
+
+	return policy != Reject
+}
+
+// onOverflow releases a message that did not fit in the mailbox. Waiting
+// callers are answered with ActorMailboxFull instead of timing out.
+func (p *Actor) onOverflow(name string, m *cfacade.Message, policy OverflowPolicy) {
+	p.logger().Warnf("[%s] %s mailbox is full. policy=%s source=%s target=%s func=%s",
+		p.path,
+		name,
+		policy,
+		m.Source,
+		m.Target,
+		m.FuncName,
+	)
+
+	if policy == DeadLetter {
+		p.system.undeliveredMessage(m, DeadLetterMailboxFull, ccode.ActorMailboxFull)
+	} else {
+		replyCode(p.App(), m, ccode.ActorMailboxFull)
+	}
+
+	m.Recycle()
 }
 
 func (p *Actor) PostEvent(data cfacade.IEventData) {
@@ -404,10 +693,14 @@ func newActor(actorID, childID string, handler cfacade.IActorHandler, c *System)
 		},
 		system:  c,
 		close:   make(chan struct{}, 1),
+		exited:  make(chan struct{}),
 		handler: handler,
+		kind:    strings.TrimPrefix(fmt.Sprintf("%T", handler), "*"),
 		lastAt:  time.Now().UnixMilli(),
 	}
 
+	thisActor.factory, _ = handler.(IActorFactory)
+
 	// Default init state
 	thisActor.setState(InitState)
 
@@ -426,6 +719,9 @@ func newActor(actorID, childID string, handler cfacade.IActorHandler, c *System)
 	timer := newTimer(&thisActor)
 	thisActor.timer = &timer
 
+	tasks := newQueue()
+	thisActor.tasks = &tasks
+
 	// spawn load!
 	actorLoad, ok := handler.(IActorLoader)
 	if ok {
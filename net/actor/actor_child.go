@@ -10,7 +10,8 @@ import (
 
 type actorChild struct {
 	thisActor   *Actor
-	childActors *sync.Map // key:childActorID, value:*actor
+	childActors *sync.Map            // key:childActorID, value:*actor
+	factory     cfacade.ActorFactory // creates unknown children on their first message
 }
 
 func newChild(thisActor *Actor) actorChild {
@@ -50,12 +51,39 @@ func (p *actorChild) Create(childID string, handler cfacade.IActorHandler) (cfac
 		return nil, err
 	}
 
+	childActor.parentSupervisor = p.thisActor.supervisor()
+
 	p.childActors.Store(childID, childActor)
 	go childActor.run()
 
 	return childActor, nil
 }
 
+// SetFactory 设置子Actor工厂,消息的目标子Actor不存在时通过工厂创建
+func (p *actorChild) SetFactory(factory cfacade.ActorFactory) {
+	p.factory = factory
+}
+
+// activate creates the child through the factory.
+func (p *actorChild) activate(childID string) (cfacade.IActor, bool) {
+	if p.factory == nil {
+		return nil, false
+	}
+
+	handler, found := p.factory(childID)
+	if !found || handler == nil {
+		return nil, false
+	}
+
+	childActor, err := p.Create(childID, handler)
+	if err != nil {
+		p.thisActor.logger().Warnf("[activate] create child actor fail. [childID = %s, err = %v]", childID, err)
+		return nil, false
+	}
+
+	return childActor, true
+}
+
 func (p *actorChild) Get(childID string) (cfacade.IActor, bool) {
 	return p.GetActor(childID)
 }
@@ -0,0 +1,102 @@
+package cherryActor
+
+import (
+	cutils "github.com/cherry-game/cherry/extend/utils"
+	cfacade "github.com/cherry-game/cherry/facade"
+)
+
+/**
+- Undeliverable messages and events are handed to the dead-letter actor set
+  with System.SetDeadLetterActor, whose handler implements IDeadLetter.
+	- OnDeadLetter runs on the goroutine of the dead-letter actor.
+	- Callers waiting for a reply are answered with an error code first, so
+	  remote callers do not time out.
+- Without a dead-letter actor they are only logged.
+*/
+
+const (
+	DeadLetterActorNotFound = "actor not found"       // no actor and no factory for the target
+	DeadLetterChildNotFound = "child actor not found" // no child and no child factory for the target
+	DeadLetterFuncNotFound  = "function not found"    // function not registered on the target mailbox
+	DeadLetterEventNotFound = "event not found"       // event not registered on the subscribed actor
+	DeadLetterMailboxFull   = "mailbox full"          // rejected by a full queue with the DeadLetter policy
+	DeadLetterActorStopped  = "actor stopped"         // the target stopped and no longer accepts messages
+)
+
+// DeadLetterMessage describes an undeliverable message or event.
+type DeadLetterMessage struct {
+	Reason   string             // why it was not delivered, one of the DeadLetter* reasons
+	Source   string             // source actor path, empty for events
+	Target   string             // target actor path
+	FuncName string             // function name, or the event name
+	Code     int32              // code replied to the caller, OK if nobody waits for a reply
+	Message  *cfacade.Message   // copy of the message, owned by the handler. nil for events
+	Event    cfacade.IEventData // the event. nil for messages
+}
+
+// SetDeadLetterActor routes undeliverable messages and events to the actor
+// actorID, an empty actorID disables the routing.
+func (p *System) SetDeadLetterActor(actorID string) {
+	p.deadLetterActor = actorID
+}
+
+// undeliveredMessage answers the caller of m with code and hands m to the dead-letter actor.
+func (p *System) undeliveredMessage(m *cfacade.Message, reason string, code int32) {
+	if code != 0 {
+		replyCode(p.app, m, code)
+	}
+
+	if p.deadLetterActor == "" {
+		return
+	}
+
+	p.undelivered(&DeadLetterMessage{
+		Reason:   reason,
+		Source:   m.Source,
+		Target:   m.Target,
+		FuncName: m.FuncName,
+		Code:     code,
+		Message:  m.Clone(),
+	})
+}
+
+// undeliveredEvent hands data to the dead-letter actor.
+func (p *System) undeliveredEvent(target *cfacade.ActorPath, data cfacade.IEventData, reason string) {
+	if p.deadLetterActor == "" {
+		return
+	}
+
+	p.undelivered(&DeadLetterMessage{
+		Reason:   reason,
+		Target:   target.String(),
+		FuncName: data.Name(),
+		Event:    data,
+	})
+}
+
+func (p *System) undelivered(letter *DeadLetterMessage) {
+	thisActor, found := p.GetActor(p.deadLetterActor)
+	if !found {
+		p.logger().Warnf("[deadLetter] dead-letter actor not found. [actorID = %s, reason = %s, target = %s -> %s]",
+			p.deadLetterActor,
+			letter.Reason,
+			letter.Target,
+			letter.FuncName,
+		)
+		return
+	}
+
+	thisActor.runTask(func() {
+		handler, ok := thisActor.handler.(IDeadLetter)
+		if !ok {
+			p.logger().Warnf("[deadLetter] handler does not implement IDeadLetter. [path = %s]", thisActor.path)
+			return
+		}
+
+		cutils.Try(func() {
+			handler.OnDeadLetter(letter)
+		}, func(errString string) {
+			p.logger().Errorf("[%s] dead letter invoke error. err = %s", thisActor.path, errString)
+		})
+	})
+}
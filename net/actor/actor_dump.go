@@ -0,0 +1,120 @@
+package cherryActor
+
+import (
+	"sort"
+	"time"
+)
+
+type (
+	// ActorInfo is a snapshot of an actor for introspection.
+	ActorInfo struct {
+		Path     string           `json:"path"`
+		State    string           `json:"state"`              // State name, "passivating" for a passivated actor not yet stopped
+		Local    []string         `json:"local,omitempty"`    // registered local function names
+		Remote   []string         `json:"remote,omitempty"`   // registered remote function names
+		Events   []string         `json:"events,omitempty"`   // subscribed event names
+		Mailbox  map[string]int32 `json:"mailbox"`            // queued messages, key:mailbox name
+		Children []*ActorInfo     `json:"children,omitempty"` // child actors
+	}
+
+	actorNames struct {
+		local  []string
+		remote []string
+		events []string
+	}
+)
+
+// Dump returns a snapshot of the actor tree. Function and event names are read
+// on the goroutine of each actor; actors that do not answer within timeout,
+// e.g. a stuck handler, are reported without them.
+func (p *System) Dump(timeout time.Duration) []*ActorInfo {
+	var (
+		list    []*ActorInfo
+		all     []*ActorInfo
+		results []chan actorNames
+	)
+
+	snapshot := func(thisActor *Actor) *ActorInfo {
+		local, remote, event := thisActor.QueueDepth()
+		info := &ActorInfo{
+			Path:  thisActor.PathString(),
+			State: stateName(thisActor),
+			Mailbox: map[string]int32{
+				LocalName:  local,
+				RemoteName: remote,
+				EventName:  event,
+			},
+		}
+
+		result := make(chan actorNames, 1)
+		thisActor.runTask(func() {
+			result <- actorNames{
+				local:  sortedKeys(thisActor.localMail.funcMap),
+				remote: sortedKeys(thisActor.remoteMail.funcMap),
+				events: sortedKeys(thisActor.event.funcMap),
+			}
+		})
+
+		all = append(all, info)
+		results = append(results, result)
+		return info
+	}
+
+	p.actorMap.Range(func(_, value any) bool {
+		thisActor := value.(*Actor)
+		info := snapshot(thisActor)
+
+		thisActor.child.childActors.Range(func(_, child any) bool {
+			info.Children = append(info.Children, snapshot(child.(*Actor)))
+			return true
+		})
+
+		list = append(list, info)
+		return true
+	})
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for i, result := range results {
+		select {
+		case names := <-result:
+			all[i].Local, all[i].Remote, all[i].Events = names.local, names.remote, names.events
+		case <-timer.C:
+			return sortInfos(list)
+		}
+	}
+
+	return sortInfos(list)
+}
+
+// stateName returns the name of the state of thisActor for the dump.
+func stateName(thisActor *Actor) string {
+	state := thisActor.State()
+	if state == StopState && thisActor.passivated.Load() {
+		return "passivating"
+	}
+	return state.String()
+}
+
+func sortInfos(list []*ActorInfo) []*ActorInfo {
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Path < list[j].Path
+	})
+
+	for _, info := range list {
+		sortInfos(info.Children)
+	}
+
+	return list
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
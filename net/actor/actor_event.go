@@ -2,7 +2,6 @@ package cherryActor
 
 import (
 	cfacade "github.com/cherry-game/cherry/facade"
-	clog "github.com/cherry-game/cherry/logger"
 )
 
 type (
@@ -47,7 +46,22 @@ func (p *actorEvent) Unregister(name string) {
 }
 
 func (p *actorEvent) Push(data cfacade.IEventData) {
-	p.queue.Push(data)
+	value, policy, overflow := p.queue.offer(data)
+	if !overflow {
+		return
+	}
+
+	p.thisActor.logger().Warnf("[%s] event queue is full. policy=%s value=%+v",
+		p.thisActor.Path(),
+		policy,
+		value,
+	)
+
+	if policy == DeadLetter {
+		if eventData, ok := value.(cfacade.IEventData); ok {
+			p.thisActor.system.undeliveredEvent(p.thisActor.path, eventData, DeadLetterMailboxFull)
+		}
+	}
 }
 
 func (p *actorEvent) Pop() cfacade.IEventData {
@@ -58,7 +72,7 @@ func (p *actorEvent) Pop() cfacade.IEventData {
 
 	eventData, ok := v.(cfacade.IEventData)
 	if !ok {
-		clog.Warnf("Convert to IEventData fail. v = %+v", v)
+		p.thisActor.logger().Warnf("Convert to IEventData fail. v = %+v", v)
 		return nil
 	}
 
@@ -68,20 +82,22 @@ func (p *actorEvent) Pop() cfacade.IEventData {
 func (p *actorEvent) invokeFunc(data cfacade.IEventData) {
 	funcList, found := p.funcMap[data.Name()]
 	if !found {
-		clog.Warnf("[%s] Event not found. [data = %+v]",
+		p.thisActor.logger().Warnf("[%s] Event not found. [data = %+v]",
 			p.thisActor.Path(),
 			data,
 		)
+		p.thisActor.system.undeliveredEvent(p.thisActor.path, data, DeadLetterEventNotFound)
 		return
 	}
 
 	defer func() {
 		if rev := recover(); rev != nil {
-			clog.Errorf("[%s] Event invoke error. [data = %+v, err = %v]",
+			p.thisActor.logger().Errorf("[%s] Event invoke error. [data = %+v, err = %v]",
 				p.thisActor.Path(),
 				data,
 				rev,
 			)
+			p.thisActor.onFailure(rev, Resume)
 		}
 	}()
 
@@ -90,6 +106,12 @@ func (p *actorEvent) invokeFunc(data cfacade.IEventData) {
 	}
 }
 
+// reset 注销所有事件
+func (p *actorEvent) reset() {
+	p.thisActor.system.removeActorEvent(p.thisActor.PathString(), p.EventNames()...)
+	p.funcMap = make(map[string][]IEventFunc)
+}
+
 func (p *actorEvent) onStop() {
 	// remove event names
 	p.thisActor.system.removeActorEvent(p.thisActor.PathString(), p.EventNames()...)
@@ -0,0 +1,96 @@
+package cherryActor
+
+import (
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cfacade "github.com/cherry-game/cherry/facade"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+/**
+- Forward moves a received message to another actor, e.g. to the new owner
+  of a sharded entity, on behalf of the original sender.
+	- Local (client) messages stay local messages and keep their session.
+	- A caller waiting for the reply of the message receives the reply of the
+	  new target. Cross-node, the reply is relayed by this node.
+	- Message.Hops counts the forwards, so that a receiver can stop a
+	  forwarding loop.
+*/
+
+// ForwardLocal sends m, a message of the local mailbox, to target.
+func (p *Actor) ForwardLocal(m *cfacade.Message, target string) int32 {
+	return p.system.forward(m, target, true)
+}
+
+// ForwardRemote sends m, a message of the remote mailbox, to target.
+func (p *Actor) ForwardRemote(m *cfacade.Message, target string) int32 {
+	return p.system.forward(m, target, false)
+}
+
+func (p *System) forward(m *cfacade.Message, target string, local bool) int32 {
+	targetPath, err := cfacade.ToActorPath(target)
+	if err != nil {
+		p.logger().Warnf("[Forward] Target path error. [source = %s, target = %s, err = %v]", m.Source, target, err)
+		return ccode.ActorConvertPathError
+	}
+
+	fwd := m.Clone()
+	fwd.Target = target
+	fwd.Hops = m.Hops + 1
+
+	if targetPath.NodeID == "" || targetPath.NodeID == p.NodeID() {
+		if local {
+			return p.postLocal(fwd)
+		}
+		return p.postRemote(fwd)
+	}
+
+	// cross-node messages carry their args as bytes
+	if _, ok := fwd.Args.([]byte); !ok && fwd.Args != nil {
+		argsBytes, errCode := p.marshalArg(fwd.Args)
+		if ccode.IsFail(errCode) {
+			fwd.Recycle()
+			return errCode
+		}
+		fwd.Args = argsBytes
+	}
+
+	// PublishLocal, PublishRemote and RequestRemote recycle fwd via defer on all paths.
+	if local {
+		if err = p.app.Cluster().PublishLocal(targetPath.NodeID, fwd); err != nil {
+			p.logger().Warnf("[Forward] Publish local fail. [source = %s, target = %s, err = %v]", m.Source, target, err)
+			return ccode.ActorPublishRemoteError
+		}
+		return ccode.OK
+	}
+
+	if m.ChanResult == nil && m.Reply == "" {
+		if err = p.app.Cluster().PublishRemote(targetPath.NodeID, fwd); err != nil {
+			p.logger().Warnf("[Forward] Publish remote fail. [source = %s, target = %s, err = %v]", m.Source, target, err)
+			return ccode.ActorPublishRemoteError
+		}
+		return ccode.OK
+	}
+
+	timeout := p.callTimeout
+	if m.Deadline > 0 {
+		timeout = time.Until(time.UnixMilli(m.Deadline))
+	}
+
+	// relay the reply without blocking the forwarding actor
+	waiter := m.Clone()
+	fwd.ReqID, fwd.Reply, fwd.ChanResult = "", "", nil
+
+	go func() {
+		defer waiter.Recycle()
+
+		rsp, code := p.requestRemote(targetPath.NodeID, fwd, timeout)
+		if rsp == nil {
+			rsp = &cproto.Response{Code: code}
+		}
+		replyTo(p.app, waiter, rsp)
+	}()
+
+	return ccode.OK
+}
@@ -7,16 +7,18 @@ import (
 )
 
 type mailbox struct {
-	queue                                 // queue
-	name    string                        // 邮箱名
-	funcMap map[string]*creflect.FuncInfo // 已注册的函数
+	queue                                  // queue
+	name     string                        // 邮箱名
+	funcMap  map[string]*creflect.FuncInfo // 已注册的函数
+	typedMap map[string]typedFunc          // 泛型注册的函数,不经过反射调用
 }
 
 func newMailbox(name string) mailbox {
 	return mailbox{
-		queue:   newQueue(),
-		name:    name,
-		funcMap: make(map[string]*creflect.FuncInfo),
+		queue:    newQueue(),
+		name:     name,
+		funcMap:  make(map[string]*creflect.FuncInfo),
+		typedMap: make(map[string]typedFunc),
 	}
 }
 
@@ -66,10 +68,19 @@ func (p *mailbox) Push(m *cfacade.Message) {
 	}
 }
 
-func (p *mailbox) onStop() {
+// reset 清除已注册的函数
+func (p *mailbox) reset() {
 	for key := range p.funcMap {
 		delete(p.funcMap, key)
 	}
 
+	for key := range p.typedMap {
+		delete(p.typedMap, key)
+	}
+}
+
+func (p *mailbox) onStop() {
+	p.reset()
+
 	p.queue.Destroy()
 }
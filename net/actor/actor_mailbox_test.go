@@ -0,0 +1,100 @@
+package cherryActor
+
+import (
+	"fmt"
+	"testing"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cfacade "github.com/cherry-game/cherry/facade"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+func newTestMessage(funcName string) *cfacade.Message {
+	m := cfacade.GetMessage()
+	m.Source = ".test"
+	m.Target = ".mailbox"
+	m.FuncName = funcName
+	m.ChanResult = make(chan interface{}, 1)
+	return m
+}
+
+func expectMailboxFull(t *testing.T, chanResult chan interface{}) {
+	select {
+	case result := <-chanResult:
+		rsp, ok := result.(*cproto.Response)
+		if !ok || rsp.Code != ccode.ActorMailboxFull {
+			t.Fatalf("expected mailbox full, got %+v", result)
+		}
+	default:
+		t.Fatal("expected a reply for the overflowed message")
+	}
+}
+
+// testDeadLetterActor collects the dead letters.
+type testDeadLetterActor struct {
+	Base
+	letters []*DeadLetterMessage
+}
+
+func (p *testDeadLetterActor) OnDeadLetter(letter *DeadLetterMessage) {
+	p.letters = append(p.letters, letter)
+}
+
+// TestMailbox_Overflow verifies each overflow policy of a bounded mailbox.
+func TestMailbox_Overflow(t *testing.T) {
+	system := NewSystem()
+
+	deadLetter := &testDeadLetterActor{}
+	deadLetterActor, _ := newActor("dead_letter", "", deadLetter, system)
+	system.actorMap.Store("dead_letter", deadLetterActor)
+	system.SetDeadLetterActor("dead_letter")
+
+	tests := []struct {
+		policy    OverflowPolicy
+		accepted  bool
+		overflow  int    // index of the message that is not delivered
+		firstFunc string // first message left in the mailbox
+	}{
+		{Reject, false, 2, "f0"},
+		{DropNewest, true, 2, "f0"},
+		{DropOldest, true, 0, "f1"},
+		{DeadLetter, true, 2, "f0"},
+	}
+
+	for _, tt := range tests {
+		thisActor, _ := newActor("mailbox", "", &testActor{}, system)
+		thisActor.remoteMail.SetCapacity(2, tt.policy)
+
+		var chanResults []chan interface{}
+		var accepted bool
+		for i := 0; i < 3; i++ {
+			m := newTestMessage(fmt.Sprintf("f%d", i))
+			chanResults = append(chanResults, m.ChanResult)
+			accepted = thisActor.post(thisActor.remoteMail, m)
+		}
+
+		if accepted != tt.accepted {
+			t.Fatalf("[%s] expected accepted %v, got %v", tt.policy, tt.accepted, accepted)
+		}
+
+		expectMailboxFull(t, chanResults[tt.overflow])
+
+		if _, remote, _ := thisActor.QueueDepth(); remote != 2 {
+			t.Fatalf("[%s] expected depth 2, got %d", tt.policy, remote)
+		}
+
+		if m := thisActor.remoteMail.Pop(); m.FuncName != tt.firstFunc {
+			t.Fatalf("[%s] expected %s, got %s", tt.policy, tt.firstFunc, m.FuncName)
+		}
+	}
+
+	// the letters are handed over as tasks of the dead-letter actor
+	for task, ok := deadLetterActor.tasks.Pop().(func()); ok; task, ok = deadLetterActor.tasks.Pop().(func()) {
+		task()
+	}
+
+	letters := deadLetter.letters
+	if len(letters) != 1 || letters[0].Reason != DeadLetterMailboxFull || letters[0].FuncName != "f2" {
+		t.Fatalf("expected one dead letter for f2, got %+v", letters)
+	}
+}
@@ -0,0 +1,190 @@
+package cherryActor
+
+import (
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cutils "github.com/cherry-game/cherry/extend/utils"
+	cfacade "github.com/cherry-game/cherry/facade"
+	clog "github.com/cherry-game/cherry/logger"
+	"go.uber.org/zap/zapcore"
+)
+
+/**
+- An actor with an idle TTL is passivated once it has received no message for longer than the TTL.
+	- Actors with children or queued messages are not idle.
+	- IPassivate.OnPassivate is called first so the handler can persist its state.
+	- The actor then stops; messages that arrived meanwhile are parked in its
+	  mailbox and redelivered once it has left the system, senders do not wait.
+	- Handoff stops the actor the same way and forwards those messages to the
+	  actor that replaces it, e.g. on another node.
+- A message to an unknown actor is delivered to a new actor created by the
+  factory set with System.SetFactory, or IActorChild.SetFactory for children.
+*/
+
+// SetIdleTTL 设置空闲超时时间,超时后Actor被回收,ttl<=0表示不回收.一般在OnInit中调用
+func (p *Actor) SetIdleTTL(ttl time.Duration) {
+	if p.idleTimer != nil {
+		p.idleTimer.Stop()
+		p.idleTimer = nil
+	}
+
+	p.idleTTL = ttl
+	if ttl <= 0 {
+		return
+	}
+
+	p.idleTimer = p.timer.Add(ttl/2, p.checkIdle)
+}
+
+// IdleTTL 返回空闲超时时间
+func (p *Actor) IdleTTL() time.Duration {
+	return p.idleTTL
+}
+
+// Passivate 在Actor的goroutine上回收Actor,after在回收后调用(可为nil)
+func (p *Actor) Passivate(after func()) {
+	p.runTask(func() {
+		if p.stopped {
+			return
+		}
+
+		p.passivate()
+		if after != nil {
+			after()
+		}
+	})
+}
+
+// Handoff 在Actor的goroutine上回收Actor,队列中的消息转发给target(可为逻辑路径),而不是在本节点重投
+func (p *Actor) Handoff(target string) {
+	p.runTask(func() {
+		if p.stopped {
+			return
+		}
+
+		p.handoff = target
+		p.passivate()
+	})
+}
+
+// Exited returns a channel closed once the actor has stopped and left the system.
+func (p *Actor) Exited() <-chan struct{} {
+	return p.exited
+}
+
+func (p *Actor) checkIdle() {
+	if p.idleTTL <= 0 || p.State() != WorkerState {
+		return
+	}
+
+	if time.Now().UnixMilli()-p.lastAt < p.idleTTL.Milliseconds() {
+		return
+	}
+
+	if p.queued() > 0 {
+		return
+	}
+
+	hasChild := false
+	p.child.childActors.Range(func(_, _ any) bool {
+		hasChild = true
+		return false
+	})
+
+	if hasChild {
+		return
+	}
+
+	p.passivate()
+}
+
+func (p *Actor) passivate() {
+	if handler, ok := p.handler.(IPassivate); ok {
+		cutils.Try(handler.OnPassivate, func(errString string) {
+			p.logger().Errorf("[%s] passivate error. err = %s", p.path, errString)
+		})
+	}
+
+	p.passivated.Store(true)
+	p.stopped = true
+	p.setState(StopState)
+
+	if clog.PrintLevel(zapcore.DebugLevel) {
+		p.logger().Debugf("[passivate] path=%s", p.path)
+	}
+}
+
+// park queues m in mb while the actor is passivating, redeliver passes it on.
+// Once the queued messages were redelivered, m is posted again with repost
+// and reaches the actor created by the factory.
+func (p *Actor) park(mb *mailbox, m *cfacade.Message, repost func(m *cfacade.Message) int32) int32 {
+	p.parkMu.Lock()
+	if p.redelivered {
+		p.parkMu.Unlock()
+		return repost(m)
+	}
+	defer p.parkMu.Unlock()
+
+	if !p.post(mb, m) {
+		return ccode.ActorMailboxFull
+	}
+
+	return ccode.OK
+}
+
+// redeliver posts the messages left in the mailboxes of a passivated actor
+// again, so they reach the actor created by the factory, or forwards them to
+// the handoff target. Call it once the actor has left the system.
+func (p *Actor) redeliver() {
+	p.parkMu.Lock()
+	p.redelivered = true
+	p.parkMu.Unlock()
+
+	if p.handoff != "" {
+		p.forwardQueued()
+		return
+	}
+
+	for m := p.remoteMail.Pop(); m != nil; m = p.remoteMail.Pop() {
+		// postRemote only keeps the reference of m when it was queued or overflowed
+		if code := p.system.postRemote(m); code == ccode.OK || code == ccode.ActorMailboxFull {
+			m.Recycle()
+		}
+	}
+
+	for m := p.localMail.Pop(); m != nil; m = p.localMail.Pop() {
+		if code := p.system.postLocal(m); code == ccode.OK || code == ccode.ActorMailboxFull {
+			m.Recycle()
+		}
+	}
+}
+
+// accepting reports whether the actor accepts new messages.
+// Messages posted before OnInit completes wait in the mailbox.
+func (p *Actor) accepting() bool {
+	state := p.State()
+	return state == InitState || state == WorkerState
+}
+
+// forwardQueued forwards the messages left in the mailboxes to the handoff
+// target, messages of a child go to the same child of the target.
+func (p *Actor) forwardQueued() {
+	handoff := p.system.resolve(p.handoff)
+	target := func(m *cfacade.Message) string {
+		if targetPath := m.TargetPath(); targetPath != nil && targetPath.IsChild() {
+			return cfacade.NewPath(handoff, targetPath.ChildID)
+		}
+		return handoff
+	}
+
+	for m := p.remoteMail.Pop(); m != nil; m = p.remoteMail.Pop() {
+		p.system.forward(m, target(m), false)
+		m.Recycle()
+	}
+
+	for m := p.localMail.Pop(); m != nil; m = p.localMail.Pop() {
+		p.system.forward(m, target(m), true)
+		m.Recycle()
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"time"
 
 	ctimeWheel "github.com/cherry-game/cherry/extend/time_wheel"
-	clog "github.com/cherry-game/cherry/logger"
 )
 
 type (
@@ -40,7 +39,7 @@ func (p *actorTimer) Pop() uint64 {
 
 	timerID, ok := v.(uint64)
 	if !ok {
-		clog.Warnf("Convert to Timer ID fail. v = %+v", v)
+		p.thisActor.logger().Warnf("Convert to Timer ID fail. v = %+v", v)
 		return 0
 	}
 
@@ -98,7 +97,7 @@ func (p *actorTimer) AddSchedule(s ITimerSchedule, fn func()) ITimerHandle {
 	})
 
 	if timer == nil {
-		clog.Warnf("Build schedule fail. ITimerSchedule = %+v, fn = %+v", s, fn)
+		p.thisActor.logger().Warnf("Build schedule fail. ITimerSchedule = %+v, fn = %+v", s, fn)
 		return nil
 	}
 
@@ -128,7 +127,7 @@ func (p *actorTimer) RemoveAll() {
 // unstarted.
 func (p *actorTimer) newTimerHandle(delay time.Duration, fn func(), once bool) ITimerHandle {
 	if delay < ctimeWheel.DefaultTick || fn == nil {
-		clog.Warnf("[Timer] parameter error. delay = %+v", delay)
+		p.thisActor.logger().Warnf("[Timer] parameter error. delay = %+v", delay)
 		return nil
 	}
 	var t ITimerHandle
@@ -151,11 +150,12 @@ func (p *actorTimer) invokeFunc(timerID uint64) {
 
 	defer func() {
 		if rev := recover(); rev != nil {
-			clog.Errorf("[%s] Timer invoke error. [timerID = %d, err = %+v]",
+			p.thisActor.logger().Errorf("[%s] Timer invoke error. [timerID = %d, err = %+v]",
 				p.thisActor.Path(),
 				timerID,
 				rev,
 			)
+			p.thisActor.onFailure(rev, Resume)
 		}
 	}()
 
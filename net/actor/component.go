@@ -1,6 +1,8 @@
 package cherryActor
 
 import (
+	"context"
+
 	cfacade "github.com/cherry-game/cherry/facade"
 )
 
@@ -24,6 +26,8 @@ func (c *Component) Name() string {
 	return Name
 }
 
+// Init starts the actor system. The trace, metrics, balancer and admin
+// components attach themselves to it in their OnAfterInit.
 func (c *Component) Init() {
 	c.System.Start(c.App())
 }
@@ -35,6 +39,11 @@ func (c *Component) OnAfterInit() {
 	}
 }
 
+// OnDrain waits for the actors to finish their queued messages and in-flight calls.
+func (c *Component) OnDrain(ctx context.Context) {
+	c.System.Drain(ctx)
+}
+
 func (c *Component) OnStop() {
 	c.System.Stop()
 }
@@ -42,3 +51,9 @@ func (c *Component) OnStop() {
 func (c *Component) Add(actors ...cfacade.IActorHandler) {
 	c.actorHandlers = append(c.actorHandlers, actors...)
 }
+
+// Find returns the actor component of app, or nil.
+func Find(app cfacade.IApplication) *Component {
+	component, _ := app.ActorSystem().(*Component)
+	return component
+}
@@ -13,4 +13,5 @@ var (
 const (
 	LocalName  = "local"
 	RemoteName = "remote"
+	EventName  = "event"
 )
@@ -11,6 +11,63 @@ type (
 	IActorLoader interface {
 		load(actor *Actor)
 	}
+
+	// ISupervised 实现该接口的Actor使用返回的监督策略处理自身及未配置策略的子Actor的失败
+	ISupervised interface {
+		Supervisor() *Supervisor
+	}
+
+	// IActorFactory 实现该接口的Actor在Restart时使用新创建的handler
+	IActorFactory interface {
+		NewHandler() cfacade.IActorHandler
+	}
+
+	// IPassivate 实现该接口的Actor在空闲超时或调用Passivate被回收前触发OnPassivate,用于保存状态
+	IPassivate interface {
+		OnPassivate()
+	}
+
+	// IChildFailed 实现该接口的父Actor在子Actor失败时收到通知
+	IChildFailed interface {
+		OnChildFailed(childID string, reason any, directive Directive)
+	}
+
+	// IDeadLetter 死信Actor实现该接口,接收无法投递的消息与事件
+	IDeadLetter interface {
+		OnDeadLetter(letter *DeadLetterMessage)
+	}
+)
+
+// The tracer, metrics and balancer are attached by their components, see
+// System.SetTracer, System.SetMetrics and System.SetBalancer.
+type (
+	ITracer interface {
+		Start(traceID, parentID, name string) ISpan // start a span, nil if the trace is not recorded
+	}
+
+	ISpan interface {
+		GetSpanID() string         // id of the span, parent of the spans of the calls it makes
+		SetAttr(key, value string) // set an attribute
+		Finish(code int32)         // end the span with the cherryCode of the operation
+	}
+
+	IMetrics interface {
+		Histogram(name, help string, buckets []float64, labelNames ...string) IHistogram
+		Counter(name, help string, labelNames ...string) ICounter
+		GaugeFunc(name, help string, labelNames []string, collect func(set func(value float64, labelValues ...string)))
+	}
+
+	IHistogram interface {
+		Observe(value float64, labelValues ...string)
+	}
+
+	ICounter interface {
+		Inc(labelValues ...string)
+	}
+
+	IMemberSelector interface {
+		Select(nodeType, key string) (cfacade.IMember, bool) // member of nodeType for key, false if nodeType has no strategy
+	}
 )
 
 type (
@@ -18,6 +75,8 @@ type (
 		Register(name string, fn IEventFunc, uniqueID ...int64)     // register event
 		Registers(names []string, fn IEventFunc, uniqueID ...int64) // register multiple events
 		Unregister(name string)                                     // unregister event
+		SetCapacity(capacity int32, policy OverflowPolicy)          // soft limit of queued events, capacity<1 means unbounded
+		Count() int32                                               // number of queued events
 	}
 
 	IEventFunc func(cfacade.IEventData) // event handler
@@ -27,6 +86,9 @@ type (
 	IMailBox interface {
 		Register(funcName string, fn interface{}) // register handler function
 		GetFuncInfo(funcName string) (*creflect.FuncInfo, bool)
+		SetCapacity(capacity int32, policy OverflowPolicy)       // soft limit of queued messages, capacity<1 means unbounded
+		Count() int32                                            // number of queued messages
+		registerTyped(funcName string, fn any, invoke typedFunc) // register a handler of RegisterLocal or RegisterRemote
 	}
 )
 
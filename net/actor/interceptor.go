@@ -0,0 +1,69 @@
+package cherryActor
+
+import (
+	ccode "github.com/cherry-game/cherry/code"
+	creflect "github.com/cherry-game/cherry/extend/reflect"
+	cfacade "github.com/cherry-game/cherry/facade"
+)
+
+// AddInterceptor appends interceptors run around every invocation of every actor.
+// System interceptors run before the interceptors of the actor. Call it before startup.
+func (p *System) AddInterceptor(interceptors ...cfacade.Interceptor) {
+	p.interceptors = append(p.interceptors, interceptors...)
+}
+
+// AddInterceptor 添加当前Actor的拦截器,一般在OnInit中调用
+func (p *Actor) AddInterceptor(interceptors ...cfacade.Interceptor) {
+	p.interceptors = append(p.interceptors, interceptors...)
+}
+
+// intercept runs invoke through the system and actor interceptors.
+// It returns the code of the chain, OK once invoke has run.
+func (p *Actor) intercept(m *cfacade.Message, fi *creflect.FuncInfo, invoke func()) int32 {
+	if len(p.system.interceptors) == 0 && len(p.interceptors) == 0 {
+		invoke()
+		return ccode.OK
+	}
+
+	var (
+		index   = 0
+		invoked = false
+		next    func() int32
+	)
+
+	next = func() int32 {
+		i := index
+		index++
+
+		if i < len(p.system.interceptors) {
+			return p.system.interceptors[i](m, fi, next)
+		}
+
+		if i -= len(p.system.interceptors); i < len(p.interceptors) {
+			return p.interceptors[i](m, fi, next)
+		}
+
+		if !invoked {
+			invoked = true
+			invoke()
+		}
+
+		return ccode.OK
+	}
+
+	code := next()
+	if invoked || ccode.IsOK(code) {
+		return ccode.OK
+	}
+
+	p.logger().Debugf("[%s] intercepted. source=%s target=%s func=%s code=%d",
+		p.path,
+		m.Source,
+		m.Target,
+		m.FuncName,
+		code,
+	)
+
+	replyCode(p.App(), m, code)
+	return code
+}
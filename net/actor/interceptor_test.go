@@ -0,0 +1,66 @@
+package cherryActor
+
+import (
+	"reflect"
+	"testing"
+
+	ccode "github.com/cherry-game/cherry/code"
+	creflect "github.com/cherry-game/cherry/extend/reflect"
+	cfacade "github.com/cherry-game/cherry/facade"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+// TestInterceptor_Order verifies that system interceptors wrap actor
+// interceptors and that a short-circuit replies to the waiting caller.
+func TestInterceptor_Order(t *testing.T) {
+	system := NewSystem()
+
+	var calls []string
+	record := func(name string, code int32) cfacade.Interceptor {
+		return func(m *cfacade.Message, _ *creflect.FuncInfo, next func() int32) int32 {
+			calls = append(calls, name+".before")
+			if ccode.IsFail(code) {
+				return code
+			}
+			result := next()
+			calls = append(calls, name+".after")
+			return result
+		}
+	}
+
+	system.AddInterceptor(record("system", ccode.OK))
+
+	thisActor, _ := newActor("intercept", "", &testActor{}, system)
+	thisActor.remoteMail.Register("hello", func(_ *cproto.I32) {})
+	thisActor.AddInterceptor(record("actor", ccode.OK))
+
+	invoke := func(cfacade.IApplication, *creflect.FuncInfo, *cfacade.Message) {
+		calls = append(calls, "handler")
+	}
+
+	m := newTestMessage("hello")
+	thisActor.invokeFunc(thisActor.remoteMail, nil, invoke, m)
+
+	expected := []string{"system.before", "actor.before", "handler", "actor.after", "system.after"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("expected %v, got %v", expected, calls)
+	}
+
+	if len(m.ChanResult) != 0 {
+		t.Fatal("unexpected reply")
+	}
+
+	calls = nil
+	thisActor.AddInterceptor(record("auth", ccode.SessionUIDNotBind))
+	thisActor.invokeFunc(thisActor.remoteMail, nil, invoke, m)
+
+	expected = []string{"system.before", "actor.before", "auth.before", "actor.after", "system.after"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("expected %v, got %v", expected, calls)
+	}
+
+	rsp, ok := (<-m.ChanResult).(*cproto.Response)
+	if !ok || rsp.Code != ccode.SessionUIDNotBind {
+		t.Fatalf("expected code %d, got %+v", ccode.SessionUIDNotBind, rsp)
+	}
+}
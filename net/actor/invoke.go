@@ -21,7 +21,7 @@ func InvokeLocalFunc(app cfacade.IApplication, fi *creflect.FuncInfo, m *cfacade
 	}
 
 	if err := EncodeLocalArgs(app, fi, m); err != nil {
-		clog.Errorf("[InvokeLocalFunc] encode args error. [message = %+v, err = %v]", m, err)
+		app.Logger().Errorf("[InvokeLocalFunc] encode args error. [message = %+v, err = %v]", m, err)
 		return
 	}
 
@@ -38,7 +38,7 @@ func InvokeRemoteFunc(app cfacade.IApplication, fi *creflect.FuncInfo, m *cfacad
 	}
 
 	if err := EncodeRemoteArgs(app, fi, m); err != nil {
-		clog.Errorf("[InvokeRemoteFunc] encode args error. [message = %+v, err = %v]", m, err)
+		app.Logger().Errorf("[InvokeRemoteFunc] encode args error. [message = %+v, err = %v]", m, err)
 		replyReponseCode(app, m, ccode.RPCRemoteExecuteError)
 		return
 	}
@@ -60,30 +60,31 @@ func InvokeRemoteFunc(app cfacade.IApplication, fi *creflect.FuncInfo, m *cfacad
 
 		}, func(errString string) {
 			replyReponseCode(app, m, ccode.RPCRemoteExecuteError)
-			clog.Errorf("[InvokeRemoteFunc] invoke error. [message = %+v, err = %s]", m, errString)
+			app.Logger().Errorf("[InvokeRemoteFunc] invoke error. [message = %+v, err = %s]", m, errString)
 		})
 	} else {
-		cutils.Try(func() {
-			if m.ChanResult == nil {
-				fi.Value.Call(values)
-			} else {
-				rets := fi.Value.Call(values)
-				rsp := retValue(app, rets)
-				m.ChanResult <- rsp
-			}
-		}, func(errString string) {
-			if m.ChanResult != nil {
-				m.ChanResult <- nil
+		// the actor replies ActorInvokeRemoteError to the caller and hands the panic to the supervisor
+		defer func() {
+			if rev := recover(); rev != nil {
+				app.Logger().Errorf("[InvokeRemoteFunc] invoke error.[source = %s, target = %s -> %s, funcType = %v, err = %+v]",
+					m.Source,
+					m.Target,
+					m.FuncName,
+					fi.InArgs,
+					rev,
+				)
+
+				panic(rev)
 			}
+		}()
 
-			clog.Errorf("[InvokeRemoteFunc] invoke error.[source = %s, target = %s -> %s, funcType = %v, err = %+v]",
-				m.Source,
-				m.Target,
-				m.FuncName,
-				fi.InArgs,
-				errString,
-			)
-		})
+		if m.ChanResult == nil {
+			fi.Value.Call(values)
+		} else {
+			rets := fi.Value.Call(values)
+			rsp := retValue(app, rets)
+			m.ChanResult <- rsp
+		}
 	}
 }
 
@@ -146,32 +147,88 @@ func retValue(app cfacade.IApplication, rets []reflect.Value) *cproto.Response {
 	retsLen := len(rets)
 	switch retsLen {
 	case 1:
-		if val := rets[0].Interface(); val != nil {
-			if c, ok := val.(int32); ok {
-				rsp.Code = c
-			}
-		}
+		setResponseCode(rsp, rets[0].Interface())
 	case 2:
 		if !rets[0].IsNil() {
 			data, err := app.Serializer().Marshal(rets[0].Interface())
 			if err != nil {
 				rsp.Code = ccode.RPCRemoteExecuteError
-				clog.Warn(err)
+				app.Logger().Warn(err)
 			} else {
 				rsp.Data = data
 			}
 		}
 
-		if val := rets[1].Interface(); val != nil {
-			if c, ok := val.(int32); ok {
-				rsp.Code = c
-			}
-		}
+		setResponseCode(rsp, rets[1].Interface())
 	}
 
 	return rsp
 }
 
+// setResponseCode sets the code returned by a handler, an int32 or an error, to rsp.
+func setResponseCode(rsp *cproto.Response, val any) {
+	switch v := val.(type) {
+	case int32:
+		rsp.Code = v
+	case error:
+		codeErr := cerror.AsCode(v)
+		rsp.Code = codeErr.Code
+		rsp.Message = codeErr.Message
+		rsp.Details = codeErr.Details
+	}
+}
+
+// setFailure copies the message and details of the failed rsp to failure, which may be nil.
+func setFailure(failure *cerror.CodeError, rsp *cproto.Response) {
+	if failure == nil || rsp == nil {
+		return
+	}
+
+	failure.Message = rsp.Message
+	failure.Details = rsp.Details
+}
+
+// callError returns nil if code is OK, otherwise failure completed with code.
+func callError(code int32, failure *cerror.CodeError) error {
+	if ccode.IsOK(code) {
+		return nil
+	}
+
+	failure.Code = code
+	if failure.Message == "" {
+		failure.Message = ccode.Desc(code)
+	}
+
+	return failure
+}
+
+// replyCode answers the caller waiting for the reply of m with errCode.
+func replyCode(app cfacade.IApplication, m *cfacade.Message, errCode int32) {
+	replyError(app, m, errCode, "")
+}
+
+// replyError answers the caller waiting for the reply of m with errCode and an error message.
+func replyError(app cfacade.IApplication, m *cfacade.Message, errCode int32, message string) {
+	rsp := &cproto.Response{
+		Code:    errCode,
+		Message: message,
+	}
+
+	replyTo(app, m, rsp)
+}
+
+// replyTo answers the caller waiting for the reply of m with rsp.
+func replyTo(app cfacade.IApplication, m *cfacade.Message, rsp *cproto.Response) {
+	if m.ChanResult != nil {
+		select {
+		case m.ChanResult <- rsp:
+		default:
+		}
+	} else if m.Reply != "" {
+		replyResponse(app, m, rsp)
+	}
+}
+
 func replyReponseCode(app cfacade.IApplication, m *cfacade.Message, errCode int32) {
 	rsp := &cproto.Response{
 		Code: errCode,
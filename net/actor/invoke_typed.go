@@ -0,0 +1,142 @@
+package cherryActor
+
+import (
+	ccode "github.com/cherry-game/cherry/code"
+	cerror "github.com/cherry-game/cherry/error"
+	creflect "github.com/cherry-game/cherry/extend/reflect"
+	cfacade "github.com/cherry-game/cherry/facade"
+	clog "github.com/cherry-game/cherry/logger"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+// typedFunc dispatches a message to a handler registered with RegisterLocal or
+// RegisterRemote, without going through reflect.Value.Call.
+type typedFunc func(app cfacade.IApplication, m *cfacade.Message)
+
+// RegisterLocal registers a typed local (client) handler. The signature is
+// checked at compile time and the handler is called without reflection.
+func RegisterLocal[Req any](mb IMailBox, funcName string, fn func(session *cproto.Session, req *Req)) {
+	if fn == nil {
+		clog.Errorf("[RegisterLocal] func is nil. funcName = %s", funcName)
+		return
+	}
+
+	mb.registerTyped(funcName, fn, func(app cfacade.IApplication, m *cfacade.Message) {
+		req, err := decodeArgs[Req](app, m)
+		if err != nil {
+			app.Logger().Errorf("[RegisterLocal] decode args error. [message = %+v, err = %v]", m, err)
+			return
+		}
+
+		fn(m.Session, req)
+	})
+}
+
+// RegisterRemote registers a typed remote handler. The response and the
+// cherryCode are sent back to callers waiting for a reply.
+func RegisterRemote[Req, Resp any](mb IMailBox, funcName string, fn func(req *Req) (*Resp, int32)) {
+	if fn == nil {
+		clog.Errorf("[RegisterRemote] func is nil. funcName = %s", funcName)
+		return
+	}
+
+	registerRemote(mb, funcName, fn, func(req *Req) (*Resp, any) {
+		return fn(req)
+	})
+}
+
+// RegisterRemoteError registers a typed remote handler returning an error.
+// The code, message and details of a *cherryError.CodeError reach the caller,
+// any other error is replied as RPCRemoteExecuteError with its text.
+func RegisterRemoteError[Req, Resp any](mb IMailBox, funcName string, fn func(req *Req) (*Resp, error)) {
+	if fn == nil {
+		clog.Errorf("[RegisterRemoteError] func is nil. funcName = %s", funcName)
+		return
+	}
+
+	registerRemote(mb, funcName, fn, func(req *Req) (*Resp, any) {
+		return fn(req)
+	})
+}
+
+// registerRemote registers fn, calling it through call which returns the
+// response and the int32 code or error of the handler.
+func registerRemote[Req, Resp any](mb IMailBox, funcName string, fn any, call func(req *Req) (*Resp, any)) {
+	mb.registerTyped(funcName, fn, func(app cfacade.IApplication, m *cfacade.Message) {
+		req, err := decodeArgs[Req](app, m)
+		if err != nil {
+			app.Logger().Errorf("[RegisterRemote] decode args error. [message = %+v, err = %v]", m, err)
+			replyCode(app, m, ccode.RPCRemoteExecuteError)
+			return
+		}
+
+		resp, code := call(req)
+		if m.ChanResult == nil && m.Reply == "" {
+			return
+		}
+
+		rsp := &cproto.Response{}
+		setResponseCode(rsp, code)
+		if resp != nil {
+			data, err := app.Serializer().Marshal(resp)
+			if err != nil {
+				app.Logger().Warnf("[RegisterRemote] marshal reply error. [message = %+v, err = %v]", m, err)
+				rsp.Code = ccode.RPCRemoteExecuteError
+			} else {
+				rsp.Data = data
+			}
+		}
+
+		if m.Reply != "" {
+			replyResponse(app, m, rsp)
+		} else {
+			m.ChanResult <- rsp
+		}
+	})
+}
+
+// decodeArgs returns the typed argument of m, decoding cross-node and client bytes.
+func decodeArgs[Req any](app cfacade.IApplication, m *cfacade.Message) (*Req, error) {
+	switch args := m.Args.(type) {
+	case *Req:
+		return args, nil
+	case []byte:
+		if app == nil {
+			return nil, cerror.Error("app is nil.")
+		}
+
+		req := new(Req)
+		if err := app.Serializer().Unmarshal(args, req); err != nil {
+			return nil, err
+		}
+
+		m.Args = req
+		return req, nil
+	case nil:
+		return new(Req), nil
+	}
+
+	return nil, cerror.Errorf("args type error. [args = %T, expected = %T]", m.Args, (*Req)(nil))
+}
+
+func (p *mailbox) registerTyped(funcName string, fn any, invoke typedFunc) {
+	if funcName == "" {
+		clog.Errorf("[%s] Func name is empty.", p.name)
+		return
+	}
+
+	// resolved once, for interceptors and GetFuncInfo
+	funcInfo, err := creflect.GetFuncInfo(fn)
+	if err != nil {
+		clog.Errorf("funcName = %s, err = %v", funcName, err)
+		return
+	}
+
+	if _, found := p.funcMap[funcName]; found {
+		clog.Errorf("funcName = %s, already exists.", funcName)
+		return
+	}
+
+	p.funcMap[funcName] = &funcInfo
+	p.typedMap[funcName] = invoke
+}
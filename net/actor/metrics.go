@@ -0,0 +1,148 @@
+package cherryActor
+
+import (
+	"strconv"
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cfacade "github.com/cherry-game/cherry/facade"
+)
+
+// systemMetrics holds the metrics of the actor system. A nil *systemMetrics records nothing.
+type systemMetrics struct {
+	invoke        IHistogram // labels: actor, mailbox, func
+	arrival       IHistogram // labels: actor, mailbox
+	request       IHistogram // labels: actor, func
+	requestErrors ICounter   // labels: code
+}
+
+// SetMetrics registers the metrics of the actor system in registry, attached
+// by the metrics component. Actors are labeled with the type of their
+// handler, so the number of series does not grow with the number of actors.
+// See SetMetricsByID.
+func (p *System) SetMetrics(registry IMetrics) {
+	if registry == nil {
+		return
+	}
+
+	p.metrics.Store(&systemMetrics{
+		invoke: registry.Histogram("cherry_actor_invoke_seconds",
+			"Execution time of actor functions.", nil, "actor", "mailbox", "func"),
+		arrival: registry.Histogram("cherry_actor_arrival_seconds",
+			"Time between the build of a message and its processing.", nil, "actor", "mailbox"),
+		request: registry.Histogram("cherry_cluster_request_seconds",
+			"Latency of cross-node CallWait requests.", nil, "actor", "func"),
+		requestErrors: registry.Counter("cherry_cluster_request_errors_total",
+			"Failed cross-node CallWait requests by cherryCode.", "code"),
+	})
+
+	registry.GaugeFunc("cherry_actor_count", "Number of running actors.", []string{"kind"},
+		func(set func(value float64, labelValues ...string)) {
+			var actors, children float64
+			p.actorMap.Range(func(_, value any) bool {
+				actors++
+				value.(*Actor).child.childActors.Range(func(_, _ any) bool {
+					children++
+					return true
+				})
+				return true
+			})
+			set(actors, "actor")
+			set(children, "child")
+		})
+
+	registry.GaugeFunc("cherry_actor_mailbox_depth", "Queued messages of the actors and their children.", []string{"actor", "mailbox"},
+		func(set func(value float64, labelValues ...string)) {
+			depths := map[string]*[3]int32{}
+			p.actorMap.Range(func(_, value any) bool {
+				thisActor := value.(*Actor)
+				label := p.metricLabel(thisActor)
+
+				depth, found := depths[label]
+				if !found {
+					depth = &[3]int32{}
+					depths[label] = depth
+				}
+
+				local, remote, event := thisActor.QueueDepth()
+				depth[0], depth[1], depth[2] = depth[0]+local, depth[1]+remote, depth[2]+event
+
+				// children are counted with their parent
+				thisActor.child.childActors.Range(func(_, child any) bool {
+					local, remote, event = child.(*Actor).QueueDepth()
+					depth[0], depth[1], depth[2] = depth[0]+local, depth[1]+remote, depth[2]+event
+					return true
+				})
+				return true
+			})
+
+			for label, depth := range depths {
+				set(float64(depth[0]), label, LocalName)
+				set(float64(depth[1]), label, RemoteName)
+				set(float64(depth[2]), label, EventName)
+			}
+		})
+
+	registry.GaugeFunc("cherry_timer_active", "Active timers of the time wheel.", nil,
+		func(set func(value float64, labelValues ...string)) {
+			if p.timeWheel != nil {
+				set(float64(p.timeWheel.ActiveCount()))
+			}
+		})
+}
+
+// SetMetricsByID labels the actor metrics with the actor id instead of the
+// handler type. Every actor then has its own series, enable it only when the
+// actor ids are a small fixed set. Call it before startup.
+func (p *System) SetMetricsByID(enable bool) {
+	p.metricsByID = enable
+}
+
+// metricLabel returns the value of the actor label of thisActor.
+func (p *System) metricLabel(thisActor *Actor) string {
+	if p.metricsByID {
+		return thisActor.path.ActorID
+	}
+	return thisActor.kind
+}
+
+// Stats returns the number of running actors, children included, and the
+// number of messages queued in their mailboxes.
+func (p *System) Stats() (actors int, queued int64) {
+	p.actorMap.Range(func(_, value any) bool {
+		thisActor := value.(*Actor)
+		actors++
+		thisActor.child.childActors.Range(func(_, _ any) bool {
+			actors++
+			return true
+		})
+		queued += thisActor.queued()
+		return true
+	})
+	return actors, queued
+}
+
+func (m *systemMetrics) invoked(p *Actor, mb *mailbox, funcName string, arrival int64, start time.Time) {
+	if m == nil {
+		return
+	}
+
+	label := p.system.metricLabel(p)
+	m.arrival.Observe(float64(arrival)/1000, label, mb.name)
+	m.invoke.Observe(time.Since(start).Seconds(), label, mb.name, funcName)
+}
+
+// requested records a cross-node request. The handler type of the target is
+// unknown on this node, so the actor label is the actor id of the target
+// path. A child is labeled with its parent, the label does not grow with the
+// number of entities.
+func (m *systemMetrics) requested(target *cfacade.ActorPath, funcName string, code int32, start time.Time) {
+	if m == nil {
+		return
+	}
+
+	m.request.Observe(time.Since(start).Seconds(), target.ActorID, funcName)
+	if ccode.IsFail(code) {
+		m.requestErrors.Inc(strconv.Itoa(int(code)))
+	}
+}
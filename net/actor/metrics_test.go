@@ -0,0 +1,54 @@
+package cherryActor
+
+import (
+	"testing"
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cfacade "github.com/cherry-game/cherry/facade"
+)
+
+// TestSystem_Stats verifies that Stats counts the children of an actor and
+// the messages queued in their mailboxes.
+func TestSystem_Stats(t *testing.T) {
+	system := NewSystem()
+
+	parent, _ := newActor("stats", "", &testActor{}, system)
+	child, _ := newActor("stats", "child", &testActor{}, system)
+	parent.child.childActors.Store("child", child)
+	system.actorMap.Store("stats", parent)
+
+	parent.remoteMail.Push(newTestMessage("hello"))
+	child.localMail.Push(newTestMessage("hello"))
+	child.remoteMail.Push(newTestMessage("hello"))
+
+	actors, queued := system.Stats()
+	if actors != 2 || queued != 3 {
+		t.Fatalf("expected 2 actors and 3 queued messages, got %d and %d", actors, queued)
+	}
+}
+
+// testHistogram records the label values of its observations.
+type testHistogram struct {
+	labels [][]string
+}
+
+func (h *testHistogram) Observe(_ float64, labelValues ...string) {
+	h.labels = append(h.labels, labelValues)
+}
+
+// TestSystemMetrics_Requested verifies that a cross-node request is labeled
+// with the actor id of its target, children with their parent.
+func TestSystemMetrics_Requested(t *testing.T) {
+	request := &testHistogram{}
+	m := &systemMetrics{request: request}
+
+	m.requested(cfacade.NewActorPath("game-2", "room", ""), "join", ccode.OK, time.Now())
+	m.requested(cfacade.NewActorPath("game-2", "players", "u1"), "login", ccode.OK, time.Now())
+
+	if len(request.labels) != 2 ||
+		request.labels[0][0] != "room" || request.labels[0][1] != "join" ||
+		request.labels[1][0] != "players" || request.labels[1][1] != "login" {
+		t.Fatalf("unexpected labels %v", request.labels)
+	}
+}
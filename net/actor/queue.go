@@ -1,15 +1,28 @@
 package cherryActor
 
 import (
+	"sync"
 	"sync/atomic"
 	"unsafe"
 )
 
+const (
+	Reject     OverflowPolicy = iota // reject the new value, the sender gets cherryCode.ActorMailboxFull
+	DropOldest                       // drop the oldest queued value and push the new one
+	DropNewest                       // silently drop the new value
+	DeadLetter                       // hand the new value to the dead-letter actor, see System.SetDeadLetterActor
+)
+
 type (
+	OverflowPolicy int32
+
 	queue struct {
 		head, tail *queueNode
 		C          chan int32
 		count      int32
+		capacity   int32       // max queued values, <1 means unbounded
+		policy     int32       // OverflowPolicy applied when full
+		popMu      *sync.Mutex // DropOldest pops from the producer side
 	}
 
 	queueNode struct {
@@ -25,6 +38,7 @@ func newQueue() queue {
 		tail:  stub,
 		C:     make(chan int32, 1),
 		count: 0,
+		popMu: &sync.Mutex{},
 	}
 	return q
 }
@@ -45,7 +59,60 @@ func (p *queue) Push(v interface{}) {
 	p._setCount(1)
 }
 
+func (p OverflowPolicy) String() string {
+	switch p {
+	case Reject:
+		return "reject"
+	case DropOldest:
+		return "drop_oldest"
+	case DropNewest:
+		return "drop_newest"
+	case DeadLetter:
+		return "dead_letter"
+	}
+	return "unknown"
+}
+
+// SetCapacity 设置队列容量及溢出策略,capacity<1表示不限制
+// The limit is soft, see offer. Call it on the consumer goroutine (e.g. in
+// OnInit of the actor), Pop only takes the pop lock under DropOldest.
+func (p *queue) SetCapacity(capacity int32, policy OverflowPolicy) {
+	atomic.StoreInt32(&p.policy, int32(policy))
+	atomic.StoreInt32(&p.capacity, capacity)
+}
+
+// Capacity 返回队列容量及溢出策略
+func (p *queue) Capacity() (int32, OverflowPolicy) {
+	return atomic.LoadInt32(&p.capacity), OverflowPolicy(atomic.LoadInt32(&p.policy))
+}
+
+// offer pushes v, applying the overflow policy when the queue is full.
+// It returns the value that is not queued (v or the dropped oldest value)
+// and the policy that was applied. The limit is soft: concurrent producers
+// may overshoot it by a few values.
+func (p *queue) offer(v interface{}) (interface{}, OverflowPolicy, bool) {
+	capacity, policy := p.Capacity()
+	if capacity < 1 || p.Count() < capacity {
+		p.Push(v)
+		return nil, policy, false
+	}
+
+	if policy != DropOldest {
+		return v, policy, true
+	}
+
+	oldest := p.Pop()
+	p.Push(v)
+	return oldest, policy, oldest != nil
+}
+
 func (p *queue) Pop() interface{} {
+	// only DropOldest also pops from the producer side
+	if OverflowPolicy(atomic.LoadInt32(&p.policy)) == DropOldest {
+		p.popMu.Lock()
+		defer p.popMu.Unlock()
+	}
+
 	tail := p.tail
 	next := (*queueNode)(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&tail.next)))) // acquire
 	if next != nil {
@@ -79,9 +146,9 @@ func (p *queue) _setCount(delta int32) {
 	}
 }
 
+// Destroy drops the queued values. A sender may still push to the queue of a
+// stopped actor, so C stays open and head stays valid for it.
 func (p *queue) Destroy() {
-	close(p.C)
-	p.head = nil
-	p.tail = nil
-	p.count = 0
+	for p.Pop() != nil {
+	}
 }
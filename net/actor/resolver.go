@@ -0,0 +1,24 @@
+package cherryActor
+
+import (
+	cfacade "github.com/cherry-game/cherry/facade"
+)
+
+type pathResolvers []cfacade.PathResolver
+
+// AddResolver appends resolvers translating the logical target paths of
+// Call and CallWait into actor paths. Call it before startup.
+func (p *System) AddResolver(resolvers ...cfacade.PathResolver) {
+	p.resolvers = append(p.resolvers, resolvers...)
+}
+
+// resolve returns the actor path of target, or target if no resolver knows it.
+func (p *System) resolve(target string) string {
+	for _, resolver := range p.resolvers {
+		if path, found := resolver(target); found {
+			return path
+		}
+	}
+
+	return target
+}
@@ -0,0 +1,190 @@
+package cherryActor
+
+import (
+	"time"
+
+	cutils "github.com/cherry-game/cherry/extend/utils"
+)
+
+/**
+- A failure is a panic raised by OnInit, a mailbox function, an event function or a timer function.
+- The Supervisor of the failed actor decides what happens next:
+	- Resume: keep the current handler and continue with the next message.
+	- Restart: stop the handler, clear its registrations and run OnInit again,
+	  on a handler created by IActorFactory when the handler implements it.
+	- Stop: stop the actor.
+	- Escalate: stop the actor and fail the parent with the same reason.
+- A child without its own Supervisor uses the Supervisor of its parent.
+- A parent implementing IChildFailed is notified on its own goroutine when a child fails.
+*/
+
+const (
+	Resume Directive = iota
+	Restart
+	Stop
+	Escalate
+)
+
+type (
+	Directive int
+
+	Supervisor struct {
+		Directive   Directive                  // directive applied when Decider is nil
+		Decider     func(reason any) Directive // pick a directive from the panic value
+		MaxRestarts int                        // restarts allowed within Within, <1 means unlimited
+		Within      time.Duration              // restart window, <=0 counts restarts over the actor lifetime
+	}
+)
+
+// NewSupervisor 创建监督策略,在within时间内最多重启maxRestarts次,超出后停止Actor
+func NewSupervisor(directive Directive, maxRestarts int, within time.Duration) *Supervisor {
+	return &Supervisor{
+		Directive:   directive,
+		MaxRestarts: maxRestarts,
+		Within:      within,
+	}
+}
+
+func (p *Supervisor) decide(reason any) Directive {
+	if p.Decider != nil {
+		return p.Decider(reason)
+	}
+	return p.Directive
+}
+
+func (d Directive) String() string {
+	switch d {
+	case Resume:
+		return "resume"
+	case Restart:
+		return "restart"
+	case Stop:
+		return "stop"
+	case Escalate:
+		return "escalate"
+	}
+	return "unknown"
+}
+
+// supervisor returns the supervisor of the handler, or the one inherited from the parent.
+func (p *Actor) supervisor() *Supervisor {
+	if supervised, ok := p.handler.(ISupervised); ok {
+		if s := supervised.Supervisor(); s != nil {
+			return s
+		}
+	}
+
+	return p.parentSupervisor
+}
+
+// onFailure applies the supervisor directive for a failure of this actor.
+// fallback is used when no supervisor is configured.
+func (p *Actor) onFailure(reason any, fallback Directive) {
+	for reason != nil {
+		directive := fallback
+		supervisor := p.supervisor()
+		if supervisor != nil {
+			directive = supervisor.decide(reason)
+		}
+
+		if directive == Restart && !p.allowRestart(supervisor) {
+			p.logger().Warnf("[%s] restart limit exceeded, stop actor. reason=%v", p.path, reason)
+			directive = Stop
+		}
+
+		if directive == Escalate && p.path.IsParent() {
+			directive = Stop
+		}
+
+		p.logger().Warnf("[%s] actor failed. directive=%s reason=%v", p.path, directive, reason)
+
+		p.notifyParent(reason, directive)
+
+		switch directive {
+		case Restart:
+			reason = p.restart()
+		case Stop, Escalate:
+			p.stopped = true
+			p.setState(StopState)
+			reason = nil
+		default:
+			reason = nil
+		}
+	}
+}
+
+func (p *Actor) allowRestart(supervisor *Supervisor) bool {
+	if supervisor == nil || supervisor.MaxRestarts < 1 {
+		return true
+	}
+
+	now := time.Now()
+	if supervisor.Within > 0 {
+		i := 0
+		for i < len(p.restarts) && now.Sub(p.restarts[i]) > supervisor.Within {
+			i++
+		}
+		p.restarts = p.restarts[i:]
+	}
+
+	if len(p.restarts) >= supervisor.MaxRestarts {
+		return false
+	}
+
+	p.restarts = append(p.restarts, now)
+	return true
+}
+
+// restart stops the current handler, clears everything it registered and
+// initializes the handler again. It returns the panic value of OnInit.
+func (p *Actor) restart() any {
+	cutils.Try(p.handler.OnStop, func(errString string) {
+		p.logger().Error(errString)
+	})
+
+	p.timer.RemoveAll()
+	p.event.reset()
+	p.localMail.reset()
+	p.remoteMail.reset()
+	p.interceptors = nil
+
+	if factory, ok := p.handler.(IActorFactory); ok {
+		if handler := factory.NewHandler(); handler != nil {
+			p.handler = handler
+		}
+	}
+
+	if actorLoad, ok := p.handler.(IActorLoader); ok {
+		actorLoad.load(p)
+	}
+
+	return p.initHandler()
+}
+
+func (p *Actor) notifyParent(reason any, directive Directive) {
+	if p.path.IsParent() {
+		return
+	}
+
+	if parent, found := p.system.GetActor(p.path.ActorID); found {
+		childID := p.path.ChildID
+		parent.runTask(func() {
+			parent.onChildFailed(childID, reason, directive)
+		})
+	}
+}
+
+// onChildFailed runs on the parent goroutine.
+func (p *Actor) onChildFailed(childID string, reason any, directive Directive) {
+	if handler, ok := p.handler.(IChildFailed); ok {
+		cutils.Try(func() {
+			handler.OnChildFailed(childID, reason, directive)
+		}, func(errString string) {
+			p.logger().Error(errString)
+		})
+	}
+
+	if directive == Escalate {
+		p.onFailure(reason, Stop)
+	}
+}
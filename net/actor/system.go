@@ -1,11 +1,14 @@
 package cherryActor
 
 import (
+	"context"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	ccode "github.com/cherry-game/cherry/code"
+	cerror "github.com/cherry-game/cherry/error"
 	ctimeWheel "github.com/cherry-game/cherry/extend/time_wheel"
 	cutils "github.com/cherry-game/cherry/extend/utils"
 	cfacade "github.com/cherry-game/cherry/facade"
@@ -30,6 +33,22 @@ type (
 		timeWheel        *ctimeWheel.TimeWheel // global timer for all actors
 		timerTick        time.Duration         // time wheel tick, configured before Start
 		timerHint        int                   // time wheel nodeMap pre-alloc hint
+		pendingCalls     atomic.Int64          // in-flight CallWait count
+		dropping         atomic.Bool           // drain deadline exceeded, actors exit without emptying queues
+		deadLetterActor  string                // receives undeliverable messages and events, empty disables
+		factory          cfacade.ActorFactory  // creates unknown actors on their first message
+		factoryMu        sync.Mutex            // serializes actor creation by the factory
+		interceptors     []cfacade.Interceptor // run around every actor invocation
+		resolvers        pathResolvers         // translate logical target paths of calls
+		metricsByID      bool                  // label metrics with actor ids instead of handler types
+		watchdogLimit    time.Duration         // report handlers running longer than the limit, 0 disables
+		watchdogPolicy   WatchdogPolicy        // applied to the stuck actors
+		watchdogDie      chan struct{}         // stops the watchdog
+
+		// attached by their components while the actors may already run
+		balancer atomic.Pointer[IMemberSelector] // chooses the member of CallType, nil publishes to every member
+		tracer   atomic.Pointer[ITracer]         // records the spans of invocations and cross-node calls, nil disables
+		metrics  atomic.Pointer[systemMetrics]   // latency and error metrics, nil disables
 	}
 )
 
@@ -62,6 +81,20 @@ func (p *System) Start(app cfacade.IApplication) {
 	}
 
 	p.timeWheel.Start()
+
+	if p.watchdogLimit > 0 {
+		p.watchdogDie = make(chan struct{})
+		go p.watchdog()
+	}
+}
+
+// logger returns the logger of the application running this system, or the
+// package default logger before Start.
+func (p *System) logger() cfacade.ILogger {
+	if p.app == nil {
+		return clog.DefaultLogger
+	}
+	return p.app.Logger()
 }
 
 func (p *System) NodeID() string {
@@ -85,26 +118,100 @@ func (p *System) SetTimerHint(n int) {
 	}
 }
 
+// SetFactory sets the factory creating unknown actors on their first message.
+func (p *System) SetFactory(factory cfacade.ActorFactory) {
+	p.factory = factory
+}
+
+// activate returns the actor with id, creating it through the factory when it does not exist.
+func (p *System) activate(id string) (*Actor, bool) {
+	if thisActor, found := p.GetActor(id); found || p.factory == nil {
+		return thisActor, found
+	}
+
+	p.factoryMu.Lock()
+	defer p.factoryMu.Unlock()
+
+	if thisActor, found := p.GetActor(id); found {
+		return thisActor, true
+	}
+
+	handler, found := p.factory(id)
+	if !found || handler == nil {
+		return nil, false
+	}
+
+	if _, err := p.CreateActor(id, handler); err != nil {
+		p.logger().Warnf("[activate] create actor fail. [actorID = %s, err = %v]", id, err)
+		return nil, false
+	}
+
+	return p.GetActor(id)
+}
+
 func (p *System) Stop() {
 	if p.timeWheel != nil {
 		p.timeWheel.Stop()
 	}
 
+	if p.watchdogDie != nil {
+		close(p.watchdogDie)
+	}
+
 	p.actorMap.Range(func(key, value any) bool {
 		actor, ok := value.(*Actor)
 		if ok {
 			cutils.Try(func() {
 				actor.Exit()
 			}, func(err string) {
-				clog.Warnf("[OnStop] - [actorID = %s, err = %s]", actor.path, err)
+				p.logger().Warnf("[OnStop] - [actorID = %s, err = %s]", actor.path, err)
 			})
 		}
 		return true
 	})
 
-	clog.Info("[OnStop] actor system stopping!")
+	p.logger().Info("[OnStop] actor system stopping!")
 	p.wg.Wait()
-	clog.Info("[OnStop] actor system stopped!")
+	p.logger().Info("[OnStop] actor system stopped!")
+}
+
+// Drain waits until every actor has emptied its local, remote and event
+// queues and no CallWait is waiting for a reply. If ctx is done first, the
+// remaining work is reported and dropped when the actors stop.
+func (p *System) Drain(ctx context.Context) bool {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		queued, calls := p.queued(), p.pendingCalls.Load()
+		if queued < 1 && calls < 1 {
+			p.logger().Info("[Drain] actor system drained!")
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			p.dropping.Store(true)
+			p.logger().Warnf("[Drain] deadline exceeded, drop remaining work. [queued messages = %d, pending calls = %d]",
+				queued,
+				calls,
+			)
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// queued returns the number of messages waiting in all actor queues.
+func (p *System) queued() int64 {
+	var count int64
+	p.actorMap.Range(func(key, value any) bool {
+		if actor, ok := value.(*Actor); ok {
+			count += actor.queued()
+		}
+		return true
+	})
+	return count
 }
 
 // GetIActor returns IActor by actor ID
@@ -135,7 +242,7 @@ func (p *System) GetChildActor(actorID, childID string) (*Actor, bool) {
 func (p *System) GetActorWithPath(path string) (*Actor, bool) {
 	actorPath, err := cfacade.ToActorPath(path)
 	if err != nil {
-		clog.Warnf("[GetActorWithPath] Actor path is error. path = %s, err = %v", path, err)
+		p.logger().Warnf("[GetActorWithPath] Actor path is error. path = %s, err = %v", path, err)
 		return nil, false
 	}
 
@@ -173,8 +280,13 @@ func (p *System) CreateActor(id string, handler cfacade.IActorHandler) (cfacade.
 
 // Call sends a remote message (no reply)
 func (p *System) Call(source, target, funcName string, arg any) int32 {
+	return p.call(callOrigin{}, source, target, funcName, arg)
+}
+
+// call sends a remote message that carries the deadline and trace of origin.
+func (p *System) call(origin callOrigin, source, target, funcName string, arg any) int32 {
 	if target == "" {
-		clog.Warnf("[Call] Target path is nil. [source = %s, target = %s, funcName = %s]",
+		p.logger().Warnf("[Call] Target path is nil. [source = %s, target = %s, funcName = %s]",
 			source,
 			target,
 			funcName,
@@ -182,8 +294,10 @@ func (p *System) Call(source, target, funcName string, arg any) int32 {
 		return ccode.ActorPathIsNil
 	}
 
+	target = p.resolve(target)
+
 	if len(funcName) < 1 {
-		clog.Warnf("[Call] FuncName error. [source = %s, target = %s, funcName = %s]",
+		p.logger().Warnf("[Call] FuncName error. [source = %s, target = %s, funcName = %s]",
 			source,
 			target,
 			funcName,
@@ -193,7 +307,7 @@ func (p *System) Call(source, target, funcName string, arg any) int32 {
 
 	targetPath, err := cfacade.ToActorPath(target)
 	if err != nil {
-		clog.Warnf("[Call] Target path error. [source = %s, target = %s, funcName = %s, err = %v]",
+		p.logger().Warnf("[Call] Target path error. [source = %s, target = %s, funcName = %s, err = %v]",
 			source,
 			target,
 			funcName,
@@ -205,14 +319,17 @@ func (p *System) Call(source, target, funcName string, arg any) int32 {
 	if targetPath.NodeID != "" && targetPath.NodeID != p.NodeID() {
 		remoteMsg, errCode := p.buildClusterMessage(source, target, funcName, arg)
 		if ccode.IsFail(errCode) {
-			clog.Warnf("[Call] Marshal arg error. [targetPath = %s, error = %d]", target, errCode)
+			p.logger().Warnf("[Call] Marshal arg error. [targetPath = %s, error = %d]", target, errCode)
 			return errCode
 		}
+		remoteMsg.Deadline = origin.deadline
+		remoteMsg.TraceID = origin.traceID
+		remoteMsg.SpanID = origin.spanID
 
 		// PublishRemote recycles remoteMsg via defer on all paths.
 		err := p.app.Cluster().PublishRemote(targetPath.NodeID, remoteMsg)
 		if err != nil {
-			clog.Warnf("[Call] Publish remote fail. [source = %s, target = %s, funcName = %s, err = %v]",
+			p.logger().Warnf("[Call] Publish remote fail. [source = %s, target = %s, funcName = %s, err = %v]",
 				source,
 				target,
 				funcName,
@@ -226,10 +343,13 @@ func (p *System) Call(source, target, funcName string, arg any) int32 {
 		remoteMsg.Target = target
 		remoteMsg.FuncName = funcName
 		remoteMsg.Args = arg
+		remoteMsg.Deadline = origin.deadline
+		remoteMsg.TraceID = origin.traceID
+		remoteMsg.SpanID = origin.spanID
 
-		if !p.PostRemote(remoteMsg) {
-			clog.Warnf("[Call] Post remote fail. [source = %s, target = %s, funcName = %s]", source, target, funcName)
-			return ccode.ActorInvokeRemoteError
+		if code := p.postRemote(remoteMsg); ccode.IsFail(code) {
+			p.logger().Warnf("[Call] Post remote fail. [source = %s, target = %s, funcName = %s, code = %d]", source, target, funcName, code)
+			return code
 		}
 	}
 
@@ -238,9 +358,68 @@ func (p *System) Call(source, target, funcName string, arg any) int32 {
 
 // CallWait sends a remote message and waits for reply
 func (p *System) CallWait(source, target, funcName string, arg, reply any) int32 {
+	return p.callWait(context.Background(), callOrigin{}, source, target, funcName, arg, reply, nil)
+}
+
+// CallWaitError sends a remote message and waits for reply. It returns nil on
+// success, otherwise a *cherryError.CodeError holding the code and the message
+// and details replied by the target.
+func (p *System) CallWaitError(source, target, funcName string, arg, reply any) error {
+	failure := &cerror.CodeError{}
+	code := p.callWait(context.Background(), callOrigin{}, source, target, funcName, arg, reply, failure)
+	return callError(code, failure)
+}
+
+// CallWaitContext sends a remote message and waits for reply until the call
+// timeout, the deadline of ctx or the cancellation of ctx. The deadline
+// travels with the message, the target drops it once expired.
+func (p *System) CallWaitContext(ctx context.Context, source, target, funcName string, arg, reply any) int32 {
+	return p.callWait(ctx, callOrigin{}, source, target, funcName, arg, reply, nil)
+}
+
+// requestRemote sends m to nodeID and waits for the response, keeping its
+// error message and details when the cluster implements IClusterResponse.
+func (p *System) requestRemote(nodeID string, m *cfacade.Message, timeout time.Duration) (*cproto.Response, int32) {
+	cluster := p.app.Cluster()
+	if requester, ok := cluster.(cfacade.IClusterResponse); ok {
+		return requester.RequestResponse(nodeID, m, timeout)
+	}
+
+	data, code := cluster.RequestRemote(nodeID, m, timeout)
+	return &cproto.Response{Code: code, Data: data}, code
+}
+
+// callDeadline returns the earliest of the call timeout, the deadline of ctx
+// and the inherited deadline (unix ms, 0 means none).
+func (p *System) callDeadline(ctx context.Context, inherited int64) time.Time {
+	deadline := time.Now().Add(p.callTimeout)
+
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	if inherited > 0 && inherited < deadline.UnixMilli() {
+		deadline = time.UnixMilli(inherited)
+	}
+
+	return deadline
+}
+
+// callWait sends a remote message and waits for reply. failure, if not nil,
+// receives the message and details replied with a failure code.
+func (p *System) callWait(ctx context.Context, origin callOrigin, source, target, funcName string, arg, reply any, failure *cerror.CodeError) int32 {
+	p.pendingCalls.Add(1)
+	defer p.pendingCalls.Add(-1)
+
+	deadline := p.callDeadline(ctx, origin.deadline)
+	timeout := time.Until(deadline)
+	if timeout <= 0 || ctx.Err() != nil {
+		return ccode.ActorCallTimeout
+	}
+
 	sourcePath, err := cfacade.ToActorPath(source)
 	if err != nil {
-		clog.Warnf("[CallWait] Source path error. [source = %s, target = %s, funcName = %s, err = %v]",
+		p.logger().Warnf("[CallWait] Source path error. [source = %s, target = %s, funcName = %s, err = %v]",
 			source,
 			target,
 			funcName,
@@ -249,9 +428,11 @@ func (p *System) CallWait(source, target, funcName string, arg, reply any) int32
 		return ccode.ActorConvertPathError
 	}
 
+	target = p.resolve(target)
+
 	targetPath, err := cfacade.ToActorPath(target)
 	if err != nil {
-		clog.Warnf("[CallWait] Target path error. [source = %s, target = %s, funcName = %s, err = %v]",
+		p.logger().Warnf("[CallWait] Target path error. [source = %s, target = %s, funcName = %s, err = %v]",
 			source,
 			target,
 			funcName,
@@ -261,7 +442,7 @@ func (p *System) CallWait(source, target, funcName string, arg, reply any) int32
 	}
 
 	if source == target {
-		clog.Warnf("[CallWait] Source path is equal target. [source = %s, target = %s, funcName = %s]",
+		p.logger().Warnf("[CallWait] Source path is equal target. [source = %s, target = %s, funcName = %s]",
 			source,
 			target,
 			funcName,
@@ -270,7 +451,7 @@ func (p *System) CallWait(source, target, funcName string, arg, reply any) int32
 	}
 
 	if len(funcName) < 1 {
-		clog.Warnf("[CallWait] FuncName error. [source = %s, target = %s, funcName = %s]",
+		p.logger().Warnf("[CallWait] FuncName error. [source = %s, target = %s, funcName = %s]",
 			source,
 			target,
 			funcName,
@@ -282,19 +463,36 @@ func (p *System) CallWait(source, target, funcName string, arg, reply any) int32
 	if targetPath.NodeID != "" && targetPath.NodeID != sourcePath.NodeID {
 		remoteMsg, errCode := p.buildClusterMessage(source, target, funcName, arg)
 		if ccode.IsFail(errCode) {
-			clog.Warnf("[CallWait] Marshal arg error. [targetPath = %s, error = %d]", target, errCode)
+			p.logger().Warnf("[CallWait] Marshal arg error. [targetPath = %s, error = %d]", target, errCode)
 			return errCode
 		}
+		remoteMsg.Deadline = deadline.UnixMilli()
+		remoteMsg.TraceID = origin.traceID
+		remoteMsg.SpanID = origin.spanID
+
+		var span ISpan = nopSpan{}
+		if origin.traceID != "" {
+			span = p.startSpan(origin.traceID, origin.spanID, "request:"+funcName)
+			span.SetAttr("source", source)
+			span.SetAttr("target", target)
+			if spanID := span.GetSpanID(); spanID != "" {
+				remoteMsg.SpanID = spanID
+			}
+		}
 
 		// RequestRemote recycles remoteMsg via defer on all paths.
-		rspData, rspCode := p.app.Cluster().RequestRemote(targetPath.NodeID, remoteMsg, p.callTimeout)
+		start := time.Now()
+		rsp, rspCode := p.requestRemote(targetPath.NodeID, remoteMsg, timeout)
+		p.metrics.Load().requested(targetPath, funcName, rspCode, start)
+		span.Finish(rspCode)
 		if ccode.IsFail(rspCode) {
+			setFailure(failure, rsp)
 			return rspCode
 		}
 
 		if reply != nil {
-			if err = p.app.Serializer().Unmarshal(rspData, reply); err != nil {
-				clog.Warnf("[CallWait] Marshal reply error. [targetPath = %s, error = %s]", target, err)
+			if err = p.app.Serializer().Unmarshal(rsp.GetData(), reply); err != nil {
+				p.logger().Warnf("[CallWait] Marshal reply error. [targetPath = %s, error = %s]", target, err)
 				return ccode.ActorMarshalError
 			}
 		}
@@ -305,8 +503,14 @@ func (p *System) CallWait(source, target, funcName string, arg, reply any) int32
 		message.Target = target
 		message.FuncName = funcName
 		message.Args = arg
+		message.Deadline = deadline.UnixMilli()
+		message.TraceID = origin.traceID
+		message.SpanID = origin.spanID
 		message.ChanResult = make(chan interface{}, 1) // Buffered (1)
 
+		// the actor recycles message after replying, keep the channel
+		chanResult := message.ChanResult
+
 		if sourcePath.ActorID == targetPath.ActorID {
 			childActor, found := p.GetChildActor(targetPath.ActorID, targetPath.ChildID)
 			if !found {
@@ -315,19 +519,19 @@ func (p *System) CallWait(source, target, funcName string, arg, reply any) int32
 			}
 			childActor.PostRemote(message)
 		} else {
-			if !p.PostRemote(message) {
-				clog.Warnf("[CallWait] Post remote fail. [source = %s, target = %s, funcName = %s]", source, target, funcName)
-				return ccode.ActorInvokeRemoteError
+			if code := p.postRemote(message); ccode.IsFail(code) {
+				p.logger().Warnf("[CallWait] Post remote fail. [source = %s, target = %s, funcName = %s, code = %d]", source, target, funcName, code)
+				return code
 			}
 		}
 
 		var result interface{}
 
 		select {
-		case result = <-message.ChanResult:
+		case result = <-chanResult:
 			{
 				if result == nil {
-					clog.Warnf("[CallWait] Response is nil. [source = %s, target = %s, funcName = %s]",
+					p.logger().Warnf("[CallWait] Response is nil. [source = %s, target = %s, funcName = %s]",
 						source,
 						target,
 						funcName,
@@ -337,7 +541,7 @@ func (p *System) CallWait(source, target, funcName string, arg, reply any) int32
 
 				rsp := result.(*cproto.Response)
 				if rsp == nil {
-					clog.Warnf("[CallWait] Response is nil. [source = %s, target = %s, funcName = %s]",
+					p.logger().Warnf("[CallWait] Response is nil. [source = %s, target = %s, funcName = %s]",
 						source,
 						target,
 						funcName,
@@ -346,12 +550,22 @@ func (p *System) CallWait(source, target, funcName string, arg, reply any) int32
 				}
 
 				if ccode.IsFail(rsp.Code) {
+					if rsp.Message != "" {
+						p.logger().Warnf("[CallWait] Response error. [source = %s, target = %s, funcName = %s, code = %d, message = %s]",
+							source,
+							target,
+							funcName,
+							rsp.Code,
+							rsp.Message,
+						)
+					}
+					setFailure(failure, rsp)
 					return rsp.Code
 				}
 
 				if reply != nil {
 					if rsp.Data == nil {
-						clog.Warnf("[CallWait] rsp.Data is nil.[source = %s, target = %s, funcName = %s, error = %s]",
+						p.logger().Warnf("[CallWait] rsp.Data is nil.[source = %s, target = %s, funcName = %s, error = %s]",
 							source,
 							target,
 							funcName,
@@ -361,7 +575,7 @@ func (p *System) CallWait(source, target, funcName string, arg, reply any) int32
 
 					err = p.app.Serializer().Unmarshal(rsp.Data, reply)
 					if err != nil {
-						clog.Warnf("[CallWait] Unmarshal reply error.[source = %s, target = %s, funcName = %s, error = %s]",
+						p.logger().Warnf("[CallWait] Unmarshal reply error.[source = %s, target = %s, funcName = %s, error = %s]",
 							source,
 							target,
 							funcName,
@@ -371,7 +585,9 @@ func (p *System) CallWait(source, target, funcName string, arg, reply any) int32
 					}
 				}
 			}
-		case <-time.After(p.callTimeout):
+		case <-ctx.Done():
+			return ccode.ActorCallTimeout
+		case <-time.After(timeout):
 			return ccode.ActorCallTimeout
 		}
 	}
@@ -379,14 +595,29 @@ func (p *System) CallWait(source, target, funcName string, arg, reply any) int32
 	return ccode.OK
 }
 
-// CallType publishes message by node type
+// SetBalancer sets the balancer choosing the member called by CallType,
+// attached by the balancer component. Node types without a strategy still
+// receive the message on every member.
+func (p *System) SetBalancer(balancer IMemberSelector) {
+	if balancer != nil {
+		p.balancer.Store(&balancer)
+	}
+}
+
+// CallType publishes message by node type, or calls the member chosen by the
+// balancer when the node type has a strategy. actorID is the balancing key.
 func (p *System) CallType(nodeType, actorID, funcName string, arg any) int32 {
+	return p.callType(callOrigin{}, "", actorID, nodeType, actorID, funcName, arg)
+}
+
+// callType is CallType sent by source, key chooses the member.
+func (p *System) callType(origin callOrigin, source, key, nodeType, actorID, funcName string, arg any) int32 {
 	if actorID == "" {
 		return ccode.ActorIDIsNil
 	}
 
 	if len(funcName) < 1 {
-		clog.Warnf("[CallType] FuncName error. [nodeType = %s, actorID = %s, funcName = %s]",
+		p.logger().Warnf("[CallType] FuncName error. [nodeType = %s, actorID = %s, funcName = %s]",
 			nodeType,
 			actorID,
 			funcName,
@@ -394,9 +625,15 @@ func (p *System) CallType(nodeType, actorID, funcName string, arg any) int32 {
 		return ccode.ActorFuncNameError
 	}
 
+	if balancer := p.balancer.Load(); balancer != nil {
+		if member, found := (*balancer).Select(nodeType, key); found {
+			return p.call(origin, source, cfacade.NewPath(member.GetNodeID(), actorID), funcName, arg)
+		}
+	}
+
 	argsBytes, errCode := p.marshalArg(arg)
 	if ccode.IsFail(errCode) {
-		clog.Warnf("[CallType] Marshal arg error. [nodeType = %s, actorID = %s, funcName = %s, error = %d]",
+		p.logger().Warnf("[CallType] Marshal arg error. [nodeType = %s, actorID = %s, funcName = %s, error = %d]",
 			nodeType,
 			actorID,
 			funcName,
@@ -413,7 +650,7 @@ func (p *System) CallType(nodeType, actorID, funcName string, arg any) int32 {
 	// PublishRemoteType recycles remoteMsg via defer on all paths.
 	err := p.app.Cluster().PublishRemoteType(nodeType, remoteMsg)
 	if err != nil {
-		clog.Warnf("[CallType] Publish remote fail. [nodeType = %s, actorID = %s, funcName = %s, error = %v]",
+		p.logger().Warnf("[CallType] Publish remote fail. [nodeType = %s, actorID = %s, funcName = %s, error = %v]",
 			nodeType,
 			actorID,
 			funcName,
@@ -427,59 +664,89 @@ func (p *System) CallType(nodeType, actorID, funcName string, arg any) int32 {
 
 // PostRemote delivers message to the remote mailbox.
 func (p *System) PostRemote(m *cfacade.Message) bool {
+	return ccode.IsOK(p.postRemote(m))
+}
+
+// postRemote delivers message to the remote mailbox and returns the cherryCode of the delivery.
+func (p *System) postRemote(m *cfacade.Message) int32 {
 	if m == nil {
-		clog.Error("Message is nil.")
-		return false
+		p.logger().Error("Message is nil.")
+		return ccode.ActorInvokeRemoteError
 	}
 
-	targetActor, found := p.GetActor(m.TargetPath().ActorID)
+	targetActor, found := p.activate(m.TargetPath().ActorID)
 	if !found {
-		clog.Warnf("[PostRemote] actor not found. [source = %s, target = %s -> %s]", m.Source, m.Target, m.FuncName)
+		p.logger().Warnf("[PostRemote] actor not found. [source = %s, target = %s -> %s]", m.Source, m.Target, m.FuncName)
+		p.undeliveredMessage(m, DeadLetterActorNotFound, ccode.ActorInvokeRemoteError)
 		m.Recycle()
-		return false
+		return ccode.ActorInvokeRemoteError
 	}
 
-	if targetActor.State() != WorkerState {
+	if targetActor.passivated.Load() {
+		return targetActor.park(targetActor.remoteMail, m, p.postRemote)
+	}
+
+	if !targetActor.accepting() {
+		p.logger().Warnf("[PostRemote] actor stopped. [source = %s, target = %s -> %s]", m.Source, m.Target, m.FuncName)
+		p.undeliveredMessage(m, DeadLetterActorStopped, ccode.ActorInvokeRemoteError)
 		m.Recycle()
-		return false
+		return ccode.ActorInvokeRemoteError
+	}
+
+	if !targetActor.post(targetActor.remoteMail, m) {
+		return ccode.ActorMailboxFull
 	}
 
-	targetActor.PostRemote(m)
-	return true
+	return ccode.OK
 }
 
 // PostLocal delivers message to the local mailbox.
 func (p *System) PostLocal(m *cfacade.Message) bool {
+	return ccode.IsOK(p.postLocal(m))
+}
+
+// postLocal delivers message to the local mailbox and returns the cherryCode of the delivery.
+func (p *System) postLocal(m *cfacade.Message) int32 {
 	if m == nil {
-		clog.Error("Message is nil.")
-		return false
+		p.logger().Error("Message is nil.")
+		return ccode.ActorInvokeRemoteError
 	}
 
-	targetActor, found := p.GetActor(m.TargetPath().ActorID)
+	targetActor, found := p.activate(m.TargetPath().ActorID)
 	if !found {
-		clog.Warnf("[PostLocal] actor not found. [source = %s, target = %s -> %s]", m.Source, m.Target, m.FuncName)
+		p.logger().Warnf("[PostLocal] actor not found. [source = %s, target = %s -> %s]", m.Source, m.Target, m.FuncName)
+		p.undeliveredMessage(m, DeadLetterActorNotFound, ccode.ActorNotFound)
 		m.Recycle()
-		return false
+		return ccode.ActorNotFound
 	}
 
-	if targetActor.State() != WorkerState {
+	if targetActor.passivated.Load() {
+		return targetActor.park(targetActor.localMail, m, p.postLocal)
+	}
+
+	if !targetActor.accepting() {
+		p.logger().Warnf("[PostLocal] actor stopped. [source = %s, target = %s -> %s]", m.Source, m.Target, m.FuncName)
+		p.undeliveredMessage(m, DeadLetterActorStopped, ccode.ActorNotFound)
 		m.Recycle()
-		return false
+		return ccode.ActorNotFound
 	}
 
-	targetActor.PostLocal(m)
-	return true
+	if !targetActor.post(targetActor.localMail, m) {
+		return ccode.ActorMailboxFull
+	}
+
+	return ccode.OK
 }
 
 // PostEvent delivers an event to subscribed actors
 func (p *System) PostEvent(data cfacade.IEventData) {
 	if data == nil {
-		clog.Error("[PostEvent] Event is nil.")
+		p.logger().Error("[PostEvent] Event is nil.")
 		return
 	}
 
 	if len(data.Name()) < 1 {
-		clog.Warnf("[PostEvent] Event name is empty. value = %v", data)
+		p.logger().Warnf("[PostEvent] Event name is empty. value = %v", data)
 		return
 	}
 
@@ -513,7 +780,7 @@ func (p *System) PostEvent(data cfacade.IEventData) {
 
 		uniqueID, ok := value.(int64)
 		if !ok {
-			clog.Warnf("[PostEvent] UniqueID set error in actorEventMap. value = %v", value)
+			p.logger().Warnf("[PostEvent] UniqueID set error in actorEventMap. value = %v", value)
 			return true
 		}
 
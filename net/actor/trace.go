@@ -0,0 +1,64 @@
+package cherryActor
+
+import (
+	cfacade "github.com/cherry-game/cherry/facade"
+)
+
+// callOrigin is inherited by the calls an actor makes while processing a message.
+type callOrigin struct {
+	deadline int64  // unix ms, 0 means none
+	traceID  string // trace of the message, empty if not traced
+	spanID   string // parent span of the calls
+}
+
+// nopSpan is the span of the operations that are not recorded.
+type nopSpan struct{}
+
+func (nopSpan) GetSpanID() string      { return "" }
+func (nopSpan) SetAttr(string, string) {}
+func (nopSpan) Finish(int32)           {}
+
+// SetTracer sets the tracer recording the spans of actor invocations and
+// cross-node calls, attached by the trace component. Without a tracer the
+// trace ids are only propagated.
+func (p *System) SetTracer(tracer ITracer) {
+	if tracer != nil {
+		p.tracer.Store(&tracer)
+	}
+}
+
+// startSpan starts a span with the tracer, a nopSpan if it is not recorded.
+func (p *System) startSpan(traceID, parentID, name string) ISpan {
+	if tracer := p.tracer.Load(); tracer != nil {
+		if span := (*tracer).Start(traceID, parentID, name); span != nil {
+			return span
+		}
+	}
+
+	return nopSpan{}
+}
+
+// startSpan starts the span of the invocation of m and makes it the parent
+// of the calls made while m is processed. It returns a nopSpan if m is not
+// traced.
+func (p *Actor) startSpan(mb *mailbox, m *cfacade.Message) ISpan {
+	traceID, parentID := m.TraceID, m.SpanID
+	if traceID == "" && m.Session != nil {
+		traceID, parentID = m.Session.TraceId, m.Session.SpanId
+	}
+
+	if traceID == "" {
+		return nopSpan{}
+	}
+
+	p.origin.traceID, p.origin.spanID = traceID, parentID
+
+	span := p.system.startSpan(traceID, parentID, mb.name+":"+m.FuncName)
+	span.SetAttr("actor", p.path.String())
+	span.SetAttr("source", m.Source)
+	if spanID := span.GetSpanID(); spanID != "" {
+		p.origin.spanID = spanID
+	}
+
+	return span
+}
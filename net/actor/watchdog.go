@@ -0,0 +1,302 @@
+package cherryActor
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	cutils "github.com/cherry-game/cherry/extend/utils"
+)
+
+/**
+- The watchdog reports handlers still running after the limit, e.g. a deadlock
+  or a cycle of CallWait, with the stack of the actor goroutine.
+- Each stuck message is reported once, then the policy applies:
+	- WatchdogLog only logs.
+	- WatchdogRestart detaches the stuck actor and starts a fresh handler from
+	  IActorFactory, or lets the factory of the system (or parent) activate it
+	  on the next message. The stuck goroutine exits once it returns.
+	- WatchdogExit kills the process.
+*/
+
+const (
+	WatchdogLog WatchdogPolicy = iota
+	WatchdogRestart
+	WatchdogExit
+)
+
+type (
+	// WatchdogPolicy is applied to an actor whose handler exceeds the watchdog limit.
+	WatchdogPolicy int
+
+	// inflight tracks the message being processed by an actor.
+	inflight struct {
+		mu       sync.Mutex
+		start    int64  // unix ms, 0 when idle
+		funcName string // function, event or "timer"/"task"
+		source   string // message source
+		reported bool   // reported by the watchdog
+	}
+
+	stuckActor struct {
+		actor    *Actor
+		elapsed  int64
+		funcName string
+		source   string
+	}
+)
+
+func (p WatchdogPolicy) String() string {
+	switch p {
+	case WatchdogLog:
+		return "log"
+	case WatchdogRestart:
+		return "restart"
+	case WatchdogExit:
+		return "exit"
+	}
+	return "unknown"
+}
+
+// watched reports whether the watchdog tracks the messages of the actor.
+// The limit is set before startup, so it is read without a lock.
+func (p *Actor) watched() bool {
+	return p.system.watchdogLimit > 0
+}
+
+func (p *inflight) begin(funcName, source string) {
+	p.mu.Lock()
+	p.start = time.Now().UnixMilli()
+	p.funcName = funcName
+	p.source = source
+	p.reported = false
+	p.mu.Unlock()
+}
+
+func (p *inflight) end() {
+	p.mu.Lock()
+	p.start = 0
+	p.mu.Unlock()
+}
+
+// stuck returns the message processed for longer than limit, once per message.
+func (p *inflight) stuck(now, limit int64) (stuckActor, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.start == 0 || p.reported || now-p.start < limit {
+		return stuckActor{}, false
+	}
+
+	p.reported = true
+	return stuckActor{elapsed: now - p.start, funcName: p.funcName, source: p.source}, true
+}
+
+// SetWatchdog reports handlers running longer than limit and applies policy
+// to their actors. limit <= 0 disables the watchdog. Call it before startup.
+func (p *System) SetWatchdog(limit time.Duration, policy WatchdogPolicy) {
+	p.watchdogLimit = limit
+	p.watchdogPolicy = policy
+}
+
+func (p *System) watchdog() {
+	interval := p.watchdogLimit / 2
+	if interval > time.Second {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			cutils.Try(p.checkStuck, func(errString string) {
+				p.logger().Errorf("[watchdog] check error. err = %s", errString)
+			})
+		case <-p.watchdogDie:
+			return
+		}
+	}
+}
+
+func (p *System) checkStuck() {
+	var (
+		now   = time.Now().UnixMilli()
+		limit = p.watchdogLimit.Milliseconds()
+		list  []stuckActor
+	)
+
+	check := func(thisActor *Actor) {
+		if stuck, ok := thisActor.inflight.stuck(now, limit); ok {
+			stuck.actor = thisActor
+			list = append(list, stuck)
+		}
+	}
+
+	p.actorMap.Range(func(_, value any) bool {
+		thisActor := value.(*Actor)
+		check(thisActor)
+
+		thisActor.child.childActors.Range(func(_, child any) bool {
+			check(child.(*Actor))
+			return true
+		})
+		return true
+	})
+
+	if len(list) < 1 {
+		return
+	}
+
+	stacks := allStacks()
+
+	for _, stuck := range list {
+		p.logger().Errorf("[watchdog] actor stuck for %dms. policy=%s path=%s func=%s source=%s\n%s",
+			stuck.elapsed,
+			p.watchdogPolicy,
+			stuck.actor.path,
+			stuck.funcName,
+			stuck.source,
+			goroutineStack(stacks, stuck.actor.goid.Load()),
+		)
+	}
+
+	switch p.watchdogPolicy {
+	case WatchdogRestart:
+		for _, stuck := range list {
+			p.replace(stuck.actor)
+		}
+	case WatchdogExit:
+		p.logger().Fatalf("[watchdog] %d actor(s) stuck, exit process.", len(list))
+	}
+}
+
+// replace detaches a stuck actor and starts a new handler on its path.
+// The handler may be replaced by a restart on the actor goroutine, so the
+// factory captured at creation is used.
+func (p *System) replace(thisActor *Actor) {
+	factory := thisActor.factory
+	canCreate := factory != nil
+
+	if !canCreate {
+		hasFactory := p.factory != nil
+		if thisActor.path.IsChild() {
+			parent, found := p.GetActor(thisActor.path.ActorID)
+			hasFactory = found && parent.child.factory != nil
+		}
+
+		if !hasFactory {
+			p.logger().Warnf("[watchdog] no factory to restart the stuck actor. path=%s", thisActor.path)
+			return
+		}
+	}
+
+	thisActor.abandon()
+
+	if !canCreate {
+		return // activated by the factory on the next message
+	}
+
+	handler := factory.NewHandler()
+	if handler == nil {
+		return
+	}
+
+	if thisActor.path.IsParent() {
+		if _, err := p.CreateActor(thisActor.path.ActorID, handler); err != nil {
+			p.logger().Warnf("[watchdog] restart actor fail. path=%s, err=%v", thisActor.path, err)
+		}
+		return
+	}
+
+	if parent, found := p.GetActor(thisActor.path.ActorID); found {
+		childID := thisActor.path.ChildID
+		parent.runTask(func() {
+			if _, err := parent.child.Create(childID, handler); err != nil {
+				p.logger().Warnf("[watchdog] restart child actor fail. path=%s, err=%v", thisActor.path, err)
+			}
+		})
+	}
+}
+
+// abandon detaches the actor so that a new actor can take its path while its
+// goroutine is stuck. The goroutine exits once it returns to the loop, without
+// touching the registries now owned by the new actor.
+func (p *Actor) abandon() {
+	if !p.abandoned.CompareAndSwap(false, true) {
+		return
+	}
+
+	if p.path.IsParent() {
+		p.system.actorMap.CompareAndDelete(p.path.ActorID, p)
+		p.child.childActors.Range(func(_, child any) bool {
+			child.(*Actor).abandon()
+			return true
+		})
+	} else if parent, found := p.system.GetActor(p.path.ActorID); found {
+		parent.child.childActors.CompareAndDelete(p.path.ChildID, p)
+	}
+
+	// the stuck goroutine must not block System.Stop
+	p.system.wg.Done()
+
+	select {
+	case p.close <- struct{}{}:
+	default:
+	}
+}
+
+// onAbandonedStop releases an abandoned actor. Queued messages are
+// redelivered to the actor that took its path.
+func (p *Actor) onAbandonedStop() {
+	cutils.Try(func() {
+		p.redeliver()
+		p.timer.onStop()
+		p.localMail.onStop()
+		p.remoteMail.onStop()
+	}, func(errString string) {
+		p.logger().Error(errString)
+	})
+}
+
+// allStacks returns the stacks of all goroutines.
+func allStacks() []byte {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= 64<<20 {
+			return buf[:n]
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+}
+
+// goroutineStack returns the stack of goroutine goid from the output of allStacks.
+func goroutineStack(stacks []byte, goid int64) []byte {
+	prefix := []byte("goroutine " + strconv.FormatInt(goid, 10) + " [")
+
+	for _, stack := range bytes.Split(stacks, []byte("\n\n")) {
+		if bytes.HasPrefix(stack, prefix) {
+			return stack
+		}
+	}
+
+	return []byte("goroutine " + strconv.FormatInt(goid, 10) + " not found")
+}
+
+// currentGoroutineID parses the id of the calling goroutine from its stack.
+func currentGoroutineID() int64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+
+	field := bytes.Fields(buf[:n])
+	if len(field) < 2 {
+		return 0
+	}
+
+	id, _ := strconv.ParseInt(string(field[1]), 10, 64)
+	return id
+}
@@ -0,0 +1,27 @@
+package cherryActor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func blockForWatchdog(started chan<- int64, release <-chan struct{}) {
+	started <- currentGoroutineID()
+	<-release
+}
+
+// TestWatchdog_GoroutineStack verifies that the stack of a goroutine is
+// extracted from the dump of all goroutines.
+func TestWatchdog_GoroutineStack(t *testing.T) {
+	started := make(chan int64)
+	release := make(chan struct{})
+	defer close(release)
+
+	go blockForWatchdog(started, release)
+	goid := <-started
+
+	stack := goroutineStack(allStacks(), goid)
+	if !bytes.Contains(stack, []byte("blockForWatchdog")) {
+		t.Fatalf("expected stack of goroutine %d, got %s", goid, stack)
+	}
+}
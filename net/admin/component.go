@@ -0,0 +1,277 @@
+package cherryAdmin
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	cfacade "github.com/cherry-game/cherry/facade"
+	cactor "github.com/cherry-game/cherry/net/actor"
+	jsoniter "github.com/json-iterator/go"
+)
+
+/**
+- The admin component serves the probes and introspection of a node over HTTP:
+	- /healthz    200 while the process serves HTTP (liveness).
+	- /readyz     200 once the application is running (every component passed
+	              OnAfterInit), the node is serving and discovery has members of
+	              the required node types.
+	- /drain      POST puts the node into draining before a rolling deploy: it
+	              is skipped by routing and hands off its shards and singletons.
+	- /components registered components and their dependencies.
+	- /members    discovery members.
+- Packages add their own JSON dumps through Find(app).Handle(...), e.g.
+  /actors (actor system) and /agents (net parsers).
+*/
+
+var (
+	Name = "admin_component"
+)
+
+type (
+	Component struct {
+		cfacade.Component
+		address       string
+		requiredTypes []string
+		listener      net.Listener
+		server        *http.Server
+		mu            sync.Mutex
+		dumps         map[string]DumpFunc
+	}
+
+	// DumpFunc returns the value served as JSON by an introspection endpoint.
+	DumpFunc func() any
+
+	componentInfo struct {
+		Name         string   `json:"name"`
+		Dependencies []string `json:"dependencies,omitempty"`
+	}
+
+	memberInfo struct {
+		NodeID   string            `json:"nodeId"`
+		NodeType string            `json:"nodeType"`
+		Address  string            `json:"address"`
+		Status   string            `json:"status"`
+		Settings map[string]string `json:"settings,omitempty"`
+	}
+)
+
+var statusNames = map[int32]string{
+	cfacade.MemberServing:  "serving",
+	cfacade.MemberJoining:  "joining",
+	cfacade.MemberDraining: "draining",
+	cfacade.MemberLeaving:  "leaving",
+}
+
+// New creates the admin component listening on address, e.g. ":9200".
+// The node is ready only when discovery has members of every requiredTypes.
+func New(address string, requiredTypes ...string) *Component {
+	return &Component{
+		address:       address,
+		requiredTypes: requiredTypes,
+		dumps:         make(map[string]DumpFunc),
+	}
+}
+
+func (c *Component) Name() string {
+	return Name
+}
+
+// Handle serves the value returned by fn as JSON at /name.
+func (c *Component) Handle(name string, fn DumpFunc) {
+	if c == nil || name == "" || fn == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dumps[strings.Trim(name, "/")] = fn
+}
+
+func (c *Component) InitE() error {
+	listener, err := net.Listen("tcp", c.address)
+	if err != nil {
+		return err
+	}
+	c.listener = listener
+
+	c.Handle("components", c.components)
+	c.Handle("members", c.members)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", c.healthz)
+	mux.HandleFunc("/readyz", c.readyz)
+	mux.HandleFunc("/drain", c.drain)
+	mux.HandleFunc("/", c.dump)
+
+	c.server = &http.Server{
+		Handler:     mux,
+		ReadTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := c.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			c.App().Logger().Warnf("[%s] serve error. err = %v", Name, err)
+		}
+	}()
+
+	c.App().Logger().Infof("[%s] serve admin at %s", Name, listener.Addr())
+	return nil
+}
+
+// OnAfterInit serves the dump of the actor system at /actors.
+func (c *Component) OnAfterInit() {
+	if system := cactor.Find(c.App()); system != nil {
+		c.Handle("actors", func() any {
+			return system.Dump(time.Second)
+		})
+	}
+}
+
+// Addr returns the listening address, nil before Init.
+func (c *Component) Addr() net.Addr {
+	if c.listener == nil {
+		return nil
+	}
+
+	return c.listener.Addr()
+}
+
+func (c *Component) OnStop() {
+	if c.server == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := c.server.Shutdown(ctx); err != nil {
+		c.App().Logger().Warnf("[%s] shutdown error. err = %v", Name, err)
+	}
+}
+
+// Ready reports whether the node can receive traffic, and the reason if not.
+func (c *Component) Ready() (bool, string) {
+	if !c.App().Running() {
+		return false, "application is not running"
+	}
+
+	discovery := c.App().Discovery()
+	if discovery != nil {
+		if member, found := discovery.GetMember(c.App().NodeID()); found && member.GetStatus() != cfacade.MemberServing {
+			return false, "member is " + statusNames[member.GetStatus()]
+		}
+	}
+
+	for _, nodeType := range c.requiredTypes {
+		if discovery == nil {
+			return false, "discovery is disabled"
+		}
+
+		if len(discovery.ListByType(nodeType)) < 1 {
+			return false, "no member of node type " + nodeType
+		}
+	}
+
+	return true, ""
+}
+
+func (c *Component) healthz(w http.ResponseWriter, _ *http.Request) {
+	w.Write([]byte("ok"))
+}
+
+func (c *Component) readyz(w http.ResponseWriter, _ *http.Request) {
+	if ready, reason := c.Ready(); !ready {
+		http.Error(w, reason, http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Write([]byte("ok"))
+}
+
+func (c *Component) drain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if c.App().Discovery() == nil {
+		http.Error(w, "discovery is disabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	c.App().Discovery().SetStatus(cfacade.MemberDraining)
+	w.Write([]byte("ok"))
+}
+
+func (c *Component) dump(w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(r.URL.Path, "/")
+
+	c.mu.Lock()
+	fn, found := c.dumps[name]
+	c.mu.Unlock()
+
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := jsoniter.Marshal(fn())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func (c *Component) components() any {
+	var list []componentInfo
+	for _, component := range c.App().All() {
+		info := componentInfo{Name: component.Name()}
+		if d, ok := component.(cfacade.IComponentDependency); ok {
+			info.Dependencies = d.Dependencies()
+		}
+		list = append(list, info)
+	}
+
+	return list
+}
+
+func (c *Component) members() any {
+	list := []memberInfo{}
+	if c.App().Discovery() == nil {
+		return list
+	}
+
+	for _, member := range c.App().Discovery().Map() {
+		list = append(list, memberInfo{
+			NodeID:   member.GetNodeID(),
+			NodeType: member.GetNodeType(),
+			Address:  member.GetAddress(),
+			Status:   statusNames[member.GetStatus()],
+			Settings: member.GetSettings(),
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].NodeID < list[j].NodeID
+	})
+
+	return list
+}
+
+// Find returns the admin component registered in app, or nil. Handle is a
+// no-op on a nil component.
+func Find(app cfacade.IApplication) *Component {
+	component, _ := app.Find(Name).(*Component)
+	return component
+}
@@ -0,0 +1,88 @@
+package cherryAdmin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cfacade "github.com/cherry-game/cherry/facade"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+// testApp implements the parts of cfacade.IApplication used by the handlers.
+type testApp struct {
+	cfacade.IApplication
+	discovery cfacade.IDiscovery
+}
+
+func (a *testApp) NodeID() string                { return "game-1" }
+func (a *testApp) Running() bool                 { return true }
+func (a *testApp) Discovery() cfacade.IDiscovery { return a.discovery }
+
+// testDiscovery holds the member of the current node.
+type testDiscovery struct {
+	cfacade.IDiscovery
+	member *cproto.Member
+}
+
+func (d *testDiscovery) GetMember(string) (cfacade.IMember, bool) {
+	return d.member, true
+}
+
+func (d *testDiscovery) SetStatus(status int32) {
+	d.member.Status = status
+}
+
+func newTestComponent(discovery cfacade.IDiscovery) *Component {
+	c := New(":0")
+	c.Set(&testApp{discovery: discovery})
+	return c
+}
+
+func serve(handler http.HandlerFunc, method string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(method, "/drain", nil))
+	return recorder
+}
+
+// TestComponent_Drain verifies that POST /drain puts the node into draining
+// and that the node is no longer ready afterwards.
+func TestComponent_Drain(t *testing.T) {
+	discovery := &testDiscovery{member: &cproto.Member{NodeID: "game-1", Status: cfacade.MemberServing}}
+	c := newTestComponent(discovery)
+
+	if ready, reason := c.Ready(); !ready {
+		t.Fatalf("expected ready, got %s", reason)
+	}
+
+	if rsp := serve(c.drain, http.MethodPost); rsp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rsp.Code)
+	}
+
+	if discovery.member.Status != cfacade.MemberDraining {
+		t.Fatalf("expected draining, got status %d", discovery.member.Status)
+	}
+
+	if ready, reason := c.Ready(); ready || reason != "member is draining" {
+		t.Fatalf("expected not ready while draining, got %v %s", ready, reason)
+	}
+}
+
+// TestComponent_DrainRejected verifies that /drain only accepts POST and
+// requires discovery.
+func TestComponent_DrainRejected(t *testing.T) {
+	discovery := &testDiscovery{member: &cproto.Member{NodeID: "game-1", Status: cfacade.MemberServing}}
+
+	rsp := serve(newTestComponent(discovery).drain, http.MethodGet)
+	if rsp.Code != http.StatusMethodNotAllowed || rsp.Header().Get("Allow") != http.MethodPost {
+		t.Fatalf("expected 405 allowing POST, got %d", rsp.Code)
+	}
+
+	if discovery.member.Status != cfacade.MemberServing {
+		t.Fatalf("GET should not drain, got status %d", discovery.member.Status)
+	}
+
+	if rsp = serve(newTestComponent(nil).drain, http.MethodPost); rsp.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without discovery, got %d", rsp.Code)
+	}
+}
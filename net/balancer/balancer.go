@@ -0,0 +1,223 @@
+package cherryBalancer
+
+import (
+	"hash/crc32"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	cfacade "github.com/cherry-game/cherry/facade"
+)
+
+// Built-in strategy names, used in the profile.
+const (
+	Random         = "random"
+	RoundRobin     = "round_robin"
+	Weighted       = "weighted"
+	LeastLoaded    = "least_loaded"
+	ConsistentHash = "consistent_hash"
+)
+
+// Member settings read by the strategies.
+const (
+	SettingWeight = "weight" // weight of the member, default 1, <= 0 excludes it
+	SettingLoad   = "load"   // current load of the member, default 0
+)
+
+type (
+	// IBalancer chooses one member of a node type. members is never empty,
+	// key identifies the sender (session uid, caller path) for sticky strategies.
+	IBalancer interface {
+		Select(members []cfacade.IMember, key string) cfacade.IMember
+	}
+
+	// Factory creates a balancer, every node type gets its own instance.
+	Factory func() IBalancer
+)
+
+var (
+	factoryMu sync.RWMutex
+	factories = map[string]Factory{
+		Random:         func() IBalancer { return &randomBalancer{} },
+		RoundRobin:     func() IBalancer { return &roundRobinBalancer{} },
+		Weighted:       func() IBalancer { return &weightedBalancer{} },
+		LeastLoaded:    func() IBalancer { return &leastLoadedBalancer{} },
+		ConsistentHash: func() IBalancer { return &consistentHashBalancer{replicas: 100} },
+	}
+)
+
+// Register adds a strategy selectable by name in the profile.
+func Register(name string, factory Factory) {
+	factoryMu.Lock()
+	defer factoryMu.Unlock()
+
+	factories[name] = factory
+}
+
+// NewBalancer creates the balancer of the strategy name.
+func NewBalancer(name string) (IBalancer, bool) {
+	factoryMu.RLock()
+	defer factoryMu.RUnlock()
+
+	factory, found := factories[name]
+	if !found {
+		return nil, false
+	}
+
+	return factory(), true
+}
+
+type (
+	randomBalancer struct{}
+
+	roundRobinBalancer struct {
+		next atomic.Uint64
+	}
+
+	weightedBalancer struct{}
+
+	leastLoadedBalancer struct{}
+
+	consistentHashBalancer struct {
+		replicas int
+		mu       sync.Mutex
+		nodeIDs  string   // members of the current ring, joined
+		hashes   []uint32 // sorted virtual node hashes
+		owners   map[uint32]string
+	}
+)
+
+func (*randomBalancer) Select(members []cfacade.IMember, _ string) cfacade.IMember {
+	return members[rand.IntN(len(members))]
+}
+
+func (p *roundRobinBalancer) Select(members []cfacade.IMember, _ string) cfacade.IMember {
+	sorted := sortMembers(members)
+	return sorted[(p.next.Add(1)-1)%uint64(len(sorted))]
+}
+
+func (*weightedBalancer) Select(members []cfacade.IMember, _ string) cfacade.IMember {
+	var total float64
+	weights := make([]float64, len(members))
+	for i, member := range members {
+		weights[i] = setting(member, SettingWeight, 1)
+		if weights[i] > 0 {
+			total += weights[i]
+		}
+	}
+
+	if total <= 0 {
+		return members[rand.IntN(len(members))]
+	}
+
+	n := rand.Float64() * total
+	for i, weight := range weights {
+		if weight <= 0 {
+			continue
+		}
+		if n -= weight; n < 0 {
+			return members[i]
+		}
+	}
+
+	return members[len(members)-1]
+}
+
+// Select returns the member with the lowest load, a random one among ties.
+func (*leastLoadedBalancer) Select(members []cfacade.IMember, _ string) cfacade.IMember {
+	var (
+		least []cfacade.IMember
+		min   float64
+	)
+
+	for _, member := range members {
+		load := setting(member, SettingLoad, 0)
+		switch {
+		case least == nil || load < min:
+			least, min = []cfacade.IMember{member}, load
+		case load == min:
+			least = append(least, member)
+		}
+	}
+
+	return least[rand.IntN(len(least))]
+}
+
+// Select returns the member owning key on a hash ring with replicas virtual
+// nodes per member, so a member joining or leaving only moves its own keys.
+func (p *consistentHashBalancer) Select(members []cfacade.IMember, key string) cfacade.IMember {
+	sorted := sortMembers(members)
+
+	p.mu.Lock()
+	p.build(sorted)
+	h := crc32.ChecksumIEEE([]byte(key))
+	i := sort.Search(len(p.hashes), func(i int) bool { return p.hashes[i] >= h })
+	if i == len(p.hashes) {
+		i = 0
+	}
+	nodeID := p.owners[p.hashes[i]]
+	p.mu.Unlock()
+
+	for _, member := range sorted {
+		if member.GetNodeID() == nodeID {
+			return member
+		}
+	}
+
+	return sorted[0]
+}
+
+// build rebuilds the ring when the members changed.
+func (p *consistentHashBalancer) build(sorted []cfacade.IMember) {
+	nodeIDs := ""
+	for _, member := range sorted {
+		nodeIDs += member.GetNodeID() + ","
+	}
+
+	if nodeIDs == p.nodeIDs {
+		return
+	}
+
+	p.nodeIDs = nodeIDs
+	p.hashes = p.hashes[:0]
+	p.owners = make(map[uint32]string, len(sorted)*p.replicas)
+	for _, member := range sorted {
+		for i := 0; i < p.replicas; i++ {
+			h := crc32.ChecksumIEEE([]byte(strconv.Itoa(i) + "#" + member.GetNodeID()))
+			if _, found := p.owners[h]; found {
+				continue
+			}
+			p.owners[h] = member.GetNodeID()
+			p.hashes = append(p.hashes, h)
+		}
+	}
+
+	sort.Slice(p.hashes, func(i, j int) bool { return p.hashes[i] < p.hashes[j] })
+}
+
+// sortMembers returns members ordered by nodeID, discovery lists them in no order.
+func sortMembers(members []cfacade.IMember) []cfacade.IMember {
+	sorted := make([]cfacade.IMember, len(members))
+	copy(sorted, members)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].GetNodeID() < sorted[j].GetNodeID()
+	})
+	return sorted
+}
+
+// setting returns the numeric member setting key, or defaultVal.
+func setting(member cfacade.IMember, key string, defaultVal float64) float64 {
+	value, found := member.GetSettings()[key]
+	if !found {
+		return defaultVal
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return defaultVal
+	}
+
+	return f
+}
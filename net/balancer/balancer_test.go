@@ -0,0 +1,99 @@
+package cherryBalancer
+
+import (
+	"strconv"
+	"testing"
+
+	cfacade "github.com/cherry-game/cherry/facade"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+func newMembers(settings ...map[string]string) []cfacade.IMember {
+	var members []cfacade.IMember
+	for i, s := range settings {
+		members = append(members, &cproto.Member{
+			NodeID:   "game-" + strconv.Itoa(i+1),
+			NodeType: "game",
+			Settings: s,
+		})
+	}
+	return members
+}
+
+func selectCount(balancer IBalancer, members []cfacade.IMember, n int) map[string]int {
+	counts := map[string]int{}
+	for i := 0; i < n; i++ {
+		counts[balancer.Select(members, strconv.Itoa(i)).GetNodeID()]++
+	}
+	return counts
+}
+
+// TestRoundRobin verifies that members are chosen in turn whatever their order.
+func TestRoundRobin(t *testing.T) {
+	balancer, _ := NewBalancer(RoundRobin)
+	members := newMembers(nil, nil, nil)
+
+	for i := 0; i < 6; i++ {
+		expected := "game-" + strconv.Itoa(i%3+1)
+		if nodeID := balancer.Select([]cfacade.IMember{members[2], members[0], members[1]}, "").GetNodeID(); nodeID != expected {
+			t.Fatalf("expected %s, got %s", expected, nodeID)
+		}
+	}
+}
+
+// TestWeighted verifies that members are chosen in proportion to their
+// weight and that a weight of 0 excludes a member.
+func TestWeighted(t *testing.T) {
+	balancer, _ := NewBalancer(Weighted)
+	members := newMembers(
+		map[string]string{SettingWeight: "3"},
+		map[string]string{SettingWeight: "1"},
+		map[string]string{SettingWeight: "0"},
+	)
+
+	counts := selectCount(balancer, members, 4000)
+	if counts["game-3"] != 0 {
+		t.Fatalf("member with weight 0 selected %d times", counts["game-3"])
+	}
+
+	if ratio := float64(counts["game-1"]) / float64(counts["game-2"]); ratio < 2.5 || ratio > 3.5 {
+		t.Fatalf("unexpected ratio %.2f. counts = %v", ratio, counts)
+	}
+}
+
+// TestLeastLoaded verifies that the member with the lowest load is chosen.
+func TestLeastLoaded(t *testing.T) {
+	balancer, _ := NewBalancer(LeastLoaded)
+	members := newMembers(
+		map[string]string{SettingLoad: "0.8"},
+		map[string]string{SettingLoad: "0.2"},
+		map[string]string{SettingLoad: "0.5"},
+	)
+
+	if counts := selectCount(balancer, members, 100); counts["game-2"] != 100 {
+		t.Fatalf("expected game-2 only, got %v", counts)
+	}
+}
+
+// TestConsistentHash verifies that a key keeps its member, and that removing
+// a member only moves the keys it owned.
+func TestConsistentHash(t *testing.T) {
+	balancer, _ := NewBalancer(ConsistentHash)
+	members := newMembers(nil, nil, nil)
+
+	owners := map[string]string{}
+	for i := 0; i < 1000; i++ {
+		key := strconv.Itoa(i)
+		owners[key] = balancer.Select(members, key).GetNodeID()
+		if nodeID := balancer.Select(members, key).GetNodeID(); nodeID != owners[key] {
+			t.Fatalf("[key = %s] expected %s, got %s", key, owners[key], nodeID)
+		}
+	}
+
+	for key, owner := range owners {
+		nodeID := balancer.Select(members[:2], key).GetNodeID()
+		if owner != "game-3" && nodeID != owner {
+			t.Fatalf("[key = %s] moved from %s to %s", key, owner, nodeID)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package cherryBalancer
+
+import (
+	"sync"
+
+	cfacade "github.com/cherry-game/cherry/facade"
+	cactor "github.com/cherry-game/cherry/net/actor"
+)
+
+/**
+- The balancer chooses the member of a node type that receives a message:
+	- CallType calls one member instead of publishing to every member.
+	  The key is the caller actor path, or the actor id for System.CallType.
+	- The pomelo and simple parsers forward client messages to it instead of
+	  a random member. The key is the session uid.
+- Node types without a strategy keep the default behavior.
+- Strategies are selected per node type in the profile:
+	"cluster": {
+	  "balancer": {"game": "consistent_hash", "chat": "least_loaded"}
+	}
+  Weights and loads are read from the member settings "weight" and "load".
+*/
+
+var (
+	Name = "balancer_component"
+)
+
+type Component struct {
+	cfacade.Component
+	mu        sync.RWMutex
+	balancers map[string]IBalancer // key:nodeType
+}
+
+func New() *Component {
+	return &Component{
+		balancers: make(map[string]IBalancer),
+	}
+}
+
+func (c *Component) Name() string {
+	return Name
+}
+
+// Init loads the strategies of "cluster.balancer" in the profile.
+func (c *Component) Init() {
+	config := c.App().Profile().GetConfig("cluster").GetConfig("balancer")
+	for _, nodeType := range config.Keys() {
+		name := config.GetString(nodeType)
+		balancer, found := NewBalancer(name)
+		if !found {
+			c.App().Logger().Warnf("[%s] strategy not found. [nodeType = %s, strategy = %s]", Name, nodeType, name)
+			continue
+		}
+
+		c.SetBalancer(nodeType, balancer)
+	}
+}
+
+// OnAfterInit lets the CallType of the actor system use the balancer.
+func (c *Component) OnAfterInit() {
+	if system := cactor.Find(c.App()); system != nil {
+		system.SetBalancer(c)
+	}
+}
+
+// SetBalancer uses balancer for the members of nodeType.
+func (c *Component) SetBalancer(nodeType string, balancer IBalancer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.balancers[nodeType] = balancer
+}
+
+// Select chooses a member of nodeType. found is false when nodeType has no
+// strategy or no member, the caller then falls back to its default.
+func (c *Component) Select(nodeType, key string) (cfacade.IMember, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	balancer, found := c.balancers[nodeType]
+	c.mu.RUnlock()
+
+	if !found || c.App().Discovery() == nil {
+		return nil, false
+	}
+
+	members := c.App().Discovery().ListByType(nodeType)
+	if len(members) < 1 {
+		return nil, false
+	}
+
+	member := balancer.Select(members, key)
+	return member, member != nil
+}
+
+// Find returns the balancer component registered in app, or nil.
+func Find(app cfacade.IApplication) *Component {
+	component, _ := app.Find(Name).(*Component)
+	return component
+}
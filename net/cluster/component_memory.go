@@ -0,0 +1,443 @@
+package cherryCluster
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cerror "github.com/cherry-game/cherry/error"
+	cfacade "github.com/cherry-game/cherry/facade"
+	cnats "github.com/cherry-game/cherry/net/nats"
+	cproto "github.com/cherry-game/cherry/net/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	memoryLocal  byte = 1 // packet for the local mailbox
+	memoryRemote byte = 2 // packet for the remote mailbox
+	memoryRaw    byte = 3 // packet for a raw subject handler
+)
+
+type (
+	// MemoryComponent is an in-process cluster transport (mode "memory").
+	// Every node started in the same process registers itself in a shared hub;
+	// messages are marshaled exactly like the nats transport and delivered
+	// through the target node's inbox channel, so cluster scenarios run
+	// without an external broker.
+	//
+	// Select it with AppBuilder.SetCluster(cherryCluster.NewMemory()).
+	// Optional profile config "cluster.memory":
+	//   - chan_size: inbox channel size per node, default 1024
+	//   - request_timeout: request timeout in seconds, default 1
+	MemoryComponent struct {
+		cfacade.Component
+		hub            *memoryHub
+		inbox          chan *memoryPacket // packets delivered to this node
+		replySubject   string             // reply subject attached to requests
+		requestTimeout time.Duration      // default request timeout
+		die            chan struct{}      // closed on OnStop
+	}
+
+	// MemoryHandler receives a packet published to a raw subject. For a
+	// request, reqID and reply are set and the answer is sent back through
+	// ICluster.RequestReply(reqID, reply, data).
+	MemoryHandler func(reqID, reply string, data []byte)
+
+	memoryPacket struct {
+		kind    byte          // memoryLocal, memoryRemote or memoryRaw
+		data    []byte        // marshaled message or raw payload
+		reqID   string        // request id, empty for publish
+		reply   string        // reply subject, empty for publish
+		handler MemoryHandler // raw subject handler
+	}
+
+	memorySubscriber struct {
+		owner   *MemoryComponent
+		handler MemoryHandler
+	}
+
+	// memoryHub connects the memory transports of all nodes in the process.
+	memoryHub struct {
+		sync.RWMutex
+		nodes       map[string]*MemoryComponent    // key:nodeID
+		subscribers map[string][]*memorySubscriber // key:raw subject
+		waiters     sync.Map                       // key:reqID, value:chan []byte
+	}
+)
+
+// defaultMemoryHub is shared by every MemoryComponent in the process.
+var defaultMemoryHub = &memoryHub{
+	nodes:       make(map[string]*MemoryComponent),
+	subscribers: make(map[string][]*memorySubscriber),
+}
+
+func NewMemory() *MemoryComponent {
+	return &MemoryComponent{
+		hub: defaultMemoryHub,
+	}
+}
+
+func (*MemoryComponent) Name() string {
+	return Name
+}
+
+func (*MemoryComponent) Mode() string {
+	return "memory"
+}
+
+func (p *MemoryComponent) Init() {
+	config := p.App().Profile().GetConfig("cluster").GetConfig(p.Mode())
+
+	p.inbox = make(chan *memoryPacket, config.GetInt("chan_size", 1024))
+	p.requestTimeout = config.GetDuration("request_timeout", 1) * time.Second
+	p.replySubject = fmt.Sprintf("cherry-memory.reply.%s.%s", p.App().NodeType(), p.App().NodeID())
+	p.die = make(chan struct{})
+
+	if err := p.hub.addNode(p.App().NodeID(), p); err != nil {
+		panic(err)
+	}
+
+	go p.process()
+
+	p.App().Logger().Info("Memory cluster execute OnInit().")
+}
+
+func (p *MemoryComponent) OnStop() {
+	p.hub.removeNode(p.App().NodeID(), p)
+	close(p.die)
+
+	p.App().Logger().Info("Memory cluster execute OnStop().")
+}
+
+// Subscribe registers a handler for a raw subject, the in-process counterpart
+// of a nats subscription used by RawPublish, RawRequest and RequestSync.
+func (p *MemoryComponent) Subscribe(subject string, handler MemoryHandler) {
+	if subject == "" || handler == nil {
+		return
+	}
+
+	p.hub.subscribe(subject, &memorySubscriber{owner: p, handler: handler})
+}
+
+func (p *MemoryComponent) process() {
+	for {
+		select {
+		case packet := <-p.inbox:
+			p.dispatch(packet)
+		case <-p.die:
+			return
+		}
+	}
+}
+
+func (p *MemoryComponent) dispatch(packet *memoryPacket) {
+	if packet.kind == memoryRaw {
+		// off the inbox goroutine, the handler may request a subject of this
+		// node and wait for the reply delivered through the same inbox
+		go packet.handler(packet.reqID, packet.reply, packet.data)
+		return
+	}
+
+	msg := cfacade.GetMessage()
+	if err := msg.Unmarshal(packet.data); err != nil {
+		p.App().Logger().Warnf("[dispatch] Unmarshal fail. [dataLen = %d, err = %v]", len(packet.data), err)
+		msg.Recycle()
+		return
+	}
+
+	if packet.kind == memoryLocal {
+		p.App().ActorSystem().PostLocal(msg)
+		return
+	}
+
+	if packet.reply != "" {
+		msg.ReqID = packet.reqID
+		msg.Reply = packet.reply
+	}
+
+	p.App().ActorSystem().PostRemote(msg)
+}
+
+// deliver puts a packet into the node inbox without blocking.
+func (p *MemoryComponent) deliver(packet *memoryPacket) error {
+	select {
+	case p.inbox <- packet:
+		return nil
+	default:
+		return cerror.ClusterPublishFail
+	}
+}
+
+func (p *MemoryComponent) PublishLocal(nodeID string, msg *cfacade.Message) error {
+	defer msg.Recycle()
+
+	return p.publish("PublishLocal", nodeID, memoryLocal, msg)
+}
+
+func (p *MemoryComponent) PublishRemote(nodeID string, msg *cfacade.Message) error {
+	defer msg.Recycle()
+
+	return p.publish("PublishRemote", nodeID, memoryRemote, msg)
+}
+
+func (p *MemoryComponent) publish(tag, nodeID string, kind byte, msg *cfacade.Message) error {
+	if _, found := p.App().Discovery().GetMember(nodeID); !found {
+		p.App().Logger().Warnf("[%s] NodeID not found in discovery. [nodeID = %s]", tag, nodeID)
+		return cerror.DiscoveryNotFoundNode
+	}
+
+	bytes, err := msg.Marshal()
+	if err != nil {
+		p.App().Logger().Warnf("[%s] Marshal error. [nodeID = %s, err = %v]", tag, nodeID, err)
+		return cerror.ClusterPacketMarshalFail
+	}
+
+	node, found := p.hub.getNode(nodeID)
+	if !found {
+		p.App().Logger().Warnf("[%s] Node not started in process. [nodeID = %s]", tag, nodeID)
+		return cerror.ClusterPublishFail
+	}
+
+	if err = node.deliver(&memoryPacket{kind: kind, data: bytes}); err != nil {
+		p.App().Logger().Warnf("[%s] Inbox is full. [nodeID = %s]", tag, nodeID)
+		return err
+	}
+
+	return nil
+}
+
+func (p *MemoryComponent) PublishRemoteType(nodeType string, msg *cfacade.Message) error {
+	defer msg.Recycle()
+
+	if nodeType == "" {
+		return cerror.ClusterNodeTypeIsNil
+	}
+
+	members := p.App().Discovery().ListByType(nodeType)
+	if len(members) < 1 {
+		return cerror.ClusterNodeTypeMemberNotFound
+	}
+
+	bytes, err := msg.Marshal()
+	if err != nil {
+		p.App().Logger().Warnf("[PublishRemoteType] Marshal error. [nodeType = %s, err = %v]", nodeType, err)
+		return cerror.ClusterPacketMarshalFail
+	}
+
+	// same as the nats remoteType subject, every node of the type receives it
+	for _, member := range members {
+		node, found := p.hub.getNode(member.GetNodeID())
+		if !found {
+			continue
+		}
+
+		if err = node.deliver(&memoryPacket{kind: memoryRemote, data: bytes}); err != nil {
+			p.App().Logger().Warnf("[PublishRemoteType] Inbox is full. [nodeID = %s]", member.GetNodeID())
+		}
+	}
+
+	// the current node may not be in its own discovery list
+	if p.App().NodeType() == nodeType {
+		if _, found := p.App().Discovery().GetMember(p.App().NodeID()); !found {
+			if err = p.deliver(&memoryPacket{kind: memoryRemote, data: bytes}); err != nil {
+				p.App().Logger().Warnf("[PublishRemoteType] Inbox is full. [nodeID = %s]", p.App().NodeID())
+			}
+		}
+	}
+
+	return nil
+}
+
+// RequestRemote sends msg to nodeID and returns the data and the code of the response.
+func (p *MemoryComponent) RequestRemote(nodeID string, msg *cfacade.Message, timeout ...time.Duration) ([]byte, int32) {
+	rsp, code := p.RequestResponse(nodeID, msg, timeout...)
+	return rsp.GetData(), code
+}
+
+// RequestResponse sends msg to nodeID and returns the response, nil if nodeID did not answer.
+func (p *MemoryComponent) RequestResponse(nodeID string, msg *cfacade.Message, timeout ...time.Duration) (*cproto.Response, int32) {
+	defer msg.Recycle()
+
+	if _, found := p.App().Discovery().GetMember(nodeID); !found {
+		p.App().Logger().Warnf("[RequestRemote] NodeID not found in discovery. [nodeID = %s]", nodeID)
+		return nil, ccode.DiscoveryNotFoundNode
+	}
+
+	reqBytes, err := msg.Marshal()
+	if err != nil {
+		p.App().Logger().Warnf("[RequestRemote] Marshal fail. [nodeID = %s, err = %v]", nodeID, err)
+		return nil, ccode.RPCMarshalError
+	}
+
+	node, found := p.hub.getNode(nodeID)
+	if !found {
+		p.App().Logger().Warnf("[RequestRemote] Node not started in process. [nodeID = %s]", nodeID)
+		return nil, ccode.RPCNetError
+	}
+
+	rspData, err := p.request(func(reqID string) error {
+		return node.deliver(&memoryPacket{
+			kind:  memoryRemote,
+			data:  reqBytes,
+			reqID: reqID,
+			reply: p.replySubject,
+		})
+	}, timeout...)
+	if err != nil {
+		p.App().Logger().Warnf("[RequestRemote] Memory request fail. [nodeID = %s, err = %v]", nodeID, err)
+		return nil, ccode.RPCRemoteExecuteError
+	}
+
+	rsp := &cproto.Response{}
+	if err = proto.Unmarshal(rspData, rsp); err != nil {
+		p.App().Logger().Warnf("[RequestRemote] unmarshal fail. [nodeID = %s, rsp = %v, err = %v]", nodeID, rsp, err)
+		return nil, ccode.RPCUnmarshalError
+	}
+
+	if ccode.IsFail(rsp.Code) && rsp.Message != "" {
+		p.App().Logger().Warnf("[RequestRemote] response error. [nodeID = %s, code = %d, message = %s]", nodeID, rsp.Code, rsp.Message)
+	}
+
+	return rsp, rsp.Code
+}
+
+// request registers a waiter for a new reqID, sends the request and blocks
+// until RequestReply answers it or the timeout expires.
+func (p *MemoryComponent) request(send func(reqID string) error, timeout ...time.Duration) ([]byte, error) {
+	reqID := cnats.NewStringReqID()
+
+	ch := make(chan []byte, 1)
+	p.hub.waiters.Store(reqID, ch)
+	defer p.hub.waiters.Delete(reqID)
+
+	if err := send(reqID); err != nil {
+		return nil, err
+	}
+
+	d := p.requestTimeout
+	if len(timeout) > 0 && timeout[0] > 0 {
+		d = timeout[0]
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case data := <-ch:
+		return data, nil
+	case <-timer.C:
+		p.App().Logger().Warnf("[request] timeout. nodeID = %s, reqID = %s", p.App().NodeID(), reqID)
+		return nil, cerror.ClusterRequestTimeout
+	}
+}
+
+func (p *MemoryComponent) RequestSync(subject string, data []byte, timeout ...time.Duration) ([]byte, error) {
+	return p.request(func(reqID string) error {
+		return p.publishRaw(subject, reqID, p.replySubject, data)
+	}, timeout...)
+}
+
+// RequestReply answers the in-flight request with the given reqID.
+// A reply that arrives after the request timed out is discarded.
+func (p *MemoryComponent) RequestReply(reqID, _ string, data []byte) error {
+	value, found := p.hub.waiters.LoadAndDelete(reqID)
+	if !found {
+		return nil
+	}
+
+	value.(chan []byte) <- data
+	return nil
+}
+
+// RawPublish delivers data to every handler subscribed to the raw subject.
+func (p *MemoryComponent) RawPublish(subject string, data []byte) error {
+	return p.publishRaw(subject, "", "", data)
+}
+
+// publishRaw delivers data to the inbox of every node subscribed to subject.
+func (p *MemoryComponent) publishRaw(subject, reqID, reply string, data []byte) error {
+	subscribers := p.hub.getSubscribers(subject)
+	if len(subscribers) < 1 {
+		return cerror.Errorf("memory cluster subject has no subscriber. [subject = %s]", subject)
+	}
+
+	for _, subscriber := range subscribers {
+		packet := &memoryPacket{
+			kind:    memoryRaw,
+			data:    data,
+			reqID:   reqID,
+			reply:   reply,
+			handler: subscriber.handler,
+		}
+
+		if err := subscriber.owner.deliver(packet); err != nil {
+			p.App().Logger().Warnf("[publishRaw] Inbox is full. [subject = %s]", subject)
+		}
+	}
+
+	return nil
+}
+
+// RawRequest sends a request to a raw subject and waits for the first reply.
+func (p *MemoryComponent) RawRequest(subject string, data []byte, timeout ...time.Duration) ([]byte, error) {
+	return p.RequestSync(subject, data, timeout...)
+}
+
+func (h *memoryHub) addNode(nodeID string, node *MemoryComponent) error {
+	h.Lock()
+	defer h.Unlock()
+
+	if _, found := h.nodes[nodeID]; found {
+		return cerror.Errorf("memory cluster nodeID is duplicate. [nodeID = %s]", nodeID)
+	}
+
+	h.nodes[nodeID] = node
+	return nil
+}
+
+func (h *memoryHub) removeNode(nodeID string, node *MemoryComponent) {
+	h.Lock()
+	defer h.Unlock()
+
+	if h.nodes[nodeID] == node {
+		delete(h.nodes, nodeID)
+	}
+
+	for subject, subscribers := range h.subscribers {
+		var list []*memorySubscriber
+		for _, subscriber := range subscribers {
+			if subscriber.owner != node {
+				list = append(list, subscriber)
+			}
+		}
+
+		if len(list) > 0 {
+			h.subscribers[subject] = list
+		} else {
+			delete(h.subscribers, subject)
+		}
+	}
+}
+
+func (h *memoryHub) getNode(nodeID string) (*MemoryComponent, bool) {
+	h.RLock()
+	defer h.RUnlock()
+
+	node, found := h.nodes[nodeID]
+	return node, found
+}
+
+func (h *memoryHub) subscribe(subject string, subscriber *memorySubscriber) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.subscribers[subject] = append(h.subscribers[subject], subscriber)
+}
+
+func (h *memoryHub) getSubscribers(subject string) []*memorySubscriber {
+	h.RLock()
+	defer h.RUnlock()
+
+	return h.subscribers[subject]
+}
@@ -0,0 +1,146 @@
+package cherryCluster
+
+import (
+	"testing"
+	"time"
+
+	cerror "github.com/cherry-game/cherry/error"
+	cfacade "github.com/cherry-game/cherry/facade"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+// testActorSystem passes the posted remote messages to a channel.
+type testActorSystem struct {
+	cfacade.IActorSystem
+	remote chan *cfacade.Message
+}
+
+func (s *testActorSystem) PostRemote(m *cfacade.Message) bool {
+	s.remote <- m
+	return true
+}
+
+// newTestMemory starts a memory transport of nodeID on hub, it knows the
+// members nodeIDs.
+func newTestMemory(t *testing.T, hub *memoryHub, nodeID string, nodeIDs ...string) (*MemoryComponent, *testActorSystem) {
+	discovery := &testDiscovery{members: map[string]cfacade.IMember{}}
+	for _, id := range nodeIDs {
+		discovery.members[id] = &cproto.Member{NodeID: id, NodeType: "game"}
+	}
+
+	system := &testActorSystem{remote: make(chan *cfacade.Message, 16)}
+
+	p := &MemoryComponent{
+		hub:            hub,
+		inbox:          make(chan *memoryPacket, 16),
+		replySubject:   "cherry-memory.reply.game." + nodeID,
+		requestTimeout: time.Second,
+		die:            make(chan struct{}),
+	}
+	p.Set(&testApp{nodeID: nodeID, discovery: discovery, actorSystem: system})
+
+	if err := hub.addNode(nodeID, p); err != nil {
+		t.Fatalf("add node fail. err = %v", err)
+	}
+
+	go p.process()
+	t.Cleanup(p.OnStop)
+
+	return p, system
+}
+
+func newTestMemoryHub() *memoryHub {
+	return &memoryHub{
+		nodes:       make(map[string]*MemoryComponent),
+		subscribers: make(map[string][]*memorySubscriber),
+	}
+}
+
+// TestMemory_Publish verifies that a published message reaches the remote
+// mailbox of the target node only.
+func TestMemory_Publish(t *testing.T) {
+	hub := newTestMemoryHub()
+	game1, system1 := newTestMemory(t, hub, "game-1", "game-2")
+	_, system2 := newTestMemory(t, hub, "game-2", "game-1")
+
+	msg := cfacade.GetMessage()
+	msg.Source = "game-1.player"
+	msg.Target = "game-2.room"
+	msg.FuncName = "join"
+
+	if err := game1.PublishRemote("game-2", msg); err != nil {
+		t.Fatalf("publish fail. err = %v", err)
+	}
+
+	select {
+	case m := <-system2.remote:
+		if m.Target != "game-2.room" || m.FuncName != "join" {
+			t.Fatalf("unexpected message %s %s", m.Target, m.FuncName)
+		}
+		m.Recycle()
+	case <-time.After(time.Second):
+		t.Fatal("the message was not delivered")
+	}
+
+	if len(system1.remote) > 0 {
+		t.Fatal("the message should only reach game-2")
+	}
+
+	if err := game1.PublishRemote("game-3", cfacade.GetMessage()); err != cerror.DiscoveryNotFoundNode {
+		t.Fatalf("expected DiscoveryNotFoundNode, got %v", err)
+	}
+}
+
+// TestMemory_Request verifies that a raw request is answered, also when the
+// handler requests a subject of its own node before replying.
+func TestMemory_Request(t *testing.T) {
+	hub := newTestMemoryHub()
+	game1, _ := newTestMemory(t, hub, "game-1", "game-2")
+	game2, _ := newTestMemory(t, hub, "game-2", "game-1")
+
+	game2.Subscribe("inner", func(reqID, reply string, data []byte) {
+		_ = game2.RequestReply(reqID, reply, append([]byte("inner:"), data...))
+	})
+
+	game2.Subscribe("outer", func(reqID, reply string, data []byte) {
+		rsp, err := game2.RequestSync("inner", data)
+		if err != nil {
+			rsp = []byte(err.Error())
+		}
+		_ = game2.RequestReply(reqID, reply, rsp)
+	})
+
+	rsp, err := game1.RequestSync("outer", []byte("ping"))
+	if err != nil || string(rsp) != "inner:ping" {
+		t.Fatalf("expected inner:ping, got %q, err = %v", rsp, err)
+	}
+}
+
+// TestMemory_RequestTimeout verifies that an unanswered request times out,
+// a late reply is discarded and a subject without subscriber fails.
+func TestMemory_RequestTimeout(t *testing.T) {
+	hub := newTestMemoryHub()
+	game1, _ := newTestMemory(t, hub, "game-1")
+
+	replies := make(chan [2]string, 1)
+	game1.Subscribe("silent", func(reqID, reply string, _ []byte) {
+		replies <- [2]string{reqID, reply}
+	})
+
+	if _, err := game1.RequestSync("silent", nil, 20*time.Millisecond); err != cerror.ClusterRequestTimeout {
+		t.Fatalf("expected ClusterRequestTimeout, got %v", err)
+	}
+
+	late := <-replies
+	if err := game1.RequestReply(late[0], late[1], []byte("late")); err != nil {
+		t.Fatalf("a late reply should be discarded, got %v", err)
+	}
+
+	if _, found := hub.waiters.Load(late[0]); found {
+		t.Fatal("the waiter of the timed out request should be removed")
+	}
+
+	if _, err := game1.RequestSync("unknown", nil); err == nil {
+		t.Fatal("expected an error without subscriber")
+	}
+}
@@ -7,10 +7,8 @@ import (
 	ccode "github.com/cherry-game/cherry/code"
 	cerror "github.com/cherry-game/cherry/error"
 	cfacade "github.com/cherry-game/cherry/facade"
-	clog "github.com/cherry-game/cherry/logger"
 	cnats "github.com/cherry-game/cherry/net/nats"
 	cproto "github.com/cherry-game/cherry/net/proto"
-	cprofile "github.com/cherry-game/cherry/profile"
 	"github.com/nats-io/nats.go"
 	"google.golang.org/protobuf/proto"
 )
@@ -66,7 +64,7 @@ func (p *Component) Init() {
 	p.remoteProcess()
 	p.remoteTypeProcess()
 
-	clog.Info("Nats cluster execute OnInit().")
+	p.App().Logger().Info("Nats cluster execute OnInit().")
 }
 
 func (p *Component) OnStop() {
@@ -76,11 +74,11 @@ func (p *Component) OnStop() {
 	if p.subscribeConnect != nil {
 		p.subscribeConnect.Close()
 	}
-	clog.Info("Nats cluster execute OnStop().")
+	p.App().Logger().Info("Nats cluster execute OnStop().")
 }
 
 func (p *Component) loadNatsConfig() {
-	natsConfig := cprofile.GetConfig("cluster").GetConfig("nats")
+	natsConfig := p.App().Profile().GetConfig("cluster").GetConfig("nats")
 	if natsConfig.LastError() != nil {
 		panic("cluster->nats config not found.")
 	}
@@ -113,7 +111,7 @@ func (p *Component) localProcess() {
 	process := func(natsMsg *nats.Msg) {
 		msg := cfacade.GetMessage()
 		if err := msg.Unmarshal(natsMsg.Data); err != nil {
-			clog.Warnf("[localProcess] Unmarshal fail. [subject = %s, dataLen = %d, err = %s]",
+			p.App().Logger().Warnf("[localProcess] Unmarshal fail. [subject = %s, dataLen = %d, err = %s]",
 				natsMsg.Subject,
 				len(natsMsg.Data),
 				err,
@@ -127,7 +125,7 @@ func (p *Component) localProcess() {
 
 	err := p.subscribeConnect.Subscribe(p.localSubject, process)
 	if err != nil {
-		clog.Errorf("[localProcess] Create subscribe fail. [subject = %s, err = %v]",
+		p.App().Logger().Errorf("[localProcess] Create subscribe fail. [subject = %s, err = %v]",
 			p.localSubject,
 			err,
 		)
@@ -138,7 +136,7 @@ func (p *Component) remoteProcess() {
 	process := func(natsMsg *nats.Msg) {
 		msg := cfacade.GetMessage()
 		if err := msg.Unmarshal(natsMsg.Data); err != nil {
-			clog.Warnf("[remoteProcess] Unmarshal fail. [subject = %s, dataLen = %d, err = %v]",
+			p.App().Logger().Warnf("[remoteProcess] Unmarshal fail. [subject = %s, dataLen = %d, err = %v]",
 				natsMsg.Subject,
 				len(natsMsg.Data),
 				err,
@@ -157,7 +155,7 @@ func (p *Component) remoteProcess() {
 
 	err := p.subscribeConnect.Subscribe(p.remoteSubject, process)
 	if err != nil {
-		clog.Errorf("[remoteProcess] Create subscribe fail. [subject = %s, err = %v]",
+		p.App().Logger().Errorf("[remoteProcess] Create subscribe fail. [subject = %s, err = %v]",
 			p.remoteSubject,
 			err,
 		)
@@ -168,7 +166,7 @@ func (p *Component) remoteTypeProcess() {
 	process := func(natsMsg *nats.Msg) {
 		msg := cfacade.GetMessage()
 		if err := msg.Unmarshal(natsMsg.Data); err != nil {
-			clog.Warnf("[remoteTypeProcess] Unmarshal fail. [subject = %s, dataLen = %d, err = %v]",
+			p.App().Logger().Warnf("[remoteTypeProcess] Unmarshal fail. [subject = %s, dataLen = %d, err = %v]",
 				natsMsg.Subject,
 				len(natsMsg.Data),
 				err,
@@ -182,7 +180,7 @@ func (p *Component) remoteTypeProcess() {
 
 	err := p.subscribeConnect.Subscribe(p.remoteNodeTypeSubject, process)
 	if err != nil {
-		clog.Errorf("[remoteTypeProcess] Create subscribe fail. [subject = %s, err = %v]",
+		p.App().Logger().Errorf("[remoteTypeProcess] Create subscribe fail. [subject = %s, err = %v]",
 			p.remoteSubject,
 			err,
 		)
@@ -194,13 +192,13 @@ func (p *Component) PublishLocal(nodeID string, msg *cfacade.Message) error {
 
 	member, found := p.App().Discovery().GetMember(nodeID)
 	if !found {
-		clog.Warnf("[PublishLocal] NodeID not found in discovery. [nodeID = %s]", nodeID)
+		p.App().Logger().Warnf("[PublishLocal] NodeID not found in discovery. [nodeID = %s]", nodeID)
 		return cerror.DiscoveryNotFoundNode
 	}
 
 	bytes, err := msg.Marshal()
 	if err != nil {
-		clog.Warnf("[PublishLocal] Marshal error. [nodeID = %s, err = %v]",
+		p.App().Logger().Warnf("[PublishLocal] Marshal error. [nodeID = %s, err = %v]",
 			nodeID,
 			err,
 		)
@@ -211,7 +209,7 @@ func (p *Component) PublishLocal(nodeID string, msg *cfacade.Message) error {
 	subject := p.GetLocalSubject(p.prefix, nodeType, nodeID)
 	err = p.publishConnect.Publish(subject, bytes)
 	if err != nil {
-		clog.Warnf("[PublishLocal] Nats publish fail. [nodeID = %s, err = %v]",
+		p.App().Logger().Warnf("[PublishLocal] Nats publish fail. [nodeID = %s, err = %v]",
 			nodeID,
 			err,
 		)
@@ -227,13 +225,13 @@ func (p *Component) PublishRemote(nodeID string, msg *cfacade.Message) error {
 
 	member, found := p.App().Discovery().GetMember(nodeID)
 	if !found {
-		clog.Warnf("[PublishRemote] NodeID not found in discovery. [nodeID = %s]", nodeID)
+		p.App().Logger().Warnf("[PublishRemote] NodeID not found in discovery. [nodeID = %s]", nodeID)
 		return cerror.DiscoveryNotFoundNode
 	}
 
 	bytes, err := msg.Marshal()
 	if err != nil {
-		clog.Warnf("[PublishRemote] Marshal error. [nodeID = %s, err = %v]",
+		p.App().Logger().Warnf("[PublishRemote] Marshal error. [nodeID = %s, err = %v]",
 			nodeID,
 			err,
 		)
@@ -244,7 +242,7 @@ func (p *Component) PublishRemote(nodeID string, msg *cfacade.Message) error {
 	subject := p.GetRemoteSubject(p.prefix, nodeType, nodeID)
 	err = p.publishConnect.Publish(subject, bytes)
 	if err != nil {
-		clog.Warnf("[PublishRemote] Nats publish fail. [nodeID = %s, err = %v]",
+		p.App().Logger().Warnf("[PublishRemote] Nats publish fail. [nodeID = %s, err = %v]",
 			nodeID,
 			err,
 		)
@@ -268,7 +266,7 @@ func (p *Component) PublishRemoteType(nodeType string, msg *cfacade.Message) err
 
 	bytes, err := msg.Marshal()
 	if err != nil {
-		clog.Warnf("[PublishRemoteType] Marshal error. [nodeType = %s, err = %v]",
+		p.App().Logger().Warnf("[PublishRemoteType] Marshal error. [nodeType = %s, err = %v]",
 			nodeType,
 			err,
 		)
@@ -278,7 +276,7 @@ func (p *Component) PublishRemoteType(nodeType string, msg *cfacade.Message) err
 	subject := p.GetRemoteTypeSubject(p.prefix, nodeType)
 	err = p.publishConnect.Publish(subject, bytes)
 	if err != nil {
-		clog.Warnf("[PublishRemoteType] Nats publish fail. [nodeType = %s, err = %v]",
+		p.App().Logger().Warnf("[PublishRemoteType] Nats publish fail. [nodeType = %s, err = %v]",
 			nodeType,
 			err,
 		)
@@ -289,18 +287,25 @@ func (p *Component) PublishRemoteType(nodeType string, msg *cfacade.Message) err
 	return nil
 }
 
+// RequestRemote sends msg to nodeID and returns the data and the code of the response.
 func (p *Component) RequestRemote(nodeID string, msg *cfacade.Message, timeout ...time.Duration) ([]byte, int32) {
+	rsp, code := p.RequestResponse(nodeID, msg, timeout...)
+	return rsp.GetData(), code
+}
+
+// RequestResponse sends msg to nodeID and returns the response, nil if nodeID did not answer.
+func (p *Component) RequestResponse(nodeID string, msg *cfacade.Message, timeout ...time.Duration) (*cproto.Response, int32) {
 	defer msg.Recycle()
 
 	member, found := p.App().Discovery().GetMember(nodeID)
 	if !found {
-		clog.Warnf("[RequestRemote] NodeID not found in discovery. [nodeID = %s]", nodeID)
+		p.App().Logger().Warnf("[RequestRemote] NodeID not found in discovery. [nodeID = %s]", nodeID)
 		return nil, ccode.DiscoveryNotFoundNode
 	}
 
 	reqBytes, err := msg.Marshal()
 	if err != nil {
-		clog.Warnf("[RequestRemote] Marshal fail. [nodeID = %s, err = %v]",
+		p.App().Logger().Warnf("[RequestRemote] Marshal fail. [nodeID = %s, err = %v]",
 			nodeID,
 			err,
 		)
@@ -313,7 +318,7 @@ func (p *Component) RequestRemote(nodeID string, msg *cfacade.Message, timeout .
 
 	natsData, err := p.RequestSync(subject, reqBytes, timeout...)
 	if err != nil {
-		clog.Warnf("[RequestRemote] Nats request fail. [nodeID = %s, err = %v]",
+		p.App().Logger().Warnf("[RequestRemote] Nats request fail. [nodeID = %s, err = %v]",
 			nodeID,
 			err,
 		)
@@ -323,7 +328,7 @@ func (p *Component) RequestRemote(nodeID string, msg *cfacade.Message, timeout .
 
 	rsp := &cproto.Response{}
 	if err = proto.Unmarshal(natsData, rsp); err != nil {
-		clog.Warnf("[RequestRemote] unmarshal fail. [nodeID = %s, rsp = %v, err = %v]",
+		p.App().Logger().Warnf("[RequestRemote] unmarshal fail. [nodeID = %s, rsp = %v, err = %v]",
 			nodeID,
 			rsp,
 			err,
@@ -332,7 +337,11 @@ func (p *Component) RequestRemote(nodeID string, msg *cfacade.Message, timeout .
 		return nil, ccode.RPCUnmarshalError
 	}
 
-	return rsp.Data, rsp.Code
+	if ccode.IsFail(rsp.Code) && rsp.Message != "" {
+		p.App().Logger().Warnf("[RequestRemote] response error. [nodeID = %s, code = %d, message = %s]", nodeID, rsp.Code, rsp.Message)
+	}
+
+	return rsp, rsp.Code
 }
 
 func (p *Component) RequestSync(subject string, data []byte, timeout ...time.Duration) ([]byte, error) {
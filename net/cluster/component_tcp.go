@@ -0,0 +1,774 @@
+package cherryCluster
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cerror "github.com/cherry-game/cherry/error"
+	cfacade "github.com/cherry-game/cherry/facade"
+	cnats "github.com/cherry-game/cherry/net/nats"
+	cproto "github.com/cherry-game/cherry/net/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	tcpFrameHello   byte = 1 // payload: nodeID of the dialing node
+	tcpFrameLocal   byte = 2 // payload: ClusterPacket for the local mailbox
+	tcpFrameRemote  byte = 3 // payload: ClusterPacket for the remote mailbox
+	tcpFrameRequest byte = 4 // payload: ClusterPacket, answered by a reply frame
+	tcpFrameReply   byte = 5 // payload: Response for the request with the same id
+
+	tcpHeadLength  = 4 + 1 + 8        // length(uint32) + type(byte) + reqID(uint64)
+	tcpMaxFrame    = 16 * 1024 * 1024 // max frame length
+	tcpReplyPrefix = "cherry-tcp.reply."
+	tcpRawReply    = "cherry-%s.tcp-reply.%s.%s" // reply subject of RequestSync: prefix, nodeType, nodeID
+)
+
+type (
+	// TCPComponent is a peer-to-peer cluster transport (mode "tcp").
+	// Each node listens on its RpcAddress and dials the RPC address of every
+	// discovery member over one persistent connection per peer. Requests and
+	// replies are multiplexed on that connection by request id, so the hot
+	// path between two nodes has no broker hop.
+	//
+	// Links are opened and closed by the discovery member add/remove events
+	// and reconnect automatically. Requests written on a connection that
+	// closes fail with RPCNetError; one-way frames are sent again on the next
+	// connection.
+	//
+	// Raw subjects need a broker. When the profile has a "cluster.nats" config
+	// (also required by the nats discovery), RawPublish, RawRequest and
+	// RequestSync go through nats; otherwise they return ClusterSubjectNotSupported.
+	//
+	// Select it with AppBuilder.SetCluster(cherryCluster.NewTCP()).
+	// Optional profile config "cluster.tcp":
+	//   - chan_size: send queue size per connection, default 1024
+	//   - request_timeout: request timeout in seconds, default 1
+	//   - reconnect_delay: delay between dial attempts in seconds, default 1
+	TCPComponent struct {
+		cfacade.Component
+		listener       net.Listener
+		links          sync.Map       // key:nodeID, value:*tcpLink, outgoing connections
+		peers          sync.Map       // key:nodeID, value:*tcpConn, incoming connections
+		reqID          atomic.Uint64  // last request id
+		rawConnect     *cnats.Connect // nats connect of the raw subjects, nil without "cluster.nats"
+		chanSize       int            // send queue size per connection
+		requestTimeout time.Duration  // default request timeout
+		reconnectDelay time.Duration  // delay between dial attempts
+		die            chan struct{}  // closed on OnStop
+	}
+
+	// tcpConn is one framed connection with a dedicated writer goroutine.
+	tcpConn struct {
+		net.Conn
+		link      *tcpLink // owner of an outgoing connection, nil if incoming
+		sendChan  chan []byte
+		die       chan struct{}
+		closeOnce sync.Once
+	}
+
+	// tcpLink keeps an outgoing connection to one peer alive.
+	// Requests waiting on a closed link or connection fail with RPCNetError.
+	tcpLink struct {
+		owner     *TCPComponent
+		nodeID    string
+		address   string
+		sendChan  chan []byte // frames queued while the link reconnects
+		pending   sync.Map    // key:reqID, value:chan []byte, requests waiting for a reply
+		die       chan struct{}
+		closeOnce sync.Once
+	}
+)
+
+func NewTCP() *TCPComponent {
+	return &TCPComponent{}
+}
+
+func (*TCPComponent) Name() string {
+	return Name
+}
+
+func (*TCPComponent) Mode() string {
+	return "tcp"
+}
+
+func (p *TCPComponent) Init() {
+	config := p.App().Profile().GetConfig("cluster").GetConfig(p.Mode())
+
+	p.chanSize = config.GetInt("chan_size", 1024)
+	p.requestTimeout = config.GetDuration("request_timeout", 1) * time.Second
+	p.reconnectDelay = config.GetDuration("reconnect_delay", 1) * time.Second
+	p.die = make(chan struct{})
+
+	address := p.App().RpcAddress()
+	if address == "" {
+		panic("tcp cluster needs the `rpc_address` of this node.")
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		panic(err)
+	}
+	p.listener = listener
+
+	go p.accept()
+
+	p.initRawConnect()
+
+	p.App().Logger().Infof("Tcp cluster execute OnInit(). [rpcAddress = %s]", address)
+}
+
+// initRawConnect connects to nats for the raw subjects when "cluster.nats" is configured.
+func (p *TCPComponent) initRawConnect() {
+	natsConfig := p.App().Profile().GetConfig("cluster").GetConfig("nats")
+	if natsConfig.LastError() != nil {
+		return
+	}
+
+	rawConnect, err := cnats.NewConnectFromConfig(natsConfig, "cluster-raw")
+	if err != nil {
+		panic(err)
+	}
+	rawConnect.Connect()
+
+	prefix := natsConfig.GetString("prefix", "node")
+	replySubject := fmt.Sprintf(tcpRawReply, prefix, p.App().NodeType(), p.App().NodeID())
+	if err = rawConnect.SubscribeReply(replySubject); err != nil {
+		panic(err)
+	}
+
+	p.rawConnect = rawConnect
+}
+
+// OnAfterInit opens links to the known members and follows discovery changes.
+// Discovery is registered after the cluster component, so this cannot run in Init.
+func (p *TCPComponent) OnAfterInit() {
+	discovery := p.App().Discovery()
+
+	discovery.OnAddMember(func(member cfacade.IMember) {
+		p.openLink(member)
+	})
+
+	discovery.OnUpdateMember(func(member cfacade.IMember) {
+		if link, found := p.getLink(member.GetNodeID()); found && link.address != member.GetAddress() {
+			p.closeLink(member.GetNodeID())
+			p.openLink(member)
+		}
+	})
+
+	discovery.OnRemoveMember(func(member cfacade.IMember) {
+		p.closeLink(member.GetNodeID())
+	})
+
+	for _, member := range discovery.Map() {
+		p.openLink(member)
+	}
+}
+
+func (p *TCPComponent) OnStop() {
+	close(p.die)
+
+	if err := p.listener.Close(); err != nil {
+		p.App().Logger().Warnf("Tcp cluster close listener fail. err = %v", err)
+	}
+
+	p.links.Range(func(key, value any) bool {
+		p.closeLink(key.(string))
+		return true
+	})
+
+	p.peers.Range(func(key, value any) bool {
+		value.(*tcpConn).close()
+		return true
+	})
+
+	if p.rawConnect != nil {
+		p.rawConnect.Close()
+	}
+
+	p.App().Logger().Info("Tcp cluster execute OnStop().")
+}
+
+func (p *TCPComponent) accept() {
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			select {
+			case <-p.die:
+				return
+			default:
+			}
+
+			p.App().Logger().Warnf("[accept] Accept fail. err = %v", err)
+			continue
+		}
+
+		go p.serve(conn)
+	}
+}
+
+// serve reads the frames sent by a peer. The first frame is the hello frame
+// carrying the peer nodeID, used to route replies back on this connection.
+func (p *TCPComponent) serve(netConn net.Conn) {
+	conn := p.newConn(netConn)
+	defer conn.close()
+
+	reader := bufio.NewReader(conn)
+
+	typ, reqID, payload, err := readFrame(reader)
+	if err != nil || typ != tcpFrameHello {
+		p.App().Logger().Warnf("[serve] Read hello fail. [remote = %s, err = %v]", conn.RemoteAddr(), err)
+		return
+	}
+
+	nodeID := string(payload)
+	if old, loaded := p.peers.Swap(nodeID, conn); loaded {
+		old.(*tcpConn).close()
+	}
+	defer p.peers.CompareAndDelete(nodeID, conn)
+
+	for {
+		typ, reqID, payload, err = readFrame(reader)
+		if err != nil {
+			if err != io.EOF {
+				p.App().Logger().Debugf("[serve] Read frame fail. [nodeID = %s, err = %v]", nodeID, err)
+			}
+			return
+		}
+
+		p.dispatch(nodeID, typ, reqID, payload)
+	}
+}
+
+func (p *TCPComponent) dispatch(nodeID string, typ byte, reqID uint64, payload []byte) {
+	msg := cfacade.GetMessage()
+	if err := msg.Unmarshal(payload); err != nil {
+		p.App().Logger().Warnf("[dispatch] Unmarshal fail. [nodeID = %s, dataLen = %d, err = %v]", nodeID, len(payload), err)
+		msg.Recycle()
+		return
+	}
+
+	switch typ {
+	case tcpFrameLocal:
+		p.App().ActorSystem().PostLocal(msg)
+	case tcpFrameRemote:
+		p.App().ActorSystem().PostRemote(msg)
+	case tcpFrameRequest:
+		msg.ReqID = strconv.FormatUint(reqID, 10)
+		msg.Reply = tcpReplyPrefix + nodeID
+		p.App().ActorSystem().PostRemote(msg)
+	default:
+		p.App().Logger().Warnf("[dispatch] Unknown frame type. [nodeID = %s, type = %d]", nodeID, typ)
+		msg.Recycle()
+	}
+}
+
+func (p *TCPComponent) newConn(conn net.Conn) *tcpConn {
+	c := &tcpConn{
+		Conn:     conn,
+		sendChan: make(chan []byte, p.chanSize),
+		die:      make(chan struct{}),
+	}
+
+	go func() {
+		// the requester fails the requests of the lost replies when its link closes
+		if unsent, err := c.writeLoop(c.sendChan); len(unsent) > 0 {
+			p.App().Logger().Debugf("[tcpConn] Replies not sent. [remote = %s, count = %d, err = %v]", c.RemoteAddr(), len(unsent), err)
+		}
+	}()
+
+	return c
+}
+
+func (p *TCPComponent) getLink(nodeID string) (*tcpLink, bool) {
+	value, found := p.links.Load(nodeID)
+	if !found {
+		return nil, false
+	}
+	return value.(*tcpLink), true
+}
+
+func (p *TCPComponent) openLink(member cfacade.IMember) (*tcpLink, bool) {
+	if member.GetNodeID() == p.App().NodeID() || member.GetAddress() == "" {
+		return nil, false
+	}
+
+	link := &tcpLink{
+		owner:    p,
+		nodeID:   member.GetNodeID(),
+		address:  member.GetAddress(),
+		sendChan: make(chan []byte, p.chanSize),
+		die:      make(chan struct{}),
+	}
+
+	if value, loaded := p.links.LoadOrStore(link.nodeID, link); loaded {
+		return value.(*tcpLink), true
+	}
+
+	go link.run()
+
+	return link, true
+}
+
+func (p *TCPComponent) closeLink(nodeID string) {
+	if value, found := p.links.LoadAndDelete(nodeID); found {
+		value.(*tcpLink).close()
+	}
+}
+
+// link returns the link to the member, opening it if discovery knows the
+// member but the add event has not been handled yet.
+func (p *TCPComponent) link(tag, nodeID string) (*tcpLink, error) {
+	member, found := p.App().Discovery().GetMember(nodeID)
+	if !found {
+		p.App().Logger().Warnf("[%s] NodeID not found in discovery. [nodeID = %s]", tag, nodeID)
+		return nil, cerror.DiscoveryNotFoundNode
+	}
+
+	if link, found := p.getLink(nodeID); found {
+		return link, nil
+	}
+
+	link, ok := p.openLink(member)
+	if !ok {
+		p.App().Logger().Warnf("[%s] Member has no rpc address. [nodeID = %s]", tag, nodeID)
+		return nil, cerror.ClusterPublishFail
+	}
+
+	return link, nil
+}
+
+func (p *TCPComponent) PublishLocal(nodeID string, msg *cfacade.Message) error {
+	defer msg.Recycle()
+
+	return p.publish("PublishLocal", nodeID, tcpFrameLocal, msg)
+}
+
+func (p *TCPComponent) PublishRemote(nodeID string, msg *cfacade.Message) error {
+	defer msg.Recycle()
+
+	return p.publish("PublishRemote", nodeID, tcpFrameRemote, msg)
+}
+
+func (p *TCPComponent) publish(tag, nodeID string, typ byte, msg *cfacade.Message) error {
+	link, err := p.link(tag, nodeID)
+	if err != nil {
+		return err
+	}
+
+	bytes, err := msg.Marshal()
+	if err != nil {
+		p.App().Logger().Warnf("[%s] Marshal error. [nodeID = %s, err = %v]", tag, nodeID, err)
+		return cerror.ClusterPacketMarshalFail
+	}
+
+	if err = link.send(encodeFrame(typ, 0, bytes)); err != nil {
+		p.App().Logger().Warnf("[%s] Send fail. [nodeID = %s, err = %v]", tag, nodeID, err)
+		return err
+	}
+
+	return nil
+}
+
+func (p *TCPComponent) PublishRemoteType(nodeType string, msg *cfacade.Message) error {
+	defer msg.Recycle()
+
+	if nodeType == "" {
+		return cerror.ClusterNodeTypeIsNil
+	}
+
+	members := p.App().Discovery().ListByType(nodeType)
+	if len(members) < 1 {
+		return cerror.ClusterNodeTypeMemberNotFound
+	}
+
+	bytes, err := msg.Marshal()
+	if err != nil {
+		p.App().Logger().Warnf("[PublishRemoteType] Marshal error. [nodeType = %s, err = %v]", nodeType, err)
+		return cerror.ClusterPacketMarshalFail
+	}
+
+	// same as the nats remoteType subject, every node of the type receives it
+	frame := encodeFrame(tcpFrameRemote, 0, bytes)
+	for _, member := range members {
+		if member.GetNodeID() == p.App().NodeID() {
+			p.dispatch(member.GetNodeID(), tcpFrameRemote, 0, bytes)
+			continue
+		}
+
+		link, err := p.link("PublishRemoteType", member.GetNodeID())
+		if err != nil {
+			continue
+		}
+
+		if err = link.send(frame); err != nil {
+			p.App().Logger().Warnf("[PublishRemoteType] Send fail. [nodeID = %s, err = %v]", member.GetNodeID(), err)
+		}
+	}
+
+	return nil
+}
+
+// RequestRemote sends msg to nodeID and returns the data and the code of the response.
+func (p *TCPComponent) RequestRemote(nodeID string, msg *cfacade.Message, timeout ...time.Duration) ([]byte, int32) {
+	rsp, code := p.RequestResponse(nodeID, msg, timeout...)
+	return rsp.GetData(), code
+}
+
+// RequestResponse sends msg to nodeID and returns the response, nil if nodeID did not answer.
+func (p *TCPComponent) RequestResponse(nodeID string, msg *cfacade.Message, timeout ...time.Duration) (*cproto.Response, int32) {
+	defer msg.Recycle()
+
+	link, err := p.link("RequestRemote", nodeID)
+	if err != nil {
+		if err == cerror.DiscoveryNotFoundNode {
+			return nil, ccode.DiscoveryNotFoundNode
+		}
+		return nil, ccode.RPCNetError
+	}
+
+	reqBytes, err := msg.Marshal()
+	if err != nil {
+		p.App().Logger().Warnf("[RequestRemote] Marshal fail. [nodeID = %s, err = %v]", nodeID, err)
+		return nil, ccode.RPCMarshalError
+	}
+
+	reqID := p.reqID.Add(1)
+
+	ch := make(chan []byte, 1)
+	link.pending.Store(reqID, ch)
+	defer link.pending.Delete(reqID)
+
+	if err = link.send(encodeFrame(tcpFrameRequest, reqID, reqBytes)); err != nil {
+		p.App().Logger().Warnf("[RequestRemote] Send fail. [nodeID = %s, err = %v]", nodeID, err)
+		return nil, ccode.RPCNetError
+	}
+
+	d := p.requestTimeout
+	if len(timeout) > 0 && timeout[0] > 0 {
+		d = timeout[0]
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	var (
+		rspData []byte
+		ok      bool
+	)
+	select {
+	case rspData, ok = <-ch:
+		if !ok {
+			p.App().Logger().Warnf("[RequestRemote] connection closed. [nodeID = %s, reqID = %d]", nodeID, reqID)
+			return nil, ccode.RPCNetError
+		}
+	case <-link.die:
+		p.App().Logger().Warnf("[RequestRemote] link closed. [nodeID = %s, reqID = %d]", nodeID, reqID)
+		return nil, ccode.RPCNetError
+	case <-timer.C:
+		p.App().Logger().Warnf("[RequestRemote] timeout. [nodeID = %s, reqID = %d]", nodeID, reqID)
+		return nil, ccode.RPCRemoteExecuteError
+	}
+
+	rsp := &cproto.Response{}
+	if err = proto.Unmarshal(rspData, rsp); err != nil {
+		p.App().Logger().Warnf("[RequestRemote] unmarshal fail. [nodeID = %s, rsp = %v, err = %v]", nodeID, rsp, err)
+		return nil, ccode.RPCUnmarshalError
+	}
+
+	if ccode.IsFail(rsp.Code) && rsp.Message != "" {
+		p.App().Logger().Warnf("[RequestRemote] response error. [nodeID = %s, code = %d, message = %s]", nodeID, rsp.Code, rsp.Message)
+	}
+
+	return rsp, rsp.Code
+}
+
+// RequestReply sends the response of a request back on the connection the
+// request arrived on. replySubject identifies the requesting node.
+func (p *TCPComponent) RequestReply(reqID, replySubject string, data []byte) error {
+	nodeID, ok := strings.CutPrefix(replySubject, tcpReplyPrefix)
+	if !ok {
+		if p.rawConnect != nil {
+			return p.rawConnect.RequestReply(reqID, replySubject, data)
+		}
+		return cerror.Errorf("invalid tcp reply subject. [reply = %s]", replySubject)
+	}
+
+	id, err := strconv.ParseUint(reqID, 10, 64)
+	if err != nil {
+		return cerror.Errorf("invalid tcp reqID. [reqID = %s]", reqID)
+	}
+
+	value, found := p.peers.Load(nodeID)
+	if !found {
+		return cerror.Errorf("tcp connection of the requester is closed. [nodeID = %s]", nodeID)
+	}
+
+	return value.(*tcpConn).send(encodeFrame(tcpFrameReply, id, data))
+}
+
+// RequestSync sends a request to a raw subject via nats.
+func (p *TCPComponent) RequestSync(subject string, data []byte, timeout ...time.Duration) ([]byte, error) {
+	if p.rawConnect == nil {
+		return nil, cerror.ClusterSubjectNotSupported
+	}
+
+	reqID := cnats.NewStringReqID()
+	return p.rawConnect.RequestSync(reqID, subject, data, timeout...)
+}
+
+// RawPublish publishes data to a raw subject via nats.
+func (p *TCPComponent) RawPublish(subject string, data []byte) error {
+	if p.rawConnect == nil {
+		return cerror.ClusterSubjectNotSupported
+	}
+
+	return p.rawConnect.Publish(subject, data)
+}
+
+// RawRequest sends a request to a raw subject via nats.
+func (p *TCPComponent) RawRequest(subject string, data []byte, timeout ...time.Duration) ([]byte, error) {
+	if p.rawConnect == nil {
+		return nil, cerror.ClusterSubjectNotSupported
+	}
+
+	return p.rawConnect.Request(subject, data, timeout...)
+}
+
+// run dials the peer until the link is closed. Frames queued while the link
+// is down, and the frames a lost connection did not write, are sent once it
+// reconnects.
+func (l *tcpLink) run() {
+	hello := encodeFrame(tcpFrameHello, 0, []byte(l.owner.App().NodeID()))
+
+	var unsent [][]byte
+	for {
+		netConn, err := net.DialTimeout("tcp", l.address, l.owner.reconnectDelay)
+		if err != nil {
+			l.owner.App().Logger().Debugf("[tcpLink] Dial fail. [nodeID = %s, address = %s, err = %v]", l.nodeID, l.address, err)
+
+			select {
+			case <-l.die:
+				return
+			case <-time.After(l.owner.reconnectDelay):
+				continue
+			}
+		}
+
+		l.owner.App().Logger().Infof("[tcpLink] Connected. [nodeID = %s, address = %s]", l.nodeID, l.address)
+
+		conn := &tcpConn{
+			Conn: netConn,
+			link: l,
+			die:  make(chan struct{}),
+		}
+
+		go l.readLoop(conn)
+		go func() {
+			select {
+			case <-l.die:
+				conn.close()
+			case <-conn.die:
+			}
+		}()
+
+		unsent, err = conn.writeLoop(l.sendChan, append([][]byte{hello}, unsent...)...)
+
+		// the requests written on the connection will not get a reply
+		l.failPending()
+
+		select {
+		case <-l.die:
+			return
+		default:
+		}
+
+		l.owner.App().Logger().Infof("[tcpLink] Disconnected. [nodeID = %s, address = %s, unsent = %d, err = %v]",
+			l.nodeID, l.address, len(unsent), err)
+	}
+}
+
+// readLoop reads the reply frames of the requests sent on the link.
+func (l *tcpLink) readLoop(conn *tcpConn) {
+	defer conn.close()
+
+	reader := bufio.NewReader(conn)
+	for {
+		typ, reqID, payload, err := readFrame(reader)
+		if err != nil {
+			return
+		}
+
+		if typ == tcpFrameReply {
+			l.onReply(reqID, payload)
+		}
+	}
+}
+
+// onReply answers the in-flight request with the same id.
+// A reply that arrives after the request timed out is discarded.
+func (l *tcpLink) onReply(reqID uint64, data []byte) {
+	if value, found := l.pending.LoadAndDelete(reqID); found {
+		value.(chan []byte) <- data
+	}
+}
+
+// failPending fails the requests waiting for a reply.
+func (l *tcpLink) failPending() {
+	l.pending.Range(func(key, _ any) bool {
+		if value, found := l.pending.LoadAndDelete(key); found {
+			close(value.(chan []byte))
+		}
+		return true
+	})
+}
+
+// awaited reports whether the frame is still worth writing: a request that
+// already failed or timed out is skipped.
+func (l *tcpLink) awaited(frame []byte) bool {
+	if frame[4] != tcpFrameRequest {
+		return true
+	}
+
+	_, found := l.pending.Load(binary.BigEndian.Uint64(frame[5:]))
+	return found
+}
+
+func (l *tcpLink) send(frame []byte) error {
+	select {
+	case <-l.die:
+		return cerror.ClusterClientIsStop
+	default:
+	}
+
+	select {
+	case l.sendChan <- frame:
+		return nil
+	default:
+		return cerror.ClusterPublishFail
+	}
+}
+
+func (l *tcpLink) close() {
+	l.closeOnce.Do(func() {
+		close(l.die)
+	})
+}
+
+// writeLoop writes the queued frames, preceded by the given first frames,
+// until the connection is closed. It returns the frames taken from sendChan
+// but not flushed to the connection, so that a link can send them again.
+func (c *tcpConn) writeLoop(sendChan chan []byte, first ...[]byte) (unsent [][]byte, err error) {
+	defer c.close()
+
+	writer := bufio.NewWriter(c)
+	flush := func() error {
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		unsent = unsent[:0]
+		return nil
+	}
+
+	write := func(frame []byte) error {
+		if c.link != nil && !c.link.awaited(frame) {
+			return nil
+		}
+
+		if writer.Buffered() > 0 && writer.Available() < len(frame) {
+			if err := flush(); err != nil {
+				unsent = append(unsent, frame)
+				return err
+			}
+		}
+
+		unsent = append(unsent, frame)
+		if _, err := writer.Write(frame); err != nil {
+			return err
+		}
+
+		// a frame larger than the buffer is written through
+		if writer.Buffered() == 0 {
+			unsent = unsent[:0]
+		}
+		return nil
+	}
+
+	for _, frame := range first {
+		if err = write(frame); err != nil {
+			return unsent, err
+		}
+	}
+
+	for {
+		if writer.Buffered() > 0 && len(sendChan) < 1 {
+			if err = flush(); err != nil {
+				return unsent, err
+			}
+		}
+
+		select {
+		case <-c.die:
+			return unsent, cerror.ClusterClientIsStop
+		case frame := <-sendChan:
+			if err = write(frame); err != nil {
+				return unsent, err
+			}
+		}
+	}
+}
+
+func (c *tcpConn) send(frame []byte) error {
+	select {
+	case <-c.die:
+		return cerror.ClusterClientIsStop
+	case c.sendChan <- frame:
+		return nil
+	default:
+		return cerror.ClusterPublishFail
+	}
+}
+
+func (c *tcpConn) close() {
+	c.closeOnce.Do(func() {
+		close(c.die)
+		_ = c.Conn.Close()
+	})
+}
+
+func encodeFrame(typ byte, reqID uint64, payload []byte) []byte {
+	frame := make([]byte, tcpHeadLength+len(payload))
+	binary.BigEndian.PutUint32(frame, uint32(1+8+len(payload)))
+	frame[4] = typ
+	binary.BigEndian.PutUint64(frame[5:], reqID)
+	copy(frame[tcpHeadLength:], payload)
+	return frame
+}
+
+func readFrame(reader io.Reader) (typ byte, reqID uint64, payload []byte, err error) {
+	head := make([]byte, tcpHeadLength)
+	if _, err = io.ReadFull(reader, head); err != nil {
+		return
+	}
+
+	length := binary.BigEndian.Uint32(head)
+	if length < 1+8 || length > tcpMaxFrame {
+		err = cerror.PacketSizeExceed
+		return
+	}
+
+	typ = head[4]
+	reqID = binary.BigEndian.Uint64(head[5:])
+	payload = make([]byte, length-1-8)
+	_, err = io.ReadFull(reader, payload)
+	return
+}
@@ -0,0 +1,305 @@
+package cherryCluster
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cerror "github.com/cherry-game/cherry/error"
+	cfacade "github.com/cherry-game/cherry/facade"
+	clog "github.com/cherry-game/cherry/logger"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+// testApp implements the parts of cfacade.IApplication used by the
+// TCPComponent and the MemoryComponent.
+type testApp struct {
+	cfacade.IApplication
+	nodeID      string
+	discovery   cfacade.IDiscovery
+	actorSystem cfacade.IActorSystem
+}
+
+func (a *testApp) NodeID() string                    { return a.nodeID }
+func (a *testApp) Logger() cfacade.ILogger           { return clog.DefaultLogger }
+func (a *testApp) Discovery() cfacade.IDiscovery     { return a.discovery }
+func (a *testApp) ActorSystem() cfacade.IActorSystem { return a.actorSystem }
+
+// testDiscovery knows a fixed set of members.
+type testDiscovery struct {
+	cfacade.IDiscovery
+	members map[string]cfacade.IMember
+}
+
+func (d *testDiscovery) GetMember(nodeID string) (cfacade.IMember, bool) {
+	member, found := d.members[nodeID]
+	return member, found
+}
+
+func newTestTCP(nodeID string, members ...*cproto.Member) *TCPComponent {
+	discovery := &testDiscovery{members: map[string]cfacade.IMember{}}
+	for _, member := range members {
+		discovery.members[member.NodeID] = member
+	}
+
+	p := &TCPComponent{
+		chanSize:       16,
+		requestTimeout: 2 * time.Second,
+		reconnectDelay: 20 * time.Millisecond,
+		die:            make(chan struct{}),
+	}
+	p.Set(&testApp{nodeID: nodeID, discovery: discovery})
+	return p
+}
+
+func listen(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen fail. err = %v", err)
+	}
+	return listener
+}
+
+func accept(t *testing.T, listener net.Listener) net.Conn {
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatalf("accept fail. err = %v", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	return conn
+}
+
+// expectHello reads the hello frame a link sends first on every connection.
+func expectHello(t *testing.T, reader *bufio.Reader, nodeID string) {
+	typ, _, payload, err := readFrame(reader)
+	if err != nil {
+		t.Fatalf("read hello fail. err = %v", err)
+	}
+	if typ != tcpFrameHello || string(payload) != nodeID {
+		t.Fatalf("expected hello from %s, got type = %d payload = %q", nodeID, typ, payload)
+	}
+}
+
+func TestFrame_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(encodeFrame(tcpFrameRequest, 42, []byte("hello")))
+	buf.Write(encodeFrame(tcpFrameReply, 7, nil))
+
+	typ, reqID, payload, err := readFrame(&buf)
+	if err != nil || typ != tcpFrameRequest || reqID != 42 || string(payload) != "hello" {
+		t.Fatalf("first frame = (%d, %d, %q, %v)", typ, reqID, payload, err)
+	}
+
+	typ, reqID, payload, err = readFrame(&buf)
+	if err != nil || typ != tcpFrameReply || reqID != 7 || len(payload) != 0 {
+		t.Fatalf("second frame = (%d, %d, %q, %v)", typ, reqID, payload, err)
+	}
+}
+
+func TestFrame_InvalidLength(t *testing.T) {
+	for _, length := range []uint32{0, 8, tcpMaxFrame + 1} {
+		frame := encodeFrame(tcpFrameLocal, 1, nil)
+		frame[0], frame[1], frame[2], frame[3] = byte(length>>24), byte(length>>16), byte(length>>8), byte(length)
+
+		if _, _, _, err := readFrame(bytes.NewReader(frame)); err != cerror.PacketSizeExceed {
+			t.Fatalf("length %d: expected PacketSizeExceed, got %v", length, err)
+		}
+	}
+}
+
+func TestFrame_Truncated(t *testing.T) {
+	frame := encodeFrame(tcpFrameLocal, 1, []byte("payload"))
+
+	if _, _, _, err := readFrame(bytes.NewReader(frame[:len(frame)-1])); err == nil {
+		t.Fatal("expected error for truncated payload")
+	}
+}
+
+func TestTCP_ServeHello(t *testing.T) {
+	p := newTestTCP("node-1")
+
+	client, server := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		p.serve(server)
+		close(done)
+	}()
+
+	if _, err := client.Write(encodeFrame(tcpFrameHello, 0, []byte("node-2"))); err != nil {
+		t.Fatalf("write hello fail. err = %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, found := p.peers.Load("node-2"); found {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("peer was not registered after hello")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	_ = client.Close()
+	<-done
+
+	if _, found := p.peers.Load("node-2"); found {
+		t.Fatal("peer should be removed when the connection closes")
+	}
+}
+
+func TestTCP_ServeRejectsMissingHello(t *testing.T) {
+	p := newTestTCP("node-1")
+
+	client, server := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		p.serve(server)
+		close(done)
+	}()
+
+	go func() {
+		_, _ = client.Write(encodeFrame(tcpFrameLocal, 0, []byte("node-2")))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("serve should return when the first frame is not hello")
+	}
+	_ = client.Close()
+}
+
+func TestTCPLink_Reconnect(t *testing.T) {
+	listener := listen(t)
+	defer listener.Close()
+
+	p := newTestTCP("node-1")
+	link, ok := p.openLink(&cproto.Member{NodeID: "node-2", Address: listener.Addr().String()})
+	if !ok {
+		t.Fatal("openLink fail")
+	}
+	defer p.closeLink("node-2")
+
+	conn := accept(t, listener)
+	expectHello(t, bufio.NewReader(conn), "node-1")
+	_ = conn.Close()
+
+	// the link dials again and says hello on the new connection
+	conn = accept(t, listener)
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	expectHello(t, reader, "node-1")
+
+	if err := link.send(encodeFrame(tcpFrameLocal, 0, []byte("after"))); err != nil {
+		t.Fatalf("send fail. err = %v", err)
+	}
+
+	typ, _, payload, err := readFrame(reader)
+	if err != nil || typ != tcpFrameLocal || string(payload) != "after" {
+		t.Fatalf("frame after reconnect = (%d, %q, %v)", typ, payload, err)
+	}
+}
+
+func TestTCP_RequestFailsOnCloseLink(t *testing.T) {
+	listener := listen(t)
+	defer listener.Close()
+
+	member := &cproto.Member{NodeID: "node-2", Address: listener.Addr().String()}
+	p := newTestTCP("node-1", member)
+
+	// the peer reads the request and never replies
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			if _, _, _, err = readFrame(reader); err != nil {
+				return
+			}
+		}
+	}()
+
+	time.AfterFunc(100*time.Millisecond, func() {
+		p.closeLink("node-2")
+	})
+
+	msg := cfacade.GetMessage()
+	msg.Source = "node-1.source"
+	msg.Target = "node-2.target"
+	msg.FuncName = "call"
+
+	start := time.Now()
+	rsp, code := p.RequestResponse("node-2", msg)
+	if code != ccode.RPCNetError || rsp != nil {
+		t.Fatalf("expected RPCNetError, got code = %d, rsp = %v", code, rsp)
+	}
+	if elapsed := time.Since(start); elapsed >= p.requestTimeout {
+		t.Fatalf("request should fail when the link closes, waited %v", elapsed)
+	}
+}
+
+func TestTCP_RequestFailsOnCloseConn(t *testing.T) {
+	listener := listen(t)
+	defer listener.Close()
+
+	member := &cproto.Member{NodeID: "node-2", Address: listener.Addr().String()}
+	p := newTestTCP("node-1", member)
+	defer p.closeLink("node-2")
+
+	// the peer reads the request and drops the connection without a reply
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			typ, _, _, err := readFrame(reader)
+			if err != nil || typ == tcpFrameRequest {
+				return
+			}
+		}
+	}()
+
+	msg := cfacade.GetMessage()
+	msg.Source = "node-1.source"
+	msg.Target = "node-2.target"
+	msg.FuncName = "call"
+
+	start := time.Now()
+	rsp, code := p.RequestResponse("node-2", msg)
+	if code != ccode.RPCNetError || rsp != nil {
+		t.Fatalf("expected RPCNetError, got code = %d, rsp = %v", code, rsp)
+	}
+	if elapsed := time.Since(start); elapsed >= p.requestTimeout {
+		t.Fatalf("request should fail when the connection closes, waited %v", elapsed)
+	}
+}
+
+func TestTCPConn_WriteLoopReturnsUnsent(t *testing.T) {
+	client, server := net.Pipe()
+	_ = server.Close()
+
+	conn := &tcpConn{Conn: client, die: make(chan struct{})}
+
+	sendChan := make(chan []byte, 1)
+	sendChan <- encodeFrame(tcpFrameLocal, 0, []byte("queued"))
+
+	unsent, err := conn.writeLoop(sendChan, encodeFrame(tcpFrameHello, 0, []byte("node-1")))
+	if err == nil {
+		t.Fatal("expected a write error")
+	}
+
+	if len(unsent) != 2 || unsent[0][4] != tcpFrameHello || unsent[1][4] != tcpFrameLocal {
+		t.Fatalf("expected the hello and the queued frame, got %d frames", len(unsent))
+	}
+}
@@ -47,9 +47,19 @@ func (p *Connector) Start() {
 	}()
 }
 
+// Stop closes the listener. Calling it more than once is a no-op, so the
+// drain phase and OnStop can both call it.
 func (p *Connector) Stop() {
+	if !p.running {
+		return
+	}
+
 	p.running = false
 
+	if p.listener == nil {
+		return
+	}
+
 	if err := p.listener.Close(); err != nil {
 		clog.Errorf("Failed to stop: %s", err)
 	}
@@ -1,6 +1,8 @@
 package cherryConnector
 
 import (
+	"context"
+
 	cfacade "github.com/cherry-game/cherry/facade"
 	clog "github.com/cherry-game/cherry/logger"
 )
@@ -20,6 +22,11 @@ func (*TCPConnector) Name() string {
 func (t *TCPConnector) OnAfterInit() {
 }
 
+// OnDrain stops accepting new connections at the start of the shutdown drain phase.
+func (t *TCPConnector) OnDrain(_ context.Context) {
+	t.Stop()
+}
+
 func (t *TCPConnector) OnStop() {
 	t.Stop()
 }
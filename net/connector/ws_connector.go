@@ -1,6 +1,7 @@
 package cherryConnector
 
 import (
+	"context"
 	"io"
 	"net"
 	"net/http"
@@ -37,6 +38,11 @@ func (*WSConnector) Name() string {
 func (w *WSConnector) OnAfterInit() {
 }
 
+// OnDrain stops accepting new connections at the start of the shutdown drain phase.
+func (w *WSConnector) OnDrain(_ context.Context) {
+	w.Stop()
+}
+
 func (w *WSConnector) OnStop() {
 	w.Stop()
 }
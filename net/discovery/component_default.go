@@ -1,6 +1,7 @@
 package cherryDiscovery
 
 import (
+	"maps"
 	"math/rand"
 	"sync"
 
@@ -9,7 +10,6 @@ import (
 	cfacade "github.com/cherry-game/cherry/facade"
 	clog "github.com/cherry-game/cherry/logger"
 	cproto "github.com/cherry-game/cherry/net/proto"
-	cprofile "github.com/cherry-game/cherry/profile"
 )
 
 // Config keys used to parse node information from the profile file.
@@ -53,13 +53,22 @@ func (n *ComponentDefault) Mode() string {
 	return "default"
 }
 
+// logger returns the application logger, or the package default logger when
+// the discovery is used standalone.
+func (n *ComponentDefault) logger() cfacade.ILogger {
+	if n.App() == nil {
+		return clog.DefaultLogger
+	}
+	return n.App().Logger()
+}
+
 // loadConfig parses the "node" section of the profile file and populates memberMap.
 // Each node entry must have: node_id, rpc_address, and optional __settings__.
 // Duplicate nodeIDs or empty nodeIDs within a node type will skip that entry.
 func (n *ComponentDefault) loadConfig() {
-	nodeConfig := cprofile.GetConfig(ConfigKeyNode)
+	nodeConfig := n.App().Profile().GetConfig(ConfigKeyNode)
 	if nodeConfig.LastError() != nil {
-		clog.Errorf("`%s` property not found in profile file.", ConfigKeyNode)
+		n.logger().Errorf("`%s` property not found in profile file.", ConfigKeyNode)
 		return
 	}
 
@@ -70,12 +79,12 @@ func (n *ComponentDefault) loadConfig() {
 
 			nodeID := item.Get(ConfigKeyNodeID).ToString()
 			if nodeID == "" {
-				clog.Errorf("nodeID is empty in nodeType = %s", nodeType)
+				n.logger().Errorf("nodeID is empty in nodeType = %s", nodeType)
 				break
 			}
 
 			if _, found := n.GetMember(nodeID); found {
-				clog.Errorf("nodeType = %s, nodeID = %s, duplicate nodeID", nodeType, nodeID)
+				n.logger().Errorf("nodeType = %s, nodeID = %s, duplicate nodeID", nodeType, nodeID)
 				break
 			}
 
@@ -110,13 +119,15 @@ func (n *ComponentDefault) Map() map[string]cfacade.IMember {
 	return memberMap
 }
 
-// ListByType returns members of the given nodeType, excluding any nodes listed in filterNodeID.
+// ListByType returns the serving members of the given nodeType, excluding any
+// nodes listed in filterNodeID. Joining, draining and leaving members are
+// skipped, Map and GetMember still return them.
 func (n *ComponentDefault) ListByType(nodeType string, filterNodeID ...string) []cfacade.IMember {
 	var memberList []cfacade.IMember
 
 	n.memberMap.Range(func(key, value any) bool {
 		member := value.(cfacade.IMember)
-		if member.GetNodeType() == nodeType {
+		if member.GetNodeType() == nodeType && member.GetStatus() == cfacade.MemberServing {
 			if _, ok := cslice.StringIn(member.GetNodeID(), filterNodeID); !ok {
 				memberList = append(memberList, member)
 			}
@@ -128,8 +139,8 @@ func (n *ComponentDefault) ListByType(nodeType string, filterNodeID ...string) [
 	return memberList
 }
 
-// Random returns a random member of the given nodeType.
-// Returns nil, false if no members of that type exist.
+// Random returns a random serving member of the given nodeType.
+// Returns nil, false if no serving members of that type exist.
 func (n *ComponentDefault) Random(nodeType string) (cfacade.IMember, bool) {
 	memberList := n.ListByType(nodeType)
 	memberLen := len(memberList)
@@ -184,18 +195,14 @@ func (n *ComponentDefault) UpdateSetting(key, value string) {
 		return
 	}
 
-	raw, found := n.memberMap.Load(nodeID)
-	if !found {
-		return
-	}
-
-	member, ok := raw.(*cproto.Member)
-	if !ok {
+	member, updated := n.updateMember(nodeID, func(member *cproto.Member) bool {
+		member.UpdateSetting(key, value)
+		return true
+	})
+	if !updated {
 		return
 	}
 
-	member.UpdateSetting(key, value)
-
 	for _, listener := range n.onUpdateListener {
 		listener(member)
 	}
@@ -217,17 +224,43 @@ func (n *ComponentDefault) UpdateSettings(settings map[string]string) {
 		return
 	}
 
-	raw, found := n.memberMap.Load(nodeID)
-	if !found {
+	member, updated := n.updateMember(nodeID, func(member *cproto.Member) bool {
+		member.UpdateSettings(settings)
+		return true
+	})
+	if !updated {
+		return
+	}
+
+	for _, listener := range n.onUpdateListener {
+		listener(member)
+	}
+}
+
+// SetStatus sets the status of the local node's member entry (e.g.
+// cfacade.MemberDraining before a rolling deploy) and notifies OnUpdateMember
+// listeners when it changed. If the local node is not in the member map, the
+// call is silently ignored.
+//
+// Backends with a transport layer (nats, etcd) override this method to also
+// broadcast the change to other nodes.
+func (n *ComponentDefault) SetStatus(status int32) {
+	if n.App() == nil {
 		return
 	}
 
-	member, ok := raw.(*cproto.Member)
-	if !ok {
+	member, updated := n.updateMember(n.App().NodeID(), func(member *cproto.Member) bool {
+		if member.Status == status {
+			return false
+		}
+		member.Status = status
+		return true
+	})
+	if !updated {
 		return
 	}
 
-	member.UpdateSettings(settings)
+	n.logger().Infof("Member status changed. [nodeID = %s, status = %d]", member.NodeID, status)
 
 	for _, listener := range n.onUpdateListener {
 		listener(member)
@@ -267,9 +300,9 @@ func (n *ComponentDefault) OnRemoveMember(listener cfacade.MemberListener) {
 func (n *ComponentDefault) AddMember(member cfacade.IMember) {
 	_, isDuplicate := n.memberMap.LoadOrStore(member.GetNodeID(), member)
 	if isDuplicate {
-		clog.Debugf("Add Duplicate Member. [member = %s]", member)
+		n.logger().Debugf("Add Duplicate Member. [member = %s]", member)
 	} else {
-		clog.Debugf("Add Member. [ member = %s]", member)
+		n.logger().Debugf("Add Member. [ member = %s]", member)
 	}
 
 	for _, listener := range n.onAddListener {
@@ -279,12 +312,23 @@ func (n *ComponentDefault) AddMember(member cfacade.IMember) {
 
 // UpdateMember updates an existing member in the local member table.
 // If the member doesn't exist, it is stored but no update listeners are notified.
-// If the member exists, the stored value is replaced and onUpdateListener callbacks fire.
+// If the member exists, a copy of the stored value with the status and settings
+// of member replaces it and onUpdateListener callbacks fire with the copy.
 func (n *ComponentDefault) UpdateMember(member *cproto.Member) {
 	value, loaded := n.memberMap.LoadOrStore(member.NodeID, member)
 	if loaded {
+		if stored, ok := value.(*cproto.Member); ok && stored != member {
+			if updated, found := n.updateMember(member.NodeID, func(stored *cproto.Member) bool {
+				stored.Status = member.Status
+				stored.Settings = maps.Clone(member.Settings)
+				return true
+			}); found {
+				value = updated
+			}
+		}
+
 		member := value.(cfacade.IMember)
-		clog.Debugf("Update member. [member = %s]", member)
+		n.logger().Debugf("Update member. [member = %s]", member)
 
 		for _, listener := range n.onUpdateListener {
 			listener(member)
@@ -292,13 +336,40 @@ func (n *ComponentDefault) UpdateMember(member *cproto.Member) {
 	}
 }
 
+// updateMember replaces the member nodeID with a copy changed by update, so
+// the values already returned to readers never change. It returns the copy,
+// or false when the member is unknown or update reports no change.
+func (n *ComponentDefault) updateMember(nodeID string, update func(member *cproto.Member) bool) (*cproto.Member, bool) {
+	for {
+		raw, found := n.memberMap.Load(nodeID)
+		if !found {
+			return nil, false
+		}
+
+		stored, ok := raw.(*cproto.Member)
+		if !ok {
+			return nil, false
+		}
+
+		member := stored.Clone()
+		if !update(member) {
+			return nil, false
+		}
+
+		// retry when another update replaced the member meanwhile
+		if n.memberMap.CompareAndSwap(nodeID, stored, member) {
+			return member, true
+		}
+	}
+}
+
 // RemoveMember deletes a member from the local member table by nodeID.
 // If the member existed, notifies all onRemoveListener callbacks.
 func (n *ComponentDefault) RemoveMember(nodeID string) {
 	value, loaded := n.memberMap.LoadAndDelete(nodeID)
 	if loaded {
 		member := value.(cfacade.IMember)
-		clog.Debugf("Remove member. [member = %s]", member)
+		n.logger().Debugf("Remove member. [member = %s]", member)
 
 		for _, listener := range n.onRemoveListener {
 			listener(member)
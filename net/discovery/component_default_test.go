@@ -1,6 +1,7 @@
 package cherryDiscovery
 
 import (
+	"strconv"
 	"sync"
 	"testing"
 
@@ -122,6 +123,33 @@ func TestComponentDefault_UpdateMember_Existing(t *testing.T) {
 	}
 }
 
+// TestComponentDefault_UpdateMember_Status verifies that an update replaces the
+// stored value by a copy with the status and settings of the remote member,
+// leaving the value already read unchanged.
+func TestComponentDefault_UpdateMember_Status(t *testing.T) {
+	d := &ComponentDefault{}
+	stored := newTestMember("node-1", "game", "127.0.0.1:10001")
+	d.AddMember(stored)
+
+	updated := newTestMember("node-1", "game", "127.0.0.1:10001")
+	updated.Status = cfacade.MemberDraining
+	updated.Settings["load"] = "0.5"
+	d.UpdateMember(updated)
+
+	member, _ := d.GetMember("node-1")
+	if member.GetStatus() != cfacade.MemberDraining || member.GetSettings()["load"] != "0.5" {
+		t.Fatalf("update not applied. member = %v", member)
+	}
+
+	if stored.GetStatus() == cfacade.MemberDraining || stored.GetSettings()["load"] != "" {
+		t.Fatalf("stored value changed. member = %v", stored)
+	}
+
+	if len(d.ListByType("game")) != 0 {
+		t.Fatal("draining member should be skipped")
+	}
+}
+
 // TestComponentDefault_UpdateMember_New verifies that updating a member that doesn't
 // exist yet stores it but does not trigger update listeners.
 func TestComponentDefault_UpdateMember_New(t *testing.T) {
@@ -260,6 +288,32 @@ func TestComponentDefault_ListByType_ExcludeFilter(t *testing.T) {
 	}
 }
 
+// TestComponentDefault_ListByType_SkipNotServing verifies ListByType and Random
+// skip joining, draining and leaving members while Map still returns them.
+func TestComponentDefault_ListByType_SkipNotServing(t *testing.T) {
+	d := &ComponentDefault{}
+	d.AddMember(newTestMember("node-1", "game", "127.0.0.1:10001"))
+	for i, status := range []int32{cfacade.MemberJoining, cfacade.MemberDraining, cfacade.MemberLeaving} {
+		member := newTestMember("node-"+strconv.Itoa(i+2), "game", "127.0.0.1:10002")
+		member.Status = status
+		d.AddMember(member)
+	}
+
+	if list := d.ListByType("game"); len(list) != 1 || list[0].GetNodeID() != "node-1" {
+		t.Fatalf("expected node-1 only, got %v", list)
+	}
+
+	for range 20 {
+		if m, ok := d.Random("game"); !ok || m.GetNodeID() != "node-1" {
+			t.Fatalf("expected node-1, got %v", m)
+		}
+	}
+
+	if len(d.Map()) != 4 {
+		t.Fatalf("expected 4 members in map, got %d", len(d.Map()))
+	}
+}
+
 // TestComponentDefault_Random_Empty verifies Random returns nil, false when no members exist.
 func TestComponentDefault_Random_Empty(t *testing.T) {
 	d := &ComponentDefault{}
@@ -432,6 +486,31 @@ func TestComponentDefault_UpdateSettings(t *testing.T) {
 	}
 }
 
+// TestComponentDefault_SetStatus verifies that SetStatus updates the local member
+// and fires OnUpdateMember listeners only when the status changed.
+func TestComponentDefault_SetStatus(t *testing.T) {
+	d := &ComponentDefault{}
+	app := &mockApp{nodeID: "node-1"}
+	d.Set(app)
+
+	d.AddMember(newTestMember("node-1", "game", "127.0.0.1:10001"))
+
+	callCount := 0
+	d.OnUpdateMember(func(m cfacade.IMember) { callCount++ })
+
+	d.SetStatus(cfacade.MemberDraining)
+	d.SetStatus(cfacade.MemberDraining)
+
+	if callCount != 1 {
+		t.Fatalf("expected 1 listener call, got %d", callCount)
+	}
+
+	m, _ := d.GetMember("node-1")
+	if m.GetStatus() != cfacade.MemberDraining {
+		t.Fatalf("expected draining, got %d", m.GetStatus())
+	}
+}
+
 // TestComponentDefault_UpdateSetting_NoSelf verifies that UpdateSetting is a no-op
 // when the local node is not in the member map.
 func TestComponentDefault_UpdateSetting_NoSelf(t *testing.T) {
@@ -3,14 +3,13 @@ package cherryDiscovery
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	ctime "github.com/cherry-game/cherry/extend/time"
 	cfacade "github.com/cherry-game/cherry/facade"
-	clog "github.com/cherry-game/cherry/logger"
 	cnats "github.com/cherry-game/cherry/net/nats"
 	cproto "github.com/cherry-game/cherry/net/proto"
-	cprofile "github.com/cherry-game/cherry/profile"
 	"github.com/nats-io/nats.go"
 )
 
@@ -26,15 +25,18 @@ var registerRequired = []byte{0x01}
 //  3. The master replies with the full member list and broadcasts the new member via add subject.
 //  4. Workers periodically send heartbeat messages; the master removes members that time out.
 //  5. Workers broadcast remove messages on shutdown.
+//  6. Status changes (joining → serving → draining/leaving) are broadcast via update subject.
 //
 // natsSubjects holds the NATS subject strings built from prefix and masterID.
-// thisMember is the local node's member info, synced with the master on setting changes.
+// thisMember is the local node's member info as registered, the current value is
+// the copy stored in memberMap by the last setting or status change.
 // ctx/cancel control the lifecycle of background goroutines (ticker, heartbeat check).
 type (
 	ComponentMaster struct {
 		ComponentDefault
 		natsSubjects
-		thisMember       *cproto.Member     // local node's member info, updated on setting changes
+		thisMember       *cproto.Member     // local node's member info as registered, never changed
+		updateMu         sync.Mutex         // serializes the changes of the local member and their broadcast
 		masterID         string             // the designated master node ID from config
 		ctx              context.Context    // lifecycle context for background goroutines
 		cancel           context.CancelFunc // cancel func
@@ -69,17 +71,66 @@ func (m *ComponentMaster) Init() {
 
 // UpdateSetting updates a single setting on this member and broadcasts the change.
 func (m *ComponentMaster) UpdateSetting(key, value string) {
-	m.thisMember.UpdateSetting(key, value)
-	m.sendUpdateMember()
+	m.updateThisMember(func(member *cproto.Member) bool {
+		member.UpdateSetting(key, value)
+		return true
+	})
 }
 
 // UpdateSettings updates multiple settings on this member and broadcasts the change.
 func (m *ComponentMaster) UpdateSettings(setting map[string]string) {
-	for key, value := range setting {
-		m.thisMember.UpdateSetting(key, value)
+	m.updateThisMember(func(member *cproto.Member) bool {
+		member.UpdateSettings(setting)
+		return true
+	})
+}
+
+// SetStatus sets the status of this member, notifies the local OnUpdateMember
+// listeners and broadcasts the change.
+func (m *ComponentMaster) SetStatus(status int32) {
+	member, updated := m.updateThisMember(func(member *cproto.Member) bool {
+		if member.Status == status {
+			return false
+		}
+		member.Status = status
+		return true
+	})
+	if !updated {
+		return
 	}
 
-	m.sendUpdateMember()
+	m.App().Logger().Infof("[SetStatus] NodeID = %s, status = %d", member.NodeID, status)
+
+	for _, listener := range m.onUpdateListener {
+		listener(member)
+	}
+}
+
+// updateThisMember stores a copy of this member changed by update and
+// broadcasts it. Readers of the member map keep the value they loaded, and
+// the broadcasts go out in the order of the changes.
+func (m *ComponentMaster) updateThisMember(update func(member *cproto.Member) bool) (*cproto.Member, bool) {
+	m.updateMu.Lock()
+	defer m.updateMu.Unlock()
+
+	member, updated := m.updateMember(m.thisMember.NodeID, update)
+	if !updated {
+		return nil, false
+	}
+
+	m.sendUpdateMember(member)
+	return member, true
+}
+
+// member returns the current value of this member.
+func (m *ComponentMaster) member() *cproto.Member {
+	if value, found := m.memberMap.Load(m.thisMember.NodeID); found {
+		if member, ok := value.(*cproto.Member); ok {
+			return member
+		}
+	}
+
+	return m.thisMember
 }
 
 // Stop performs a graceful shutdown: workers send a remove message,
@@ -93,7 +144,7 @@ func (m *ComponentMaster) Stop() {
 		m.cancel()
 	}
 
-	clog.Debugf("[Stop] NodeID = %s is unregister", m.App().NodeID())
+	m.App().Logger().Debugf("[Stop] NodeID = %s is unregister", m.App().NodeID())
 }
 
 func (m *ComponentMaster) isMaster() bool {
@@ -127,22 +178,22 @@ func (m *ComponentMaster) init() {
 	m.masterInit()
 	m.clientInit()
 
-	clog.Infof("[init] Discovery = %s is running. [isMaster = %v, nodeID = %s]", m.Mode(), m.isMaster(), m.App().NodeID())
+	m.App().Logger().Infof("[init] Discovery = %s is running. [isMaster = %v, nodeID = %s]", m.Mode(), m.isMaster(), m.App().NodeID())
 }
 
 // loadThisMember reads NATS/member config from profile and constructs the local member.
 // Config path: cluster.<mode>  e.g. cluster.nats
 func (m *ComponentMaster) loadThisMember() {
-	config := cprofile.GetConfig("cluster").GetConfig(m.Mode())
+	config := m.App().Profile().GetConfig("cluster").GetConfig(m.Mode())
 	if config.LastError() != nil {
-		clog.Fatalf("[loadMember] Nats config not found. err = %v", config.LastError())
+		m.App().Logger().Fatalf("[loadMember] Nats config not found. err = %v", config.LastError())
 	}
 
 	m.prefix = config.GetString("prefix", "node")
 
 	m.masterID = config.GetString("master_node_id")
 	if m.masterID == "" {
-		clog.Fatal("[loadMember] Master node id not in config.")
+		m.App().Logger().Fatal("[loadMember] Master node id not in config.")
 	}
 
 	// The reply subject base must be unique per node (nodeID), otherwise two
@@ -208,7 +259,7 @@ func (m *ComponentMaster) addSubscribe() {
 	err := m.subscribeConnect.Subscribe(m.addSubject, func(msg *nats.Msg) {
 		addMember, err := m.bytes2Member(msg.Data)
 		if err != nil {
-			clog.Warnf("[addSubscribe] bytes to Member error. err = %s", err)
+			m.App().Logger().Warnf("[addSubscribe] bytes to Member error. err = %s", err)
 			return
 		}
 
@@ -219,7 +270,7 @@ func (m *ComponentMaster) addSubscribe() {
 		}
 	})
 	if err != nil {
-		clog.Warnf("[addSubscribe] fail. subject = %s, err = %s", m.addSubject, err)
+		m.App().Logger().Warnf("[addSubscribe] fail. subject = %s, err = %s", m.addSubject, err)
 	}
 }
 
@@ -233,11 +284,11 @@ func (m *ComponentMaster) clientTicker() {
 	for {
 		select {
 		case <-m.ctx.Done():
-			clog.Info("[clientTicker] Is exit.")
+			m.App().Logger().Info("[clientTicker] Is exit.")
 			return
 		case <-ticker.C:
 			if !m.App().Running() {
-				clog.Info("[clientTicker] Waiting for the application to change its running state.")
+				m.App().Logger().Info("[clientTicker] Waiting for the application to change its running state.")
 				continue
 			}
 
@@ -257,14 +308,14 @@ func (m *ComponentMaster) clientTicker() {
 func (m *ComponentMaster) sendHeartbeat2Master() bool {
 	nodeIDBytes, err := m.NodeID2Bytes(m.thisMember.NodeID)
 	if err != nil {
-		clog.Warnf("[sendHeartbeat2Master] NodeID to bytes error. err = %s", err)
+		m.App().Logger().Warnf("[sendHeartbeat2Master] NodeID to bytes error. err = %s", err)
 		return false
 	}
 
 	reqID := cnats.NewStringReqID()
 	rspData, err := m.publishConnect.RequestSync(reqID, m.heartbeatSubject, nodeIDBytes)
 	if err != nil {
-		clog.Warnf("[sendHeartbeat2Master] Fail. master = %s, err = %s", m.masterID, err)
+		m.App().Logger().Warnf("[sendHeartbeat2Master] Fail. master = %s, err = %s", m.masterID, err)
 		return false
 	}
 
@@ -274,24 +325,24 @@ func (m *ComponentMaster) sendHeartbeat2Master() bool {
 // sendRegister2Master sends a full registration to the master and processes
 // the member list reply, adding any members not yet known locally.
 func (m *ComponentMaster) sendRegister2Master() {
-	memberBytes, err := m.member2Bytes(m.thisMember)
+	memberBytes, err := m.member2Bytes(m.member())
 	if err != nil {
-		clog.Warnf("[sendRegister2Master] member marshal error. err = %s", err)
+		m.App().Logger().Warnf("[sendRegister2Master] member marshal error. err = %s", err)
 		return
 	}
 
 	reqID := cnats.NewStringReqID()
 	rspData, err := m.publishConnect.RequestSync(reqID, m.registerSubject, memberBytes)
 	if err != nil {
-		clog.Warnf("[sendRegister2Master] Fail. master = %s, err = %s", m.masterID, err)
+		m.App().Logger().Warnf("[sendRegister2Master] Fail. master = %s, err = %s", m.masterID, err)
 		return
 	}
 
-	clog.Infof("[sendRegister2Master] OK. master = %s", m.masterID)
+	m.App().Logger().Infof("[sendRegister2Master] OK. master = %s", m.masterID)
 
 	memberList, err := m.bytes2MemberList(rspData)
 	if err != nil {
-		clog.Warnf("[sendRegister2Master] Rsp data error. err = %s", err)
+		m.App().Logger().Warnf("[sendRegister2Master] Rsp data error. err = %s", err)
 		return
 	}
 
@@ -306,18 +357,18 @@ func (m *ComponentMaster) sendRegister2Master() {
 	}
 }
 
-// sendUpdateMember broadcasts updated member info (typically settings changes)
+// sendUpdateMember broadcasts updated member info (settings or status changes)
 // to all nodes via the update subject.
-func (m *ComponentMaster) sendUpdateMember() {
-	memberBytes, err := m.member2Bytes(m.thisMember)
+func (m *ComponentMaster) sendUpdateMember(member *cproto.Member) {
+	memberBytes, err := m.member2Bytes(member)
 	if err != nil {
-		clog.Warnf("[UpdateMember] member marshal error. err = %s", err)
+		m.App().Logger().Warnf("[UpdateMember] member marshal error. err = %s", err)
 		return
 	}
 
 	err = m.publishConnect.Publish(m.updateSubject, memberBytes)
 	if err != nil {
-		clog.Warnf("[UpdateMember] Fail. master = %s, err = %s", m.masterID, err)
+		m.App().Logger().Warnf("[UpdateMember] Fail. master = %s, err = %s", m.masterID, err)
 		return
 	}
 }
@@ -326,13 +377,13 @@ func (m *ComponentMaster) sendUpdateMember() {
 func (m *ComponentMaster) sendRemove(nodeID string) {
 	nodeBytes, err := m.NodeID2Bytes(nodeID)
 	if err != nil {
-		clog.Warnf("[sendRemove] NodeID2Bytes error. err = %s", err)
+		m.App().Logger().Warnf("[sendRemove] NodeID2Bytes error. err = %s", err)
 		return
 	}
 
 	err = m.publishConnect.Publish(m.removeSubject, nodeBytes)
 	if err != nil {
-		clog.Warnf("[sendRemove] Publish fail. err = %s", err)
+		m.App().Logger().Warnf("[sendRemove] Publish fail. err = %s", err)
 	}
 }
 
@@ -346,7 +397,7 @@ func (m *ComponentMaster) heartbeatCheck() {
 	for {
 		select {
 		case <-m.ctx.Done():
-			clog.Info("[heartbeatCheck] check is exit.")
+			m.App().Logger().Info("[heartbeatCheck] check is exit.")
 			return
 		case <-ticker.C:
 			m.checkMemberTimeout()
@@ -362,7 +413,7 @@ func (m *ComponentMaster) checkMemberTimeout() {
 	m.memberMap.Range(func(key, value any) bool {
 		protoMember, ok := value.(*cproto.Member)
 		if !ok {
-			clog.Warnf("[checkMemberTimeout] Member type error. Member = %v", value)
+			m.App().Logger().Warnf("[checkMemberTimeout] Member type error. Member = %v", value)
 			return true
 		}
 
@@ -386,7 +437,7 @@ func (m *ComponentMaster) registerSubscribe() {
 	err := m.subscribeConnect.Subscribe(m.registerSubject, func(msg *nats.Msg) {
 		newMember, err := m.bytes2Member(msg.Data)
 		if err != nil {
-			clog.Warnf("[registerSubscribe] bytes to Member error. err = %s", err)
+			m.App().Logger().Warnf("[registerSubscribe] bytes to Member error. err = %s", err)
 			return
 		}
 
@@ -398,7 +449,7 @@ func (m *ComponentMaster) registerSubscribe() {
 		m.replyMemberList(msg)
 	})
 	if err != nil {
-		clog.Warnf("[registerSubscribe] fail. subject = %s, err = %s", m.registerSubject, err)
+		m.App().Logger().Warnf("[registerSubscribe] fail. subject = %s, err = %s", m.registerSubject, err)
 	}
 }
 
@@ -409,7 +460,7 @@ func (m *ComponentMaster) heartbeatSubscribe() {
 	err := m.subscribeConnect.Subscribe(m.heartbeatSubject, func(msg *nats.Msg) {
 		nodeID, err := m.bytes2NodeID(msg.Data)
 		if err != nil {
-			clog.Warnf("[heartbeatSubscribe] bytes to NodeID error. err = %v", err)
+			m.App().Logger().Warnf("[heartbeatSubscribe] bytes to NodeID error. err = %v", err)
 			return
 		}
 
@@ -427,7 +478,7 @@ func (m *ComponentMaster) heartbeatSubscribe() {
 		}
 	})
 	if err != nil {
-		clog.Warnf("[heartbeatSubscribe] fail. subject = %s, err = %s", m.heartbeatSubject, err)
+		m.App().Logger().Warnf("[heartbeatSubscribe] fail. subject = %s, err = %s", m.heartbeatSubject, err)
 	}
 }
 
@@ -436,14 +487,14 @@ func (m *ComponentMaster) heartbeatSubscribe() {
 func (m *ComponentMaster) replyMemberList(msg *nats.Msg) {
 	memberListBytes, err := m.memberList2Bytes()
 	if err != nil {
-		clog.Warnf("[replyMemberList] Marshal fail. err = %s", err)
+		m.App().Logger().Warnf("[replyMemberList] Marshal fail. err = %s", err)
 		return
 	}
 
 	reqID := msg.Header.Get(cnats.REQ_ID)
 	err = m.publishConnect.RequestReply(reqID, msg.Reply, memberListBytes)
 	if err != nil {
-		clog.Warnf("[replyMemberList] Reply fail. err = %s", err)
+		m.App().Logger().Warnf("[replyMemberList] Reply fail. err = %s", err)
 	}
 }
 
@@ -451,13 +502,13 @@ func (m *ComponentMaster) replyMemberList(msg *nats.Msg) {
 func (m *ComponentMaster) sendAdd(member *cproto.Member) {
 	memberBytes, err := m.member2Bytes(member)
 	if err != nil {
-		clog.Warnf("[sendAdd] Marshal fail. err = %s", err)
+		m.App().Logger().Warnf("[sendAdd] Marshal fail. err = %s", err)
 		return
 	}
 
 	err = m.publishConnect.Publish(m.addSubject, memberBytes)
 	if err != nil {
-		clog.Warnf("[sendAdd] Publish fail. err = %s", err)
+		m.App().Logger().Warnf("[sendAdd] Publish fail. err = %s", err)
 	}
 }
 
@@ -467,7 +518,7 @@ func (m *ComponentMaster) removeSubscribe() {
 	err := m.subscribeConnect.Subscribe(m.removeSubject, func(msg *nats.Msg) {
 		nodeID, err := m.bytes2NodeID(msg.Data)
 		if err != nil {
-			clog.Warnf("[removeSubscribe] bytes to NodeID error. err = %s", err)
+			m.App().Logger().Warnf("[removeSubscribe] bytes to NodeID error. err = %s", err)
 			return
 		}
 
@@ -479,7 +530,7 @@ func (m *ComponentMaster) removeSubscribe() {
 		m.ComponentDefault.RemoveMember(nodeID)
 	})
 	if err != nil {
-		clog.Warnf("[removeSubscribe] fail. subject = %s, err = %s", m.removeSubject, err)
+		m.App().Logger().Warnf("[removeSubscribe] fail. subject = %s, err = %s", m.removeSubject, err)
 	}
 }
 
@@ -489,7 +540,7 @@ func (m *ComponentMaster) updateSubscribe() {
 	err := m.subscribeConnect.Subscribe(m.updateSubject, func(msg *nats.Msg) {
 		member, err := m.bytes2Member(msg.Data)
 		if err != nil {
-			clog.Warnf("[updateSubscribe] bytes to member error. err = %s", err)
+			m.App().Logger().Warnf("[updateSubscribe] bytes to member error. err = %s", err)
 			return
 		}
 
@@ -500,7 +551,7 @@ func (m *ComponentMaster) updateSubscribe() {
 		m.ComponentDefault.UpdateMember(member)
 	})
 	if err != nil {
-		clog.Warnf("[updateSubscribe] fail. subject = %s, err = %s", m.updateSubject, err)
+		m.App().Logger().Warnf("[updateSubscribe] fail. subject = %s, err = %s", m.updateSubject, err)
 	}
 }
 
@@ -566,6 +617,7 @@ func (m *ComponentMaster) OnStop() {
 
 // NewMemberWithApp creates a *cproto.Member populated from the application's node identity.
 // HeartbeatTimeout is read from the "cluster_heartbeat_timeout" setting (default 3s).
+// The member is joining until the application is running.
 func NewMemberWithApp(app cfacade.IApplication) *cproto.Member {
 	clusterHeartbeatTimeout := app.Settings().GetInt64("cluster_heartbeat_timeout", 3) * ctime.MillisecondsPerSecond
 
@@ -576,5 +628,6 @@ func NewMemberWithApp(app cfacade.IApplication) *cproto.Member {
 		LastAt:           ctime.Now().ToMillisecond(),
 		HeartbeatTimeout: clusterHeartbeatTimeout,
 		Settings:         make(map[string]string),
+		Status:           cfacade.MemberJoining,
 	}
 }
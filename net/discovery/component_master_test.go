@@ -1,9 +1,12 @@
 package cherryDiscovery
 
 import (
+	"strconv"
+	"sync"
 	"testing"
 
 	ctime "github.com/cherry-game/cherry/extend/time"
+	cfacade "github.com/cherry-game/cherry/facade"
 	cnats "github.com/cherry-game/cherry/net/nats"
 	cproto "github.com/cherry-game/cherry/net/proto"
 )
@@ -271,19 +274,29 @@ func TestComponentMaster_CheckMemberTimeout_TypeError(t *testing.T) {
 	m.checkMemberTimeout()
 }
 
-// TestComponentMaster_UpdateSetting verifies that UpdateSetting updates the
-// local thisMember Settings map.
-func TestComponentMaster_UpdateSetting(t *testing.T) {
-	m := newTestMaster("node-1", "master-1")
+// newTestThisMember registers the local member of m in its member map.
+func newTestThisMember(m *ComponentMaster, status int32) {
 	m.thisMember = &cproto.Member{
 		NodeID:   "node-1",
 		Settings: make(map[string]string),
+		Status:   status,
 	}
+	m.memberMap.Store(m.thisMember.NodeID, m.thisMember)
+}
+
+// TestComponentMaster_UpdateSetting verifies that UpdateSetting stores a copy
+// of the local member with the new setting and leaves the old value unchanged.
+func TestComponentMaster_UpdateSetting(t *testing.T) {
+	m := newTestMaster("node-1", "master-1")
+	newTestThisMember(m, cfacade.MemberServing)
 
 	m.UpdateSetting("region", "us-west")
 
-	if m.thisMember.Settings["region"] != "us-west" {
-		t.Fatalf("expected 'us-west', got '%s'", m.thisMember.Settings["region"])
+	if region := m.member().Settings["region"]; region != "us-west" {
+		t.Fatalf("expected 'us-west', got '%s'", region)
+	}
+	if _, found := m.thisMember.Settings["region"]; found {
+		t.Fatal("the registered member should not be changed in place")
 	}
 }
 
@@ -291,20 +304,67 @@ func TestComponentMaster_UpdateSetting(t *testing.T) {
 // multiple settings at once.
 func TestComponentMaster_UpdateSettings(t *testing.T) {
 	m := newTestMaster("node-1", "master-1")
-	m.thisMember = &cproto.Member{
-		NodeID:   "node-1",
-		Settings: make(map[string]string),
-	}
+	newTestThisMember(m, cfacade.MemberServing)
 
 	m.UpdateSettings(map[string]string{
 		"region": "us-west",
 		"zone":   "a",
 	})
 
-	if m.thisMember.Settings["region"] != "us-west" {
-		t.Fatalf("region mismatch: expected 'us-west', got '%s'", m.thisMember.Settings["region"])
+	member, _ := m.GetMember("node-1")
+	if member.GetSettings()["region"] != "us-west" {
+		t.Fatalf("region mismatch: expected 'us-west', got '%s'", member.GetSettings()["region"])
 	}
-	if m.thisMember.Settings["zone"] != "a" {
-		t.Fatalf("zone mismatch: expected 'a', got '%s'", m.thisMember.Settings["zone"])
+	if member.GetSettings()["zone"] != "a" {
+		t.Fatalf("zone mismatch: expected 'a', got '%s'", member.GetSettings()["zone"])
 	}
 }
+
+// TestComponentMaster_SetStatus verifies that SetStatus stores a copy of the
+// local member with the new status and notifies the local OnUpdateMember
+// listeners with it.
+func TestComponentMaster_SetStatus(t *testing.T) {
+	m := newTestMaster("node-1", "master-1")
+	newTestThisMember(m, cfacade.MemberJoining)
+
+	var received cfacade.IMember
+	m.OnUpdateMember(func(member cfacade.IMember) { received = member })
+
+	m.SetStatus(cfacade.MemberServing)
+
+	member, _ := m.GetMember("node-1")
+	if member.GetStatus() != cfacade.MemberServing {
+		t.Fatalf("expected serving, got %d", member.GetStatus())
+	}
+	if received != member {
+		t.Fatal("OnUpdateMember listener was not called with the stored member")
+	}
+	if m.thisMember.Status != cfacade.MemberJoining {
+		t.Fatal("the registered member should not be changed in place")
+	}
+}
+
+// TestComponentMaster_SetStatus_Concurrent verifies that status changes and
+// readers of the member map do not race.
+func TestComponentMaster_SetStatus_Concurrent(t *testing.T) {
+	m := newTestMaster("node-1", "master-1")
+	newTestThisMember(m, cfacade.MemberServing)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			m.SetStatus(cfacade.MemberDraining + int32(i%2))
+			m.UpdateSetting("load", strconv.Itoa(i))
+		}(i)
+		go func() {
+			defer wg.Done()
+			for _, member := range m.Map() {
+				_ = member.GetStatus()
+				_ = member.GetSettings()["load"]
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -5,8 +5,9 @@
 //   - "nats" mode: master-based discovery over NATS messaging
 //   - "etcd" mode: distributed discovery via etcd (maintained in a separate repository)
 //
-// Custom backends can be registered via Register() and selected via the "cluster.discovery.mode"
-// property in the profile configuration file.
+// Custom backends can be registered via Register() or RegisterFactory() and selected via the
+// "cluster.discovery.mode" property in the profile configuration file. Each Application gets
+// its own instance from the registered factory, so several applications can share a process.
 package cherryDiscovery
 
 import (
@@ -18,17 +19,22 @@ import (
 
 // discoveryMap holds all registered discovery component constructors, keyed by mode name.
 var (
-	discoveryMap = make(map[string]cfacade.IDiscoveryComponent)
+	discoveryMap = make(map[string]Factory)
 )
 
+// Factory creates a new discovery component instance.
+type Factory func() cfacade.IDiscoveryComponent
+
 func init() {
-	Register(&ComponentDefault{})
-	Register(&ComponentMaster{})
+	RegisterFactory("default", func() cfacade.IDiscoveryComponent { return &ComponentDefault{} })
+	RegisterFactory("nats", func() cfacade.IDiscoveryComponent { return &ComponentMaster{} })
 	// etcd mode is maintained in a separate repository (cherry-game/components/etcd)
 	// to avoid pulling etcd client dependencies into the core framework.
 }
 
 // Register adds a discovery component implementation to the registry.
+// Every lookup of its mode returns this same instance, so it can serve only one
+// Application; use RegisterFactory for backends shared by several applications.
 // Panics via log.Fatal if component is nil or its Mode() returns empty.
 func Register(component cfacade.IDiscoveryComponent) {
 	if component == nil {
@@ -36,20 +42,39 @@ func Register(component cfacade.IDiscoveryComponent) {
 		return
 	}
 
-	if component.Mode() == "" {
-		clog.Fatalf("Discovery mode is empty. %T", component)
+	RegisterFactory(component.Mode(), func() cfacade.IDiscoveryComponent {
+		return component
+	})
+}
+
+// RegisterFactory adds a discovery component constructor to the registry.
+// Panics via log.Fatal if mode is empty or factory is nil.
+func RegisterFactory(mode string, factory Factory) {
+	if mode == "" {
+		clog.Fatal("Discovery mode is empty.")
+		return
+	}
+
+	if factory == nil {
+		clog.Fatalf("Discovery factory is nil. mode = %s", mode)
 		return
 	}
 
-	discoveryMap[component.Mode()] = component
+	discoveryMap[mode] = factory
 }
 
-// New creates a discovery component based on the profile configuration.
+// New creates a discovery component based on the default profile.
+// See NewWithProfile.
+func New() cfacade.IDiscoveryComponent {
+	return NewWithProfile(cprofile.Default())
+}
+
+// NewWithProfile creates a discovery component based on the given profile.
 // It reads the mode from "cluster.discovery.mode" and instantiates
 // the corresponding registered component. Panics via log.Fatal on
 // missing config or unknown mode.
-func New() cfacade.IDiscoveryComponent {
-	mode, err := GetMode()
+func NewWithProfile(profile cfacade.IProfile) cfacade.IDiscoveryComponent {
+	mode, err := GetModeWithProfile(profile)
 	if err != nil {
 		clog.Fatal(err)
 		return nil
@@ -64,20 +89,25 @@ func New() cfacade.IDiscoveryComponent {
 	return component
 }
 
-// GetDiscovery looks up a registered discovery component by mode name.
+// GetDiscovery creates the discovery component registered for the mode name.
 func GetDiscovery(mode string) (cfacade.IDiscoveryComponent, error) {
-	value, found := discoveryMap[mode]
+	factory, found := discoveryMap[mode]
 	if !found {
 		return nil, cerror.Errorf("`cluster` mode not found. mode = %s", mode)
 	}
 
-	return value, nil
+	return factory(), nil
 }
 
-// GetMode reads the discovery mode from the profile configuration.
-// The config path is "cluster.discovery.mode".
+// GetMode reads the discovery mode from the default profile.
 func GetMode() (string, error) {
-	config := cprofile.GetConfig("cluster").GetConfig("discovery")
+	return GetModeWithProfile(cprofile.Default())
+}
+
+// GetModeWithProfile reads the discovery mode from the given profile.
+// The config path is "cluster.discovery.mode".
+func GetModeWithProfile(profile cfacade.IProfile) (string, error) {
+	config := profile.GetConfig("cluster").GetConfig("discovery")
 	if config.LastError() != nil {
 		return "", cerror.Error("`cluster` property not found in profile file.")
 	}
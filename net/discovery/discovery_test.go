@@ -25,6 +25,7 @@ func (t *testDiscoveryComponent) Random(string) (cfacade.IMember, bool)    { ret
 func (t *testDiscoveryComponent) GetMember(string) (cfacade.IMember, bool) { return nil, false }
 func (t *testDiscoveryComponent) UpdateSetting(string, string)              {}
 func (t *testDiscoveryComponent) UpdateSettings(map[string]string)          {}
+func (t *testDiscoveryComponent) SetStatus(int32)                           {}
 func (t *testDiscoveryComponent) OnAddMember(cfacade.MemberListener)       {}
 func (t *testDiscoveryComponent) OnUpdateMember(cfacade.MemberListener)    {}
 func (t *testDiscoveryComponent) OnRemoveMember(cfacade.MemberListener)    {}
@@ -70,3 +71,25 @@ func TestGetDiscovery_Builtins(t *testing.T) {
 		t.Fatal("nats component is nil")
 	}
 }
+
+// TestGetDiscovery_NewInstance verifies that a mode registered with a factory
+// returns a new component for every lookup, so applications do not share it.
+func TestGetDiscovery_NewInstance(t *testing.T) {
+	RegisterFactory("test-factory", func() cfacade.IDiscoveryComponent {
+		return &testDiscoveryComponent{mode: "test-factory"}
+	})
+
+	first, err := GetDiscovery("test-factory")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	second, err := GetDiscovery("test-factory")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if first == second {
+		t.Fatal("expected a new component for every lookup")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	cfacade "github.com/cherry-game/cherry/facade"
+	clog "github.com/cherry-game/cherry/logger"
 )
 
 // mockSerializer implements cfacade.ISerializer using protobuf marshal/unmarshal.
@@ -44,5 +45,8 @@ func (a *mockApp) Shutdown()                         {}
 func (a *mockApp) Discovery() cfacade.IDiscovery     { return nil }
 func (a *mockApp) Cluster() cfacade.ICluster         { return nil }
 func (a *mockApp) ActorSystem() cfacade.IActorSystem { return nil }
+func (a *mockApp) NetParser() cfacade.INetParser     { return nil }
 func (a *mockApp) Address() string                   { return "" }
 func (a *mockApp) Enabled() bool                     { return true }
+func (a *mockApp) Profile() cfacade.IProfile         { return nil }
+func (a *mockApp) Logger() cfacade.ILogger           { return clog.DefaultLogger }
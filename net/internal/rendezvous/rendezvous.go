@@ -0,0 +1,70 @@
+// Package cherryRendezvous chooses the member of a node type owning a key, shared
+// by the sharding and the singleton components.
+package cherryRendezvous
+
+import (
+	"hash/fnv"
+
+	cfacade "github.com/cherry-game/cherry/facade"
+)
+
+// Members returns the nodeIDs of the serving members of nodeType as seen by
+// app, including the current node. These are the candidates of Select.
+func Members(app cfacade.IApplication, nodeType string) []string {
+	var nodeIDs []string
+	if discovery := app.Discovery(); discovery != nil {
+		for _, member := range discovery.ListByType(nodeType, app.NodeID()) {
+			nodeIDs = append(nodeIDs, member.GetNodeID())
+		}
+	}
+
+	// the current node may not be in its own discovery list
+	if app.NodeType() == nodeType && serving(app) {
+		nodeIDs = append(nodeIDs, app.NodeID())
+	}
+
+	return nodeIDs
+}
+
+// serving reports whether the current node accepts new traffic. A draining or
+// leaving node owns no shard and hosts no singleton.
+func serving(app cfacade.IApplication) bool {
+	if app.Discovery() == nil {
+		return true
+	}
+
+	member, found := app.Discovery().GetMember(app.NodeID())
+	return !found || member.GetStatus() == cfacade.MemberServing
+}
+
+// Select returns the nodeID with the highest hash of key, "" if nodeIDs
+// is empty. Every node computes the same result from the same set of nodeIDs,
+// and removing a node only moves the keys it was chosen for.
+func Select(key string, nodeIDs []string) string {
+	var (
+		chosen    string
+		maxWeight uint64
+	)
+
+	for _, nodeID := range nodeIDs {
+		if w := weight(nodeID, key); chosen == "" || w > maxWeight {
+			chosen, maxWeight = nodeID, w
+		}
+	}
+
+	return chosen
+}
+
+func weight(nodeID, key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(nodeID + ":" + key))
+
+	// fnv alone ranks nodeIDs differing in one byte the same way for every key
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
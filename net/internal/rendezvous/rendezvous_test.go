@@ -0,0 +1,136 @@
+package cherryRendezvous
+
+import (
+	"strconv"
+	"testing"
+
+	cfacade "github.com/cherry-game/cherry/facade"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+// testApp implements the parts of cfacade.IApplication used by Members.
+type testApp struct {
+	cfacade.IApplication
+	nodeID    string
+	nodeType  string
+	discovery cfacade.IDiscovery
+}
+
+func (a *testApp) NodeID() string                { return a.nodeID }
+func (a *testApp) NodeType() string              { return a.nodeType }
+func (a *testApp) Discovery() cfacade.IDiscovery { return a.discovery }
+
+// testDiscovery knows a fixed set of members.
+type testDiscovery struct {
+	cfacade.IDiscovery
+	members []*cproto.Member
+}
+
+func (d *testDiscovery) ListByType(nodeType string, filterNodeID ...string) []cfacade.IMember {
+	var list []cfacade.IMember
+	for _, member := range d.members {
+		if member.NodeType != nodeType || member.Status != cfacade.MemberServing {
+			continue
+		}
+		if len(filterNodeID) > 0 && member.NodeID == filterNodeID[0] {
+			continue
+		}
+		list = append(list, member)
+	}
+	return list
+}
+
+func (d *testDiscovery) GetMember(nodeID string) (cfacade.IMember, bool) {
+	for _, member := range d.members {
+		if member.NodeID == nodeID {
+			return member, true
+		}
+	}
+	return nil, false
+}
+
+func nodeIDs(n int) []string {
+	var list []string
+	for i := 1; i <= n; i++ {
+		list = append(list, "game-"+strconv.Itoa(i))
+	}
+	return list
+}
+
+func owners(keys int, nodeIDs []string) map[string]string {
+	result := make(map[string]string, keys)
+	for i := 0; i < keys; i++ {
+		key := strconv.Itoa(i)
+		result[key] = Select(key, nodeIDs)
+	}
+	return result
+}
+
+// TestSelect_Stable verifies that the chosen node does not depend on the
+// order of the nodeIDs and that every node gets a share of the keys.
+func TestSelect_Stable(t *testing.T) {
+	if nodeID := Select("key", nil); nodeID != "" {
+		t.Fatalf("expected no node, got %s", nodeID)
+	}
+
+	list := nodeIDs(4)
+	reversed := []string{list[3], list[2], list[1], list[0]}
+
+	counts := map[string]int{}
+	for key, nodeID := range owners(1000, list) {
+		if other := Select(key, reversed); other != nodeID {
+			t.Fatalf("key %s: %s in order, %s reversed", key, nodeID, other)
+		}
+		counts[nodeID]++
+	}
+
+	for _, nodeID := range list {
+		if counts[nodeID] < 150 {
+			t.Fatalf("unbalanced keys. counts = %v", counts)
+		}
+	}
+}
+
+// TestSelect_MinimalMovement verifies that removing a node only moves
+// the keys it owned and adding a node only moves keys to it.
+func TestSelect_MinimalMovement(t *testing.T) {
+	before := owners(1000, nodeIDs(4))
+
+	for key, nodeID := range owners(1000, nodeIDs(3)) {
+		if before[key] != "game-4" && before[key] != nodeID {
+			t.Fatalf("key %s moved from %s to %s after removing game-4", key, before[key], nodeID)
+		}
+	}
+
+	for key, nodeID := range owners(1000, nodeIDs(5)) {
+		if nodeID != "game-5" && before[key] != nodeID {
+			t.Fatalf("key %s moved from %s to %s after adding game-5", key, before[key], nodeID)
+		}
+	}
+}
+
+// TestMembers verifies that the current node is a member only while it is
+// serving and that members of other node types are left out.
+func TestMembers(t *testing.T) {
+	self := &cproto.Member{NodeID: "game-1", NodeType: "game", Status: cfacade.MemberServing}
+	discovery := &testDiscovery{members: []*cproto.Member{
+		self,
+		{NodeID: "game-2", NodeType: "game", Status: cfacade.MemberServing},
+		{NodeID: "game-3", NodeType: "game", Status: cfacade.MemberDraining},
+		{NodeID: "center-1", NodeType: "center", Status: cfacade.MemberServing},
+	}}
+	app := &testApp{nodeID: "game-1", nodeType: "game", discovery: discovery}
+
+	if members := Members(app, "game"); len(members) != 2 || members[0] != "game-2" || members[1] != "game-1" {
+		t.Fatalf("expected [game-2 game-1], got %v", members)
+	}
+
+	self.Status = cfacade.MemberDraining
+	if members := Members(app, "game"); len(members) != 1 || members[0] != "game-2" {
+		t.Fatalf("expected [game-2] while draining, got %v", members)
+	}
+
+	if members := Members(&testApp{nodeID: "game-1", nodeType: "game"}, "game"); len(members) != 1 || members[0] != "game-1" {
+		t.Fatalf("expected [game-1] without discovery, got %v", members)
+	}
+}
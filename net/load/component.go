@@ -0,0 +1,251 @@
+package cherryLoad
+
+import (
+	"math"
+	"runtime"
+	"runtime/metrics"
+	"strconv"
+	"sync"
+	"time"
+
+	cfacade "github.com/cherry-game/cherry/facade"
+	cadmin "github.com/cherry-game/cherry/net/admin"
+	cbalancer "github.com/cherry-game/cherry/net/balancer"
+)
+
+/**
+- The load component publishes the load of the node into its member settings,
+  other nodes read them from IMember.GetSettings():
+	- load.agents      clients connected to this node, if the net parser is a cfacade.ISessionCounter.
+	- load.actors      running actors, children included.
+	- load.mailbox     average queued messages per actor.
+	- load.goroutines  goroutines of the process.
+	- load.cpu         cpu used by the process, 0..1 of GOMAXPROCS.
+	- load.heap        heap bytes of live and unswept objects.
+	- load             load score read by the least_loaded balancer, load.cpu by default.
+- Loads are collected every interval but published only when a value moved by
+  more than the threshold, so discovery is not flooded with updates.
+- The last collected loads are served by the admin component at /load.
+*/
+
+// Member settings published by the component.
+const (
+	SettingAgents     = "load.agents"
+	SettingActors     = "load.actors"
+	SettingMailbox    = "load.mailbox"
+	SettingGoroutines = "load.goroutines"
+	SettingCPU        = "load.cpu"
+	SettingHeap       = "load.heap"
+)
+
+var (
+	Name = "load_component"
+)
+
+type (
+	Component struct {
+		cfacade.Component
+		interval  time.Duration
+		threshold float64
+		loadFunc  LoadFunc
+		die       chan struct{}
+		mu        sync.Mutex
+		stats     Stats              // last collected
+		published map[string]float64 // last published values
+		cpuTotal  float64            // cumulative cpu seconds of the last collection
+		cpuIdle   float64
+	}
+
+	// Stats is the load of the node.
+	Stats struct {
+		Agents     int     `json:"agents"`
+		Actors     int     `json:"actors"`
+		Mailbox    float64 `json:"mailbox"`
+		Goroutines int     `json:"goroutines"`
+		CPU        float64 `json:"cpu"`
+		Heap       uint64  `json:"heap"`
+		Load       float64 `json:"load"`
+	}
+
+	// LoadFunc computes the load score of the node from its stats.
+	LoadFunc func(stats Stats) float64
+
+	// actorCounter is implemented by the actor component.
+	actorCounter interface {
+		Stats() (actors int, queued int64)
+	}
+)
+
+// New creates the load component collecting the loads every interval (5s if <= 0).
+func New(interval time.Duration) *Component {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	return &Component{
+		interval:  interval,
+		threshold: 0.1,
+		loadFunc: func(stats Stats) float64 {
+			return stats.CPU
+		},
+		die: make(chan struct{}),
+	}
+}
+
+func (c *Component) Name() string {
+	return Name
+}
+
+// SetThreshold publishes a value only when it moved by more than ratio of its
+// last published value, or by more than ratio for values below 1 (default 0.1).
+func (c *Component) SetThreshold(ratio float64) {
+	c.threshold = ratio
+}
+
+// SetLoadFunc sets the function computing the "load" setting.
+func (c *Component) SetLoadFunc(fn LoadFunc) {
+	if fn != nil {
+		c.loadFunc = fn
+	}
+}
+
+// Stats returns the last collected loads.
+func (c *Component) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats
+}
+
+func (c *Component) Init() {
+	cadmin.Find(c.App()).Handle("load", func() any {
+		return c.Stats()
+	})
+}
+
+func (c *Component) OnAfterInit() {
+	c.report()
+
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.report()
+			case <-c.die:
+				return
+			}
+		}
+	}()
+}
+
+func (c *Component) OnStop() {
+	close(c.die)
+}
+
+// report collects the loads and publishes them when they changed.
+func (c *Component) report() {
+	stats := c.collect()
+	values := stats.values()
+
+	c.mu.Lock()
+	c.stats = stats
+	changed := c.changed(values)
+	if changed {
+		c.published = values
+	}
+	c.mu.Unlock()
+
+	if !changed || c.App().Discovery() == nil {
+		return
+	}
+
+	settings := make(map[string]string, len(values))
+	for key, value := range values {
+		settings[key] = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	c.App().Discovery().UpdateSettings(settings)
+	c.App().Logger().Debugf("[%s] publish load. [settings = %v]", Name, settings)
+}
+
+// changed reports whether a value moved by more than the threshold.
+func (c *Component) changed(values map[string]float64) bool {
+	if c.published == nil {
+		return true
+	}
+
+	for key, value := range values {
+		last := c.published[key]
+		if math.Abs(value-last) > c.threshold*math.Max(math.Abs(last), 1) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Component) collect() Stats {
+	stats := Stats{
+		Goroutines: runtime.NumGoroutine(),
+	}
+
+	if counter, ok := c.App().NetParser().(cfacade.ISessionCounter); ok {
+		stats.Agents = counter.SessionCount()
+	}
+
+	if system, ok := c.App().ActorSystem().(actorCounter); ok {
+		actors, queued := system.Stats()
+		stats.Actors = actors
+		if actors > 0 {
+			stats.Mailbox = float64(queued) / float64(actors)
+		}
+	}
+
+	samples := []metrics.Sample{
+		{Name: "/cpu/classes/total:cpu-seconds"},
+		{Name: "/cpu/classes/idle:cpu-seconds"},
+		{Name: "/memory/classes/heap/objects:bytes"},
+	}
+	metrics.Read(samples)
+
+	total, idle := sampleFloat(samples[0]), sampleFloat(samples[1])
+	if delta := total - c.cpuTotal; c.cpuTotal > 0 && delta > 0 {
+		stats.CPU = math.Max(0, math.Min(1, (delta-(idle-c.cpuIdle))/delta))
+	}
+	c.cpuTotal, c.cpuIdle = total, idle
+
+	if samples[2].Value.Kind() == metrics.KindUint64 {
+		stats.Heap = samples[2].Value.Uint64()
+	}
+
+	stats.Load = c.loadFunc(stats)
+	return stats
+}
+
+func (s Stats) values() map[string]float64 {
+	return map[string]float64{
+		SettingAgents:         float64(s.Agents),
+		SettingActors:         float64(s.Actors),
+		SettingMailbox:        s.Mailbox,
+		SettingGoroutines:     float64(s.Goroutines),
+		SettingCPU:            s.CPU,
+		SettingHeap:           float64(s.Heap),
+		cbalancer.SettingLoad: s.Load,
+	}
+}
+
+func sampleFloat(sample metrics.Sample) float64 {
+	if sample.Value.Kind() == metrics.KindFloat64 {
+		return sample.Value.Float64()
+	}
+	return 0
+}
+
+// Find returns the load component registered in app, or nil.
+func Find(app cfacade.IApplication) *Component {
+	component, _ := app.Find(Name).(*Component)
+	return component
+}
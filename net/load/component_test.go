@@ -0,0 +1,100 @@
+package cherryLoad
+
+import (
+	"testing"
+
+	cfacade "github.com/cherry-game/cherry/facade"
+	clog "github.com/cherry-game/cherry/logger"
+	cbalancer "github.com/cherry-game/cherry/net/balancer"
+)
+
+// testApp implements the parts of cfacade.IApplication used by report.
+type testApp struct {
+	cfacade.IApplication
+	discovery *testDiscovery
+}
+
+func (a *testApp) NodeID() string                    { return "game-1" }
+func (a *testApp) Logger() cfacade.ILogger           { return clog.DefaultLogger }
+func (a *testApp) ActorSystem() cfacade.IActorSystem { return nil }
+func (a *testApp) NetParser() cfacade.INetParser     { return nil }
+func (a *testApp) Discovery() cfacade.IDiscovery     { return a.discovery }
+
+// testDiscovery records the published settings.
+type testDiscovery struct {
+	cfacade.IDiscovery
+	published []map[string]string
+}
+
+func (d *testDiscovery) UpdateSettings(settings map[string]string) {
+	d.published = append(d.published, settings)
+}
+
+// TestComponent_Changed verifies the threshold, relative to the last value
+// and absolute below 1.
+func TestComponent_Changed(t *testing.T) {
+	c := New(0)
+
+	if !c.changed(map[string]float64{SettingActors: 100}) {
+		t.Fatal("the first values should be published")
+	}
+
+	c.published = map[string]float64{SettingActors: 100, SettingCPU: 0.5}
+
+	tests := []struct {
+		values  map[string]float64
+		changed bool
+	}{
+		{map[string]float64{SettingActors: 100, SettingCPU: 0.5}, false},
+		{map[string]float64{SettingActors: 109, SettingCPU: 0.5}, false},
+		{map[string]float64{SettingActors: 111, SettingCPU: 0.5}, true},
+		{map[string]float64{SettingActors: 100, SettingCPU: 0.55}, false},
+		{map[string]float64{SettingActors: 100, SettingCPU: 0.65}, true},
+	}
+
+	for _, test := range tests {
+		if changed := c.changed(test.values); changed != test.changed {
+			t.Fatalf("values %v: expected changed = %v", test.values, test.changed)
+		}
+	}
+
+	c.SetThreshold(0.5)
+	if c.changed(map[string]float64{SettingActors: 140, SettingCPU: 0.5}) {
+		t.Fatal("a move below the new threshold should not be published")
+	}
+}
+
+// TestComponent_Report verifies that report publishes the load computed by
+// the load func, and publishes again only after a value changed.
+func TestComponent_Report(t *testing.T) {
+	discovery := &testDiscovery{}
+
+	c := New(0)
+	c.Set(&testApp{discovery: discovery})
+	c.SetThreshold(1e9)
+
+	load := 3.0
+	c.SetLoadFunc(func(Stats) float64 {
+		return load
+	})
+
+	c.report()
+	if len(discovery.published) != 1 || discovery.published[0][cbalancer.SettingLoad] != "3" {
+		t.Fatalf("expected the load to be published, got %v", discovery.published)
+	}
+
+	if stats := c.Stats(); stats.Load != 3 {
+		t.Fatalf("expected the collected load, got %v", stats.Load)
+	}
+
+	c.report()
+	if len(discovery.published) != 1 {
+		t.Fatalf("unchanged loads should not be published, got %v", discovery.published)
+	}
+
+	load = 3e10
+	c.report()
+	if len(discovery.published) != 2 || discovery.published[1][cbalancer.SettingLoad] != "30000000000" {
+		t.Fatalf("expected the new load to be published, got %v", discovery.published)
+	}
+}
@@ -0,0 +1,126 @@
+package cherryMetrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	cfacade "github.com/cherry-game/cherry/facade"
+	cactor "github.com/cherry-game/cherry/net/actor"
+)
+
+/**
+- The metrics component serves the registry at http://address/metrics.
+- Packages instrument themselves when the component is registered:
+	- actor system: invocation/arrival latency, mailbox depths, actor count,
+	  active timers, cluster request latency and errors by cherryCode.
+	- net parsers: online agents, packets in/out per type.
+- Application metrics can be added through Find(app).Counter(...) etc.
+*/
+
+var (
+	Name = "metrics_component"
+)
+
+type Component struct {
+	cfacade.Component
+	*Registry
+	address  string
+	listener net.Listener
+	server   *http.Server
+}
+
+// New creates the metrics component listening on address, e.g. ":9100".
+func New(address string) *Component {
+	return &Component{
+		Registry: NewRegistry(),
+		address:  address,
+	}
+}
+
+func (c *Component) Name() string {
+	return Name
+}
+
+func (c *Component) InitE() error {
+	listener, err := net.Listen("tcp", c.address)
+	if err != nil {
+		return err
+	}
+	c.listener = listener
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", c.Registry)
+
+	c.server = &http.Server{
+		Handler:     mux,
+		ReadTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := c.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			c.App().Logger().Warnf("[%s] serve error. err = %v", Name, err)
+		}
+	}()
+
+	c.App().Logger().Infof("[%s] serve metrics at %s/metrics", Name, listener.Addr())
+	return nil
+}
+
+// OnAfterInit registers the metrics of the actor system.
+func (c *Component) OnAfterInit() {
+	if system := cactor.Find(c.App()); system != nil {
+		system.SetMetrics(actorMetrics{c.Registry})
+	}
+}
+
+// Addr returns the listening address, nil before Init.
+func (c *Component) Addr() net.Addr {
+	if c.listener == nil {
+		return nil
+	}
+
+	return c.listener.Addr()
+}
+
+func (c *Component) OnStop() {
+	if c.server == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := c.server.Shutdown(ctx); err != nil {
+		c.App().Logger().Warnf("[%s] shutdown error. err = %v", Name, err)
+	}
+}
+
+// Find returns the registry of the metrics component registered in app,
+// or nil when metrics are disabled.
+func Find(app cfacade.IApplication) *Registry {
+	if component, ok := app.Find(Name).(*Component); ok {
+		return component.Registry
+	}
+
+	return nil
+}
+
+// actorMetrics is the registry as seen by the actor system.
+type actorMetrics struct {
+	registry *Registry
+}
+
+func (m actorMetrics) Histogram(name, help string, buckets []float64, labelNames ...string) cactor.IHistogram {
+	return m.registry.Histogram(name, help, buckets, labelNames...)
+}
+
+func (m actorMetrics) Counter(name, help string, labelNames ...string) cactor.ICounter {
+	return m.registry.Counter(name, help, labelNames...)
+}
+
+func (m actorMetrics) GaugeFunc(name, help string, labelNames []string, collect func(set func(value float64, labelValues ...string))) {
+	m.registry.GaugeFunc(name, help, labelNames, collect)
+}
@@ -0,0 +1,169 @@
+package cherryMetrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// DefBuckets are the default latency buckets (seconds).
+	DefBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+)
+
+type (
+	// collector writes the samples of a metric family.
+	collector interface {
+		write(w *writer)
+	}
+
+	desc struct {
+		name       string
+		help       string
+		labelNames []string
+	}
+
+	// Counter is a monotonically increasing value partitioned by label values.
+	// A nil *Counter is valid and records nothing.
+	Counter struct {
+		desc
+		series sync.Map // key:joined label values, value:*counterSeries
+	}
+
+	counterSeries struct {
+		labelValues []string
+		value       atomic.Uint64
+	}
+
+	// Histogram counts observations in buckets, partitioned by label values.
+	// A nil *Histogram is valid and records nothing.
+	Histogram struct {
+		desc
+		buckets []float64 // upper bounds, ascending
+		series  sync.Map  // key:joined label values, value:*histogramSeries
+	}
+
+	histogramSeries struct {
+		labelValues []string
+		counts      []atomic.Uint64 // per bucket, the last one is +Inf
+		count       atomic.Uint64
+		sum         atomic.Uint64 // float64 bits
+	}
+
+	// GaugeCollectFunc reports the current values of a gauge on each scrape.
+	GaugeCollectFunc func(set func(value float64, labelValues ...string))
+
+	gaugeFunc struct {
+		desc
+		collect GaugeCollectFunc
+	}
+)
+
+func seriesKey(labelValues []string) string {
+	return strings.Join(labelValues, "\xff")
+}
+
+// Inc increments the counter of labelValues by 1.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increments the counter of labelValues by delta.
+func (c *Counter) Add(delta uint64, labelValues ...string) {
+	if c == nil {
+		return
+	}
+
+	key := seriesKey(labelValues)
+	value, found := c.series.Load(key)
+	if !found {
+		value, _ = c.series.LoadOrStore(key, &counterSeries{
+			labelValues: append([]string(nil), labelValues...),
+		})
+	}
+
+	value.(*counterSeries).value.Add(delta)
+}
+
+func (c *Counter) write(w *writer) {
+	w.header(c.desc, "counter")
+
+	for _, s := range sortedSeries[*counterSeries](&c.series) {
+		w.sample(c.name, c.labelNames, s.labelValues, "", "", float64(s.value.Load()))
+	}
+}
+
+// Observe adds an observation of labelValues, e.g. a latency in seconds.
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	if h == nil {
+		return
+	}
+
+	key := seriesKey(labelValues)
+	v, found := h.series.Load(key)
+	if !found {
+		v, _ = h.series.LoadOrStore(key, &histogramSeries{
+			labelValues: append([]string(nil), labelValues...),
+			counts:      make([]atomic.Uint64, len(h.buckets)+1),
+		})
+	}
+
+	s := v.(*histogramSeries)
+	s.counts[sort.SearchFloat64s(h.buckets, value)].Add(1)
+	s.count.Add(1)
+
+	for {
+		old := s.sum.Load()
+		if s.sum.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+value)) {
+			break
+		}
+	}
+}
+
+func (h *Histogram) write(w *writer) {
+	w.header(h.desc, "histogram")
+
+	for _, s := range sortedSeries[*histogramSeries](&h.series) {
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.counts[i].Load()
+			w.sample(h.name+"_bucket", h.labelNames, s.labelValues, "le", formatFloat(upper), float64(cumulative))
+		}
+
+		cumulative += s.counts[len(h.buckets)].Load()
+		w.sample(h.name+"_bucket", h.labelNames, s.labelValues, "le", "+Inf", float64(cumulative))
+		w.sample(h.name+"_sum", h.labelNames, s.labelValues, "", "", math.Float64frombits(s.sum.Load()))
+		w.sample(h.name+"_count", h.labelNames, s.labelValues, "", "", float64(s.count.Load()))
+	}
+}
+
+func (g *gaugeFunc) write(w *writer) {
+	w.header(g.desc, "gauge")
+
+	g.collect(func(value float64, labelValues ...string) {
+		w.sample(g.name, g.labelNames, labelValues, "", "", value)
+	})
+}
+
+// sortedSeries returns the series of m ordered by key, so that scrapes are stable.
+func sortedSeries[T any](m *sync.Map) []T {
+	var keys []string
+	values := map[string]T{}
+
+	m.Range(func(key, value any) bool {
+		keys = append(keys, key.(string))
+		values[key.(string)] = value.(T)
+		return true
+	})
+
+	sort.Strings(keys)
+
+	list := make([]T, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, values[key])
+	}
+
+	return list
+}
@@ -0,0 +1,196 @@
+package cherryMetrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	clog "github.com/cherry-game/cherry/logger"
+)
+
+// Registry holds the metrics of a node and writes them in the Prometheus
+// text exposition format. A nil *Registry is valid: the metrics it returns
+// are nil and record nothing.
+type Registry struct {
+	mu      sync.Mutex
+	names   map[string]collector
+	ordered []collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		names: make(map[string]collector),
+	}
+}
+
+// Counter returns the counter registered with name, registering it first if needed.
+func (r *Registry) Counter(name, help string, labelNames ...string) *Counter {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c, ok := r.names[name].(*Counter); ok {
+		return c
+	}
+
+	c := &Counter{desc: desc{name, help, labelNames}}
+	r.add(name, c)
+	return c
+}
+
+// Histogram returns the histogram registered with name, registering it first
+// if needed. buckets are the ascending upper bounds, DefBuckets if nil.
+func (r *Registry) Histogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if h, ok := r.names[name].(*Histogram); ok {
+		return h
+	}
+
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+
+	h := &Histogram{desc: desc{name, help, labelNames}, buckets: buckets}
+	r.add(name, h)
+	return h
+}
+
+// GaugeFunc registers a gauge whose values are reported by collect on each
+// scrape. A gauge registered again with the same name replaces the previous one.
+func (r *Registry) GaugeFunc(name, help string, labelNames []string, collect GaugeCollectFunc) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	g := &gaugeFunc{desc: desc{name, help, labelNames}, collect: collect}
+
+	if old, ok := r.names[name].(*gaugeFunc); ok {
+		for i, c := range r.ordered {
+			if c == collector(old) {
+				r.ordered[i] = g
+			}
+		}
+		r.names[name] = g
+		return
+	}
+
+	r.add(name, g)
+}
+
+func (r *Registry) add(name string, c collector) {
+	if _, found := r.names[name]; found {
+		clog.Warnf("[Registry] metric registered with another type. [name = %s]", name)
+		return
+	}
+
+	r.names[name] = c
+	r.ordered = append(r.ordered, c)
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format.
+func (r *Registry) WriteTo(out io.Writer) (int64, error) {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.ordered...)
+	r.mu.Unlock()
+
+	w := &writer{Writer: bufio.NewWriter(out)}
+	for _, c := range collectors {
+		c.write(w)
+	}
+
+	err := w.Flush()
+	return w.n, err
+}
+
+// ServeHTTP serves the metrics to a Prometheus scrape.
+func (r *Registry) ServeHTTP(rw http.ResponseWriter, _ *http.Request) {
+	rw.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	if _, err := r.WriteTo(rw); err != nil {
+		clog.Warnf("[Registry] write metrics error. err = %v", err)
+	}
+}
+
+type writer struct {
+	*bufio.Writer
+	n int64
+}
+
+func (w *writer) write(s string) {
+	n, _ := w.WriteString(s)
+	w.n += int64(n)
+}
+
+func (w *writer) header(d desc, typ string) {
+	w.write("# HELP " + d.name + " " + escape(d.help, false) + "\n")
+	w.write("# TYPE " + d.name + " " + typ + "\n")
+}
+
+// sample writes a line of metric name. extraName/extraValue is an additional
+// label, e.g. the "le" bound of a histogram bucket.
+func (w *writer) sample(name string, labelNames, labelValues []string, extraName, extraValue string, value float64) {
+	w.write(name)
+
+	if len(labelNames) > 0 || extraName != "" {
+		w.write("{")
+		for i, labelName := range labelNames {
+			if i > 0 {
+				w.write(",")
+			}
+
+			labelValue := ""
+			if i < len(labelValues) {
+				labelValue = labelValues[i]
+			}
+			w.write(labelName + "=\"" + escape(labelValue, true) + "\"")
+		}
+
+		if extraName != "" {
+			if len(labelNames) > 0 {
+				w.write(",")
+			}
+			w.write(extraName + "=\"" + extraValue + "\"")
+		}
+		w.write("}")
+	}
+
+	w.write(" " + formatFloat(value) + "\n")
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// escape escapes a help text or, with quote, a label value.
+func escape(s string, quote bool) string {
+	if quote {
+		return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(s)
+	}
+
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
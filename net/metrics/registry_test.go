@@ -0,0 +1,57 @@
+package cherryMetrics
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRegistry_WriteTo verifies the Prometheus text format of each metric type.
+func TestRegistry_WriteTo(t *testing.T) {
+	registry := NewRegistry()
+
+	counter := registry.Counter("requests_total", "Requests.", "code")
+	counter.Inc("0")
+	counter.Add(2, "33")
+	counter.Inc("0")
+
+	if registry.Counter("requests_total", "Requests.", "code") != counter {
+		t.Fatal("expected the registered counter")
+	}
+
+	histogram := registry.Histogram("latency_seconds", "Latency.", []float64{0.1, 1}, "func")
+	histogram.Observe(0.05, "login")
+	histogram.Observe(0.5, "login")
+	histogram.Observe(2, "login")
+
+	registry.GaugeFunc("online", "Online \"agents\".", nil, func(set func(float64, ...string)) {
+		set(3)
+	})
+
+	var nilRegistry *Registry
+	nilRegistry.Counter("none", "").Inc()
+	nilRegistry.Histogram("none", "", nil).Observe(1)
+
+	var sb strings.Builder
+	if _, err := registry.WriteTo(&sb); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# HELP requests_total Requests.
+# TYPE requests_total counter
+requests_total{code="0"} 2
+requests_total{code="33"} 2
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{func="login",le="0.1"} 1
+latency_seconds_bucket{func="login",le="1"} 2
+latency_seconds_bucket{func="login",le="+Inf"} 3
+latency_seconds_sum{func="login"} 2.55
+latency_seconds_count{func="login"} 3
+# HELP online Online "agents".
+# TYPE online gauge
+online 3
+`
+	if sb.String() != expected {
+		t.Fatalf("unexpected output:\n%s", sb.String())
+	}
+}
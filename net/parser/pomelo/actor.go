@@ -1,6 +1,7 @@
 package pomelo
 
 import (
+	"context"
 	"net"
 	"time"
 
@@ -23,6 +24,8 @@ type (
 		connectors     []cfacade.IConnector
 		onNewAgentFunc OnNewAgentFunc
 		onInitFunc     func()
+		drainReason    interface{} // kick reason pushed to clients on drain
+		components                 // components of the app, passed to the agents
 	}
 
 // OnNewAgentFunc is called when a new agent connection is established.
@@ -39,6 +42,7 @@ func NewActor(agentActorID string) *Actor {
 		agentActorID: agentActorID,
 		connectors:   make([]cfacade.IConnector, 0),
 		onInitFunc:   nil,
+		drainReason:  &cproto.I32{Value: ccode.NodeShutdown},
 	}
 
 	return parser
@@ -69,10 +73,11 @@ func (p *Actor) Load(app cfacade.IApplication) {
 	}
 
 	cmd.init(app)
+	p.components = findComponents(app)
 
 	//  Create agent actor
 	if _, err := app.ActorSystem().CreateActor(p.agentActorID, p); err != nil {
-		clog.Panicf("Create agent actor fail. err = %+v", err)
+		p.App().Logger().Panicf("Create agent actor fail. err = %+v", err)
 	}
 
 	for _, connector := range p.connectors {
@@ -101,6 +106,7 @@ func (p *Actor) defaultOnConnectFunc(conn net.Conn) {
 	}
 
 	agent := NewAgent(p.App(), conn, session)
+	agent.components = p.components
 
 	if p.onNewAgentFunc != nil {
 		p.onNewAgentFunc(agent)
@@ -110,6 +116,55 @@ func (p *Actor) defaultOnConnectFunc(conn net.Conn) {
 	agent.Run()
 }
 
+// SetDrainReason sets the kick reason pushed to every client when the node
+// starts draining. Defaults to cproto.I32{Value: ccode.NodeShutdown}.
+func (p *Actor) SetDrainReason(reason interface{}) {
+	if reason != nil {
+		p.drainReason = reason
+	}
+}
+
+// SessionCount returns the number of clients connected to this node.
+func (p *Actor) SessionCount() int {
+	return CountNode(p.App().NodeID())
+}
+
+// OnDrain kicks the idle clients connected to this node right away, and the
+// others once their requests are answered. The clients still busy when ctx is
+// done are kicked too.
+func (p *Actor) OnDrain(ctx context.Context) {
+	nodeID := p.App().NodeID()
+	p.App().Logger().Infof("[agentActorID = %s] drain. kick idle agents. [count = %d]", p.agentActorID, CountNode(nodeID))
+
+	kicked := map[*Agent]bool{}
+	kick := func(all bool) {
+		ForeachNodeAgent(nodeID, func(agent *Agent) {
+			if !kicked[agent] && (all || agent.Idle()) {
+				kicked[agent] = true
+				agent.Kick(p.drainReason, true)
+			}
+		})
+	}
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		kick(false)
+		if CountNode(nodeID) < 1 {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			p.App().Logger().Warnf("[agentActorID = %s] drain timeout. kick busy agents. [remaining agents = %d]", p.agentActorID, CountNode(nodeID))
+			kick(true)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // SetDictionary sets the route-to-code dictionary used for route compression.
 func (*Actor) SetDictionary(dict map[string]uint16) {
 	pomeloMessage.SetDictionary(dict)
@@ -161,7 +216,7 @@ func (p *Actor) response(rsp *cproto.PomeloResponse) {
 	agent, found := GetAgentWithSID(rsp.Sid)
 	if !found {
 		if clog.PrintLevel(zapcore.DebugLevel) {
-			clog.Debugf("[response] Not found agent. [rsp = %+v]", rsp)
+			p.App().Logger().Debugf("[response] Not found agent. [rsp = %+v]", rsp)
 		}
 		return
 	}
@@ -170,7 +225,12 @@ func (p *Actor) response(rsp *cproto.PomeloResponse) {
 		agent.ResponseMID(rsp.Mid, rsp.Data, false)
 	} else {
 		errRsp := &cproto.Response{
-			Code: rsp.Code,
+			Code:    rsp.Code,
+			Message: rsp.Message,
+			Details: rsp.Details,
+		}
+		if errRsp.Message == "" {
+			errRsp.Message = ccode.Desc(rsp.Code)
 		}
 		agent.ResponseMID(rsp.Mid, errRsp, true)
 	}
@@ -200,7 +260,7 @@ func (p *Actor) broadcast(rsp *cproto.PomeloBroadcast) {
 	switch rsp.PushType {
 	case cproto.PomeloBroadcast_AllUID:
 		{
-			ForeachAgent(func(agent *Agent) {
+			ForeachNodeAgent(p.App().NodeID(), func(agent *Agent) {
 				if agent.IsBind() {
 					agent.Push(rsp.Route, rsp.Data)
 				}
@@ -1,8 +1,8 @@
 package pomelo
 
 import (
+	cerror "github.com/cherry-game/cherry/error"
 	cfacade "github.com/cherry-game/cherry/facade"
-	clog "github.com/cherry-game/cherry/logger"
 	cactor "github.com/cherry-game/cherry/net/actor"
 	cproto "github.com/cherry-game/cherry/net/proto"
 )
@@ -31,6 +31,11 @@ func (p *ActorBase) ResponseCode(session *cproto.Session, statusCode int32) {
 	ResponseCode(p, session.AgentPath, session.Sid, session.GetMID(), statusCode)
 }
 
+// ResponseError sends an error response with the code, message and details of err back to the client.
+func (p *ActorBase) ResponseError(session *cproto.Session, err error) {
+	ResponseError(p, session.AgentPath, session.Sid, session.GetMID(), err)
+}
+
 // Push sends a push message to the client identified by the session's sid.
 func (p *ActorBase) Push(session *cproto.Session, route string, v any) {
 	PushWithSID(p, session.AgentPath, session.Sid, route, v)
@@ -51,7 +56,7 @@ func (p *ActorBase) Kick(session *cproto.Session, reason any, closed bool) {
 func Response(iActor cfacade.IActor, agentPath, sid string, mid uint32, v any) {
 	data, err := iActor.App().Serializer().Marshal(v)
 	if err != nil {
-		clog.Warnf("[Response] Marshal error. agentPath = %s, v = %+v", agentPath, v)
+		iActor.App().Logger().Warnf("[Response] Marshal error. agentPath = %s, v = %+v", agentPath, v)
 		return
 	}
 
@@ -75,10 +80,27 @@ func ResponseCode(iActor cfacade.IActor, agentPath, sid string, mid uint32, stat
 	iActor.Call(agentPath, ResponseFuncName, rsp)
 }
 
+// ResponseError looks up the agent by request mid and sends an error response
+// with the code, message and details of err back to the client.
+func ResponseError(iActor cfacade.IActor, agentPath, sid string, mid uint32, err error) {
+	rsp := &cproto.PomeloResponse{
+		Sid: sid,
+		Mid: mid,
+	}
+
+	if codeErr := cerror.AsCode(err); codeErr != nil {
+		rsp.Code = codeErr.Code
+		rsp.Message = codeErr.Message
+		rsp.Details = codeErr.Details
+	}
+
+	iActor.Call(agentPath, ResponseFuncName, rsp)
+}
+
 // Push looks up the agent by sid or uid and sends a push message to the client.
 func Push(iActor cfacade.IActor, agentPath, sid string, uid cfacade.UID, route string, v any) {
 	if sid == "" && uid < 1 {
-		clog.Warnf("[Push] sid or uid value error. agentPath = %s, route = %s, sid = %s, uid = %d",
+		iActor.App().Logger().Warnf("[Push] sid or uid value error. agentPath = %s, route = %s, sid = %s, uid = %d",
 			agentPath,
 			route,
 			sid,
@@ -88,13 +110,13 @@ func Push(iActor cfacade.IActor, agentPath, sid string, uid cfacade.UID, route s
 	}
 
 	if route == "" {
-		clog.Warnf("[Push] route value error. agentPath = %s, route = %s", agentPath, route)
+		iActor.App().Logger().Warnf("[Push] route value error. agentPath = %s, route = %s", agentPath, route)
 		return
 	}
 
 	data, err := iActor.App().Serializer().Marshal(v)
 	if err != nil {
-		clog.Warnf("[Push] Marshal error. agentPath = %s, route = %s, v = %+v", agentPath, route, v)
+		iActor.App().Logger().Warnf("[Push] Marshal error. agentPath = %s, route = %s, v = %+v", agentPath, route, v)
 		return
 	}
 
@@ -122,18 +144,18 @@ func PushWithUID(iActor cfacade.IActor, agentPath string, uid cfacade.UID, route
 // clients when allUID is true.
 func PushWithUIDS(iActor cfacade.IActor, agentPath string, uidList []int64, allUID bool, route string, v any) {
 	if !allUID && len(uidList) < 1 {
-		clog.Warnf("[PushWithUIDS] uidList value error. agentPath = %s, route = %s", agentPath, route)
+		iActor.App().Logger().Warnf("[PushWithUIDS] uidList value error. agentPath = %s, route = %s", agentPath, route)
 		return
 	}
 
 	if route == "" {
-		clog.Warnf("[PushWithUIDS] route value error. agentPath = %s, route = %s", agentPath, route)
+		iActor.App().Logger().Warnf("[PushWithUIDS] route value error. agentPath = %s, route = %s", agentPath, route)
 		return
 	}
 
 	data, err := iActor.App().Serializer().Marshal(v)
 	if err != nil {
-		clog.Warnf("[PushWithUIDS] Marshal error. agentPath = %s, route = %s, v = %+v", agentPath, route, v)
+		iActor.App().Logger().Warnf("[PushWithUIDS] Marshal error. agentPath = %s, route = %s, v = %+v", agentPath, route, v)
 		return
 	}
 
@@ -156,7 +178,7 @@ func PushWithUIDS(iActor cfacade.IActor, agentPath string, uidList []int64, allU
 func Kick(iActor cfacade.IActor, agentPath, sid string, reason any, closed bool) {
 	data, err := iActor.App().Serializer().Marshal(reason)
 	if err != nil {
-		clog.Warnf("[Kick] Marshal error. agentPath = %s, sid = %s, reason = %+v", agentPath, sid, reason)
+		iActor.App().Logger().Warnf("[Kick] Marshal error. agentPath = %s, sid = %s, reason = %+v", agentPath, sid, reason)
 		return
 	}
 
@@ -173,7 +195,7 @@ func Kick(iActor cfacade.IActor, agentPath, sid string, reason any, closed bool)
 func KickUID(iActor cfacade.IActor, agentPath string, uid cfacade.UID, reason any, closed bool) {
 	data, err := iActor.App().Serializer().Marshal(reason)
 	if err != nil {
-		clog.Warnf("[KickUID] Marshal error. agentPath = %s, uid = %d, reason = %+v", agentPath, uid, reason)
+		iActor.App().Logger().Warnf("[KickUID] Marshal error. agentPath = %s, uid = %d, reason = %+v", agentPath, uid, reason)
 		return
 	}
 
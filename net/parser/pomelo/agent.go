@@ -3,9 +3,12 @@ package pomelo
 import (
 	"fmt"
 	"net"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	ccode "github.com/cherry-game/cherry/code"
+	cerror "github.com/cherry-game/cherry/error"
 	ctime "github.com/cherry-game/cherry/extend/time"
 	cutils "github.com/cherry-game/cherry/extend/utils"
 	cfacade "github.com/cherry-game/cherry/facade"
@@ -38,6 +41,8 @@ type (
 		chKick               chan []byte          // kick channel: write bytes then close within writeChan
 		lastAt               atomic.Int64         // last heartbeat unix time stamp
 		onCloseFunc          []OnCloseFunc        // on close agent
+		requests             sync.Map             // key: mid of a request waiting for its response
+		components                                // components of the parser
 
 	}
 
@@ -72,7 +77,7 @@ func NewAgent(app cfacade.IApplication, conn net.Conn, session *cproto.Session)
 	agent.SetLastAt()
 
 	if clog.PrintLevel(zapcore.DebugLevel) {
-		clog.Debugf("[sid = %s,uid = %d] Agent create. [count = %d, ip = %s]",
+		agent.Logger().Debugf("[sid = %s,uid = %d] Agent create. [count = %d, ip = %s]",
 			agent.SID(),
 			agent.UID(),
 			Count(),
@@ -151,7 +156,7 @@ func (a *Agent) SendRaw(bytes []byte) {
 func (a *Agent) SendPacket(typ pomeloPacket.Type, data []byte) {
 	pkg, err := pomeloPacket.Encode(typ, data)
 	if err != nil {
-		clog.Warnf("[sid = %s,uid = %d] Packet encode error. [error = %v]", a.SID(), a.UID(), err)
+		a.Logger().Warnf("[sid = %s,uid = %d] Packet encode error. [error = %v]", a.SID(), a.UID(), err)
 		return
 	}
 	a.SendRaw(pkg)
@@ -163,20 +168,42 @@ func (a *Agent) Response(session *cproto.Session, v interface{}, isError ...bool
 }
 
 // ResponseCode sends a status-code response for the given session.
+// A failure code carries its registered description as the message.
 func (a *Agent) ResponseCode(session *cproto.Session, statusCode int32, isError ...bool) {
 	rsp := &cproto.Response{Code: statusCode}
+	if ccode.IsFail(statusCode) {
+		rsp.Message = ccode.Desc(statusCode)
+	}
 	a.ResponseMID(session.GetMID(), rsp, isError...)
 }
 
+// ResponseError sends an error response with the code, message and details of err.
+func (a *Agent) ResponseError(session *cproto.Session, err error) {
+	codeErr := cerror.AsCode(err)
+	if codeErr == nil {
+		a.ResponseCode(session, ccode.OK)
+		return
+	}
+
+	rsp := &cproto.Response{
+		Code:    codeErr.Code,
+		Message: codeErr.Message,
+		Details: codeErr.Details,
+	}
+	a.ResponseMID(session.GetMID(), rsp, true)
+}
+
 // ResponseMID sends a response payload for the given message id.
 func (a *Agent) ResponseMID(mid uint32, v interface{}, isError ...bool) {
+	a.requests.Delete(uint(mid))
+
 	isErr := false
 	if len(isError) > 0 {
 		isErr = isError[0]
 	}
 	a.sendPending(pomeloMessage.Response, "", mid, v, isErr)
 	if clog.PrintLevel(zapcore.DebugLevel) {
-		clog.Debugf("[sid = %s,uid = %d] Response ok. [mid = %d, isError = %v]",
+		a.Logger().Debugf("[sid = %s,uid = %d] Response ok. [mid = %d, isError = %v]",
 			a.SID(), a.UID(), mid, isErr)
 	}
 }
@@ -185,7 +212,7 @@ func (a *Agent) ResponseMID(mid uint32, v interface{}, isError ...bool) {
 func (a *Agent) Push(route string, val interface{}) {
 	a.sendPending(pomeloMessage.Push, route, 0, val, false)
 	if clog.PrintLevel(zapcore.DebugLevel) {
-		clog.Debugf("[sid = %s,uid = %d] Push ok. [route = %s]", a.SID(), a.UID(), route)
+		a.Logger().Debugf("[sid = %s,uid = %d] Push ok. [route = %s]", a.SID(), a.UID(), route)
 	}
 }
 
@@ -199,7 +226,7 @@ func (a *Agent) SendKick(pkg []byte) {
 	select {
 	case a.chKick <- pkg:
 	default:
-		clog.Warnf("[sid = %s,uid = %d] Kick buffer full, closing without kick packet.", a.SID(), a.UID())
+		a.Logger().Warnf("[sid = %s,uid = %d] Kick buffer full, closing without kick packet.", a.SID(), a.UID())
 		a.Close()
 	}
 }
@@ -209,7 +236,7 @@ func (a *Agent) SendKick(pkg []byte) {
 func (a *Agent) Kick(reason interface{}, closed bool) {
 	bytes, err := a.Serializer().Marshal(reason)
 	if err != nil {
-		clog.Warnf("[sid = %s,uid = %d] Kick marshal fail. [closed = %v, reason = {%+v}, err = %s]", a.SID(), a.UID(), closed, reason, err)
+		a.Logger().Warnf("[sid = %s,uid = %d] Kick marshal fail. [closed = %v, reason = {%+v}, err = %s]", a.SID(), a.UID(), closed, reason, err)
 		if closed {
 			a.Close()
 		}
@@ -218,7 +245,7 @@ func (a *Agent) Kick(reason interface{}, closed bool) {
 
 	pkg, err := pomeloPacket.Encode(pomeloPacket.Kick, bytes)
 	if err != nil {
-		clog.Warnf("[sid = %s,uid = %d] Kick packet encode error. [closed = %v, reason = %+v, err = %s]",
+		a.Logger().Warnf("[sid = %s,uid = %d] Kick packet encode error. [closed = %v, reason = %+v, err = %s]",
 			a.SID(), a.UID(), closed, reason, err)
 		if closed {
 			a.Close()
@@ -227,7 +254,7 @@ func (a *Agent) Kick(reason interface{}, closed bool) {
 	}
 
 	if clog.PrintLevel(zapcore.DebugLevel) {
-		clog.Debugf("[sid = %s,uid = %d] Kick ok. [closed = %v, reason = %+v]",
+		a.Logger().Debugf("[sid = %s,uid = %d] Kick ok. [closed = %v, reason = %+v]",
 			a.SID(), a.UID(), closed, reason)
 	}
 
@@ -260,7 +287,7 @@ func (a *Agent) Close() {
 func (a *Agent) closeClean() {
 	for _, fn := range a.onCloseFunc {
 		cutils.Try(func() { fn(a) }, func(errString string) {
-			clog.Warnf("[sid = %s,uid = %d] onCloseFunc error = %s",
+			a.Logger().Warnf("[sid = %s,uid = %d] onCloseFunc error = %s",
 				a.SID(), a.UID(), errString)
 		})
 	}
@@ -270,7 +297,7 @@ func (a *Agent) closeClean() {
 
 	closeErr := a.conn.Close()
 	if clog.PrintLevel(zapcore.InfoLevel) {
-		clog.Infof("[sid = %s,uid = %d] Agent closed. [count = %d, ip = %s, error = %v]",
+		a.Logger().Infof("[sid = %s,uid = %d] Agent closed. [count = %d, ip = %s, error = %v]",
 			a.SID(), a.UID(), Count(), a.RemoteAddr(), closeErr)
 	}
 }
@@ -279,7 +306,7 @@ func (a *Agent) closeClean() {
 // Must be called while the agent is in AgentInit state.
 func (a *Agent) AddOnClose(fn OnCloseFunc) {
 	if a.state.Load() != AgentInit {
-		clog.Warnf("[sid = %s,uid = %d] AddOnClose failed: agent is not in Init state. [state = %d]",
+		a.Logger().Warnf("[sid = %s,uid = %d] AddOnClose failed: agent is not in Init state. [state = %d]",
 			a.SID(), a.UID(), a.State())
 		return
 	}
@@ -312,7 +339,7 @@ func (a *Agent) readChan() {
 		packets, isBreak, err := pomeloPacket.Read(a.conn)
 		if isBreak || err != nil {
 			if clog.PrintLevel(zapcore.InfoLevel) {
-				clog.Infof("[sid = %s,uid = %d] Agent read chan exit. [isBreak = %v, error = %v]",
+				a.Logger().Infof("[sid = %s,uid = %d] Agent read chan exit. [isBreak = %v, error = %v]",
 					a.SID(), a.UID(), isBreak, err)
 			}
 			return
@@ -347,7 +374,7 @@ func (a *Agent) writeChan() {
 		a.Close()
 		a.closeClean()
 		if clog.PrintLevel(zapcore.DebugLevel) {
-			clog.Debugf("[sid = %s,uid = %d] Agent write chan exit.", a.SID(), a.UID())
+			a.Logger().Debugf("[sid = %s,uid = %d] Agent write chan exit.", a.SID(), a.UID())
 		}
 	}()
 
@@ -359,7 +386,7 @@ func (a *Agent) writeChan() {
 			return
 		case bytes := <-a.chKick:
 			if err := a.write(bytes); err != nil {
-				clog.Warnf("[sid = %s,uid = %d] Kick write error. [error = %v]", a.SID(), a.UID(), err)
+				a.Logger().Warnf("[sid = %s,uid = %d] Kick write error. [error = %v]", a.SID(), a.UID(), err)
 			}
 			return
 		case <-ticker.C:
@@ -367,7 +394,7 @@ func (a *Agent) writeChan() {
 			deadline = time.Now().Add(-cmd.heartbeatTime).Unix()
 			if lastAt < deadline {
 				if clog.PrintLevel(zapcore.DebugLevel) {
-					clog.Debugf("[sid = %s,uid = %d] Check heartbeat timeout.", a.SID(), a.UID())
+					a.Logger().Debugf("[sid = %s,uid = %d] Check heartbeat timeout.", a.SID(), a.UID())
 				}
 				return
 			}
@@ -375,7 +402,7 @@ func (a *Agent) writeChan() {
 			a.processPending(pending)
 		case bytes := <-a.chWrite:
 			if err := a.write(bytes); err != nil {
-				clog.Warnf("[sid = %s,uid = %d] Write bytes error. [error = %v]", a.SID(), a.UID(), err)
+				a.Logger().Warnf("[sid = %s,uid = %d] Write bytes error. [error = %v]", a.SID(), a.UID(), err)
 				return
 			}
 		}
@@ -387,10 +414,14 @@ func (a *Agent) writeChan() {
 func (a *Agent) write(bytes []byte) error {
 	if a.IsClosed() {
 		if clog.PrintLevel(zapcore.InfoLevel) {
-			clog.Infof("[sid = %s,uid = %d] Write bytes failed because the connection is closed!", a.SID(), a.UID())
+			a.Logger().Infof("[sid = %s,uid = %d] Write bytes failed because the connection is closed!", a.SID(), a.UID())
 		}
 		return nil
 	}
+	if len(bytes) > 0 {
+		a.packetsOut.Inc(pomeloPacket.TypeName(bytes[0]))
+	}
+
 	_, err := a.conn.Write(bytes)
 	return err
 }
@@ -401,11 +432,12 @@ func (a *Agent) processPacket(packet *pomeloPacket.Packet) {
 	process, found := cmd.onPacketFuncMap[packet.Type()]
 	if !found {
 		if clog.PrintLevel(zapcore.DebugLevel) {
-			clog.Warnf("[sid = %s,uid = %d] Packet type not found, close connect! [packet = %+v]", a.SID(), a.UID(), packet)
+			a.Logger().Warnf("[sid = %s,uid = %d] Packet type not found, close connect! [packet = %+v]", a.SID(), a.UID(), packet)
 		}
 		a.Close()
 		return
 	}
+	a.packetsIn.Inc(pomeloPacket.TypeName(packet.Type()))
 	process(a, packet)
 	a.SetLastAt()
 }
@@ -415,7 +447,7 @@ func (a *Agent) processPacket(packet *pomeloPacket.Packet) {
 func (a *Agent) processPending(data *pendingMessage) {
 	payload, err := a.Serializer().Marshal(data.payload)
 	if err != nil {
-		clog.Warnf("[sid = %s,uid = %d] Payload marshal error. [data = %s]",
+		a.Logger().Warnf("[sid = %s,uid = %d] Payload marshal error. [data = %s]",
 			a.SID(), a.UID(), data.String())
 		return
 	}
@@ -430,7 +462,7 @@ func (a *Agent) processPending(data *pendingMessage) {
 
 	em, err := pomeloMessage.Encode(m)
 	if err != nil {
-		clog.Warnf("[sid = %s,uid = %d] Message encode error. [error = %v]", a.SID(), a.UID(), err)
+		a.Logger().Warnf("[sid = %s,uid = %d] Message encode error. [error = %v]", a.SID(), a.UID(), err)
 		return
 	}
 
@@ -441,7 +473,7 @@ func (a *Agent) processPending(data *pendingMessage) {
 // Drops the message if the agent is closed or the buffer is full.
 func (a *Agent) sendPending(typ pomeloMessage.Type, route string, mid uint32, v interface{}, isError bool) {
 	if a.IsClosed() {
-		clog.Warnf("[sid = %s,uid = %d] Session is closed. [typ = %v, route = %s, mid = %d, val = %+v, err = %v]",
+		a.Logger().Warnf("[sid = %s,uid = %d] Session is closed. [typ = %v, route = %s, mid = %d, val = %+v, err = %v]",
 			a.SID(), a.UID(), typ, route, mid, v, isError)
 		return
 	}
@@ -457,11 +489,26 @@ func (a *Agent) sendPending(typ pomeloMessage.Type, route string, mid uint32, v
 	select {
 	case a.chPending <- pending:
 	default:
-		clog.Warnf("[sid = %s,uid = %d] send buffer exceed. [typ = %v, route = %s, mid = %d, val = %+v, err = %v]",
+		a.Logger().Warnf("[sid = %s,uid = %d] send buffer exceed. [typ = %v, route = %s, mid = %d, val = %+v, err = %v]",
 			a.SID(), a.UID(), typ, route, mid, v, isError)
 	}
 }
 
+// Idle reports whether every request of the client was answered and nothing
+// is waiting to be written, so the agent can be closed without losing a response.
+func (a *Agent) Idle() bool {
+	if len(a.chPending) > 0 || len(a.chWrite) > 0 {
+		return false
+	}
+
+	idle := true
+	a.requests.Range(func(_, _ any) bool {
+		idle = false
+		return false
+	})
+	return idle
+}
+
 // RemoteAddr returns the client IP address.
 func (a *Agent) RemoteAddr() string {
 	if a.session != nil {
@@ -1,6 +1,7 @@
 package pomelo
 
 import (
+	"strings"
 	"sync"
 
 	cerr "github.com/cherry-game/cherry/error"
@@ -137,3 +138,33 @@ func Count() int {
 	})
 	return count
 }
+
+// ForeachNodeAgent iterates over the agents connected to the node nodeID.
+// Several applications may run in one process, each with its own agents.
+func ForeachNodeAgent(nodeID string, fn func(a *Agent)) {
+	prefix := nodeID + "."
+	ForeachAgent(func(agent *Agent) {
+		if strings.HasPrefix(agent.session.AgentPath, prefix) {
+			fn(agent)
+		}
+	})
+}
+
+// CountNode returns the number of agents connected to the node nodeID.
+func CountNode(nodeID string) int {
+	count := 0
+	ForeachNodeAgent(nodeID, func(_ *Agent) {
+		count += 1
+	})
+	return count
+}
+
+// BindCount returns the number of agents bound to a uid.
+func BindCount() int {
+	count := 0
+	uidMap.Range(func(key, value any) bool {
+		count += 1
+		return true
+	})
+	return count
+}
@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	cfacade "github.com/cherry-game/cherry/facade"
+	clog "github.com/cherry-game/cherry/logger"
 	cproto "github.com/cherry-game/cherry/net/proto"
+	"github.com/nats-io/nuid"
 )
 
 func newTestAgent(sid string) *Agent {
@@ -149,3 +151,57 @@ func TestCountAndForeach(t *testing.T) {
 		t.Fatalf("ForeachAgent saw %d, expected at least %d", seen, before+3)
 	}
 }
+
+func TestForeachNodeAgent(t *testing.T) {
+	for _, agentPath := range []string{"node-1.agent", "node-1.agent", "node-10.agent"} {
+		agent := newTestAgent(nuid.Next())
+		agent.session.AgentPath = agentPath
+		BindSID(agent)
+	}
+
+	if n := CountNode("node-1"); n != 2 {
+		t.Fatalf("expected 2 agents of node-1, got %d", n)
+	}
+
+	ForeachNodeAgent("node-10", func(a *Agent) {
+		if a.session.AgentPath != "node-10.agent" {
+			t.Fatalf("unexpected agent of %s", a.session.AgentPath)
+		}
+	})
+}
+
+// testApp implements the parts of cfacade.IApplication used by the agent.
+type testApp struct {
+	cfacade.IApplication
+}
+
+func (a *testApp) Logger() cfacade.ILogger { return clog.DefaultLogger }
+
+// TestAgent_Idle verifies that an agent is busy while a request waits for its
+// response or a message waits to be written.
+func TestAgent_Idle(t *testing.T) {
+	agent := newTestAgent("sid-idle")
+	agent.IApplication = &testApp{}
+	agent.chPending = make(chan *pendingMessage, 1)
+	agent.chWrite = make(chan []byte, 1)
+	agent.state.Store(AgentWorking)
+
+	if !agent.Idle() {
+		t.Fatal("a new agent should be idle")
+	}
+
+	agent.requests.Store(uint(7), struct{}{})
+	if agent.Idle() {
+		t.Fatal("an agent with a pending request should be busy")
+	}
+
+	agent.ResponseMID(7, &cproto.I32{})
+	if agent.Idle() {
+		t.Fatal("an agent with a queued response should be busy")
+	}
+
+	<-agent.chPending
+	if !agent.Idle() {
+		t.Fatal("an answered agent should be idle")
+	}
+}
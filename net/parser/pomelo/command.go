@@ -3,8 +3,11 @@ package pomelo
 import (
 	"time"
 
+	ccode "github.com/cherry-game/cherry/code"
 	cfacade "github.com/cherry-game/cherry/facade"
 	clog "github.com/cherry-game/cherry/logger"
+	cadmin "github.com/cherry-game/cherry/net/admin"
+	cmetrics "github.com/cherry-game/cherry/net/metrics"
 	pmessage "github.com/cherry-game/cherry/net/parser/pomelo/message"
 	ppacket "github.com/cherry-game/cherry/net/parser/pomelo/packet"
 	jsoniter "github.com/json-iterator/go"
@@ -60,6 +63,23 @@ func (p *Command) init(app cfacade.IApplication) {
 
 	p.setOnPacketFunc()
 
+	p.initMetrics(cmetrics.Find(app))
+
+	cadmin.Find(app).Handle("agents", dumpAgents)
+}
+
+func dumpAgents() any {
+	return map[string]int{
+		"agents":   Count(),
+		"sessions": BindCount(),
+	}
+}
+
+func (p *Command) initMetrics(registry *cmetrics.Registry) {
+	registry.GaugeFunc("cherry_agent_online", "Connected agents.", nil,
+		func(set func(value float64, labelValues ...string)) {
+			set(float64(Count()))
+		})
 }
 
 func (p *Command) setData(name string, value interface{}) {
@@ -122,7 +142,7 @@ func handshakeCommand(agent *Agent, _ *ppacket.Packet) {
 	agent.SendRaw(cmd.handshakeBytes)
 
 	if clog.PrintLevel(zapcore.DebugLevel) {
-		clog.Debugf("[sid = %s,uid = %d] Request handshake. [address = %s]",
+		agent.Logger().Debugf("[sid = %s,uid = %d] Request handshake. [address = %s]",
 			agent.SID(),
 			agent.UID(),
 			agent.RemoteAddr(),
@@ -136,7 +156,7 @@ func handshakeACKCommand(agent *Agent, _ *ppacket.Packet) {
 	agent.SetState(AgentWorking)
 
 	if clog.PrintLevel(zapcore.DebugLevel) {
-		clog.Debugf("[sid = %s,uid = %d] request handshakeACK. [address = %s]",
+		agent.Logger().Debugf("[sid = %s,uid = %d] request handshakeACK. [address = %s]",
 			agent.SID(),
 			agent.UID(),
 			agent.RemoteAddr(),
@@ -155,7 +175,7 @@ func heartbeatCommand(agent *Agent, _ *ppacket.Packet) {
 func dataCommand(agent *Agent, pkg *ppacket.Packet) {
 	if agent.State() != AgentWorking {
 		if clog.PrintLevel(zapcore.DebugLevel) {
-			clog.Warnf("[sid = %s,uid = %d] Data State is not working. [state = %d]",
+			agent.Logger().Warnf("[sid = %s,uid = %d] Data State is not working. [state = %d]",
 				agent.SID(),
 				agent.UID(),
 				agent.State(),
@@ -167,7 +187,7 @@ func dataCommand(agent *Agent, pkg *ppacket.Packet) {
 	msg, err := pmessage.Decode(pkg.Data())
 	if err != nil {
 		if clog.PrintLevel(zapcore.DebugLevel) {
-			clog.Warnf("[sid = %s,uid = %d] Data message decode error. [data = %s, error = %s]",
+			agent.Logger().Warnf("[sid = %s,uid = %d] Data message decode error. [data = %s, error = %s]",
 				agent.SID(),
 				agent.UID(),
 				pkg.Data(),
@@ -180,7 +200,7 @@ func dataCommand(agent *Agent, pkg *ppacket.Packet) {
 	route, err := pmessage.DecodeRoute(msg.Route)
 	if err != nil {
 		if clog.PrintLevel(zapcore.DebugLevel) {
-			clog.Warnf("[sid = %s,uid = %d] Data Message decode route error. [data = %s, error = %s]",
+			agent.Logger().Warnf("[sid = %s,uid = %d] Data Message decode route error. [data = %s, error = %s]",
 				agent.SID(),
 				agent.UID(),
 				pkg.Data(),
@@ -190,5 +210,19 @@ func dataCommand(agent *Agent, pkg *ppacket.Packet) {
 		return
 	}
 
+	span := agent.tracer.Start("", "", "agent")
+	if span != nil {
+		span.SetAttr("sid", agent.SID())
+		span.SetAttr("route", msg.Route)
+		agent.session.TraceId, agent.session.SpanId = span.TraceID, span.SpanID
+	} else {
+		agent.session.TraceId, agent.session.SpanId = "", ""
+	}
+
+	if msg.Type == pmessage.Request {
+		agent.requests.Store(msg.ID, struct{}{})
+	}
+
 	cmd.onDataRouteFunc(agent, route, &msg)
+	span.Finish(ccode.OK)
 }
@@ -1,16 +1,49 @@
 package pomelo
 
 import (
+	"strconv"
+
 	cfacade "github.com/cherry-game/cherry/facade"
-	clog "github.com/cherry-game/cherry/logger"
+	cbalancer "github.com/cherry-game/cherry/net/balancer"
+	cmetrics "github.com/cherry-game/cherry/net/metrics"
 	pmessage "github.com/cherry-game/cherry/net/parser/pomelo/message"
 	cproto "github.com/cherry-game/cherry/net/proto"
+	csharding "github.com/cherry-game/cherry/net/sharding"
+	ctrace "github.com/cherry-game/cherry/net/trace"
 )
 
+// components are the optional components of the app a parser runs in, shared
+// by the agents of the parser. A nil component disables its feature.
+type components struct {
+	tracer     *ctrace.Tracer       // starts a trace per data message
+	sharding   *csharding.Component // routes the messages of sharded entities
+	balancer   *cbalancer.Component // chooses the member receiving forwarded messages, nil picks a random one
+	packetsIn  *cmetrics.Counter    // received packets by type
+	packetsOut *cmetrics.Counter    // sent packets by type
+}
+
+func findComponents(app cfacade.IApplication) components {
+	registry := cmetrics.Find(app)
+
+	return components{
+		tracer:     ctrace.Find(app),
+		sharding:   csharding.Find(app),
+		balancer:   cbalancer.Find(app),
+		packetsIn:  registry.Counter("cherry_agent_packets_in_total", "Packets received from clients by type.", "type"),
+		packetsOut: registry.Counter("cherry_agent_packets_out_total", "Packets sent to clients by type.", "type"),
+	}
+}
+
 // DefaultDataRoute default message route handler
 func DefaultDataRoute(agent *Agent, route *pmessage.Route, msg *pmessage.Message) {
 	session := BuildSession(agent, msg)
 
+	// sharded entity, the uid is the entity id
+	if region, found := agent.sharding.Region(route.HandleName()); found && region.NodeType() == route.NodeType() {
+		ShardDataRoute(agent, session, route, msg)
+		return
+	}
+
 	// current node
 	if agent.NodeType() == route.NodeType() {
 		targetPath := cfacade.NewChildPath(agent.NodeID(), route.HandleName(), session.Sid)
@@ -19,7 +52,7 @@ func DefaultDataRoute(agent *Agent, route *pmessage.Route, msg *pmessage.Message
 	}
 
 	if !session.IsBind() {
-		clog.Warnf("[sid = %s,uid = %d] Session is not bind with UID. failed to forward message.[route = %s]",
+		agent.Logger().Warnf("[sid = %s,uid = %d] Session is not bind with UID. failed to forward message.[route = %s]",
 			agent.SID(),
 			agent.UID(),
 			msg.Route,
@@ -27,7 +60,11 @@ func DefaultDataRoute(agent *Agent, route *pmessage.Route, msg *pmessage.Message
 		return
 	}
 
-	member, found := agent.Discovery().Random(route.NodeType())
+	member, found := agent.balancer.Select(route.NodeType(), session.BalanceKey())
+	if !found {
+		member, found = agent.Discovery().Random(route.NodeType())
+	}
+
 	if !found {
 		return
 	}
@@ -35,7 +72,47 @@ func DefaultDataRoute(agent *Agent, route *pmessage.Route, msg *pmessage.Message
 	targetPath := cfacade.NewPath(member.GetNodeID(), route.HandleName())
 	err := ClusterLocalDataRoute(agent, session, route, msg, member.GetNodeID(), targetPath)
 	if err != nil {
-		clog.Warnf("[sid = %s,uid = %d,route = %s] cluster local data error. err = %v",
+		agent.Logger().Warnf("[sid = %s,uid = %d,route = %s] cluster local data error. err = %v",
+			agent.SID(),
+			agent.UID(),
+			msg.Route,
+			err,
+		)
+	}
+}
+
+// ShardDataRoute routes the message to the entity of the session uid on the
+// node owning its shard, the route handler is the region name.
+func ShardDataRoute(agent *Agent, session *cproto.Session, route *pmessage.Route, msg *pmessage.Message) {
+	region, found := agent.sharding.Region(route.HandleName())
+	if !found {
+		return
+	}
+
+	if !session.IsBind() {
+		agent.Logger().Warnf("[sid = %s,uid = %d] Session is not bind with UID. failed to route sharded message.[route = %s]",
+			agent.SID(),
+			agent.UID(),
+			msg.Route,
+		)
+		return
+	}
+
+	entityID := strconv.FormatInt(session.Uid, 10)
+	nodeID, found := region.Owner(entityID)
+	if !found {
+		agent.Logger().Warnf("[sid = %s,uid = %d,route = %s] shard owner not found.", agent.SID(), agent.UID(), msg.Route)
+		return
+	}
+
+	targetPath := cfacade.NewChildPath(nodeID, region.Name(), entityID)
+	if nodeID == agent.NodeID() {
+		LocalDataRoute(agent, session, route, msg, targetPath)
+		return
+	}
+
+	if err := ClusterLocalDataRoute(agent, session, route, msg, nodeID, targetPath); err != nil {
+		agent.Logger().Warnf("[sid = %s,uid = %d,route = %s] cluster local data error. err = %v",
 			agent.SID(),
 			agent.UID(),
 			msg.Route,
@@ -50,6 +127,8 @@ func LocalDataRoute(agent *Agent, session *cproto.Session, route *pmessage.Route
 	message.Target = targetPath
 	message.FuncName = route.Method()
 	message.Session = session
+	message.TraceID = session.TraceId
+	message.SpanID = session.SpanId
 	message.Args = msg.Data
 
 	agent.ActorSystem().PostLocal(message)
@@ -61,6 +140,8 @@ func ClusterLocalDataRoute(agent *Agent, session *cproto.Session, route *pmessag
 	message.Target = targetPath
 	message.FuncName = route.Method()
 	message.Session = session
+	message.TraceID = session.TraceId
+	message.SpanID = session.SpanId
 	message.Args = msg.Data
 
 	return agent.Cluster().PublishLocal(nodeID, message)
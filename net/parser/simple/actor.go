@@ -1,6 +1,7 @@
 package simple
 
 import (
+	"context"
 	"encoding/binary"
 	"net"
 	"time"
@@ -9,6 +10,8 @@ import (
 	cfacade "github.com/cherry-game/cherry/facade"
 	clog "github.com/cherry-game/cherry/logger"
 	cactor "github.com/cherry-game/cherry/net/actor"
+	cadmin "github.com/cherry-game/cherry/net/admin"
+	cmetrics "github.com/cherry-game/cherry/net/metrics"
 	cproto "github.com/cherry-game/cherry/net/proto"
 	"github.com/nats-io/nuid"
 	"go.uber.org/zap/zapcore"
@@ -20,6 +23,7 @@ type (
 		agentActorID   string
 		connectors     []cfacade.IConnector
 		onNewAgentFunc OnNewAgentFunc
+		components     // components of the app, passed to the agents
 	}
 
 	OnNewAgentFunc func(newAgent *Agent)
@@ -39,6 +43,45 @@ func NewActor(agentActorID string) *actor {
 	return parser
 }
 
+// SessionCount returns the number of clients connected to this node.
+func (p *actor) SessionCount() int {
+	return CountNode(p.App().NodeID())
+}
+
+// OnDrain closes the idle clients connected to this node right away, and the
+// others once they are idle. The clients still busy when ctx is done are
+// closed too.
+func (p *actor) OnDrain(ctx context.Context) {
+	nodeID := p.App().NodeID()
+	p.App().Logger().Infof("[agentActorID = %s] drain. close idle agents. [count = %d]", p.agentActorID, CountNode(nodeID))
+
+	closeAgents := func(all bool) {
+		ForeachNodeAgent(nodeID, func(agent *Agent) {
+			if all || agent.Idle() {
+				agent.Close()
+			}
+		})
+	}
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		closeAgents(false)
+		if CountNode(nodeID) < 1 {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			p.App().Logger().Warnf("[agentActorID = %s] drain timeout. close busy agents. [remaining agents = %d]", p.agentActorID, CountNode(nodeID))
+			closeAgents(true)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // OnInit Actor初始化前触发该函数
 func (p *actor) OnInit() {
 	p.Remote().Register(ResponseFuncName, p.response)
@@ -50,9 +93,13 @@ func (p *actor) Load(app cfacade.IApplication) {
 		panic("Connectors is nil. Please call the AddConnector(...) method add IConnector.")
 	}
 
+	p.components = findComponents(app)
+	initMetrics(cmetrics.Find(app))
+	cadmin.Find(app).Handle("agents", dumpAgents)
+
 	//  Create agent actor
 	if _, err := app.ActorSystem().CreateActor(p.agentActorID, p); err != nil {
-		clog.Panicf("Create agent actor fail. err = %+v", err)
+		p.App().Logger().Panicf("Create agent actor fail. err = %+v", err)
 	}
 
 	for _, connector := range p.connectors {
@@ -61,6 +108,20 @@ func (p *actor) Load(app cfacade.IApplication) {
 	}
 }
 
+func dumpAgents() any {
+	return map[string]int{
+		"agents":   Count(),
+		"sessions": BindCount(),
+	}
+}
+
+func initMetrics(registry *cmetrics.Registry) {
+	registry.GaugeFunc("cherry_agent_online", "Connected agents.", nil,
+		func(set func(value float64, labelValues ...string)) {
+			set(float64(Count()))
+		})
+}
+
 // AddConnector registers a connector that will be started when the parser loads.
 func (p *actor) AddConnector(connector cfacade.IConnector) {
 	p.connectors = append(p.connectors, connector)
@@ -86,6 +147,7 @@ func (p *actor) defaultOnConnectFunc(conn net.Conn) {
 	}
 
 	agent := NewAgent(p.App(), conn, session)
+	agent.components = p.components
 
 	if p.onNewAgentFunc != nil {
 		p.onNewAgentFunc(agent)
@@ -126,7 +188,7 @@ func (p *actor) response(rsp *cproto.PomeloResponse) {
 	agent, found := GetAgentWithSID(rsp.Sid)
 	if !found {
 		if clog.PrintLevel(zapcore.DebugLevel) {
-			clog.Debugf("[response] Not found agent. [rsp = %+v]", rsp)
+			p.App().Logger().Debugf("[response] Not found agent. [rsp = %+v]", rsp)
 		}
 		return
 	}
@@ -2,7 +2,6 @@ package simple
 
 import (
 	cfacade "github.com/cherry-game/cherry/facade"
-	clog "github.com/cherry-game/cherry/logger"
 	cactor "github.com/cherry-game/cherry/net/actor"
 	cproto "github.com/cherry-game/cherry/net/proto"
 )
@@ -21,7 +20,7 @@ func (p *ActorBase) Response(session *cproto.Session, mid uint32, v interface{})
 func Response(iActor cfacade.IActor, session *cproto.Session, mid uint32, v interface{}) {
 	data, err := iActor.App().Serializer().Marshal(v)
 	if err != nil {
-		clog.Warnf("[Response] Marshal error. v = %+v", v)
+		iActor.App().Logger().Warnf("[Response] Marshal error. v = %+v", v)
 		return
 	}
 
@@ -6,6 +6,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	ccode "github.com/cherry-game/cherry/code"
 	cnet "github.com/cherry-game/cherry/extend/net"
 	ctime "github.com/cherry-game/cherry/extend/time"
 	cutils "github.com/cherry-game/cherry/extend/utils"
@@ -35,6 +36,7 @@ type (
 		chKick      chan []byte
 		lastAt      atomic.Int64
 		onCloseFunc []OnCloseFunc
+		components  // components of the parser
 	}
 
 	pendingMessage struct {
@@ -61,7 +63,7 @@ func NewAgent(app cfacade.IApplication, conn net.Conn, session *cproto.Session)
 	agent.SetLastAt()
 
 	if clog.PrintLevel(zapcore.DebugLevel) {
-		clog.Debugf("[sid = %s,uid = %d] Agent create. [count = %d, ip = %s]",
+		agent.Logger().Debugf("[sid = %s,uid = %d] Agent create. [count = %d, ip = %s]",
 			agent.SID(), agent.UID(), Count(), agent.RemoteAddr())
 	}
 
@@ -101,6 +103,17 @@ func (a *Agent) Unbind() {
 	Unbind(a.SID())
 }
 
+// Idle reports whether the client sent no message for more than a second and
+// nothing is waiting to be written. The protocol has no request ids, so the
+// requests still running on the actors are not known.
+func (a *Agent) Idle() bool {
+	if len(a.chPending) > 0 || len(a.chWrite) > 0 {
+		return false
+	}
+
+	return ctime.Now().ToSecond()-a.lastAt.Load() > 1
+}
+
 func (a *Agent) SetLastAt() {
 	a.lastAt.Store(ctime.Now().ToSecond())
 }
@@ -118,7 +131,7 @@ func (a *Agent) SendRaw(bytes []byte) {
 func (a *Agent) Response(mid uint32, v interface{}) {
 	a.sendPending(mid, v)
 	if clog.PrintLevel(zapcore.DebugLevel) {
-		clog.Debugf("[sid = %s,uid = %d] Response ok. [mid = %d, val = %+v]",
+		a.Logger().Debugf("[sid = %s,uid = %d] Response ok. [mid = %d, val = %+v]",
 			a.SID(), a.UID(), mid, v)
 	}
 }
@@ -130,7 +143,7 @@ func (a *Agent) SendKick(pkg []byte) {
 	select {
 	case a.chKick <- pkg:
 	default:
-		clog.Warnf("[sid = %s,uid = %d] Kick buffer full, closing without kick packet.", a.SID(), a.UID())
+		a.Logger().Warnf("[sid = %s,uid = %d] Kick buffer full, closing without kick packet.", a.SID(), a.UID())
 		a.Close()
 	}
 }
@@ -138,7 +151,7 @@ func (a *Agent) SendKick(pkg []byte) {
 func (a *Agent) Kick(mid uint32, reason interface{}, closed bool) {
 	bytes, err := a.Serializer().Marshal(reason)
 	if err != nil {
-		clog.Warnf("[sid = %s,uid = %d] Kick marshal fail. [closed = %v, reason = {%+v}, err = %s]",
+		a.Logger().Warnf("[sid = %s,uid = %d] Kick marshal fail. [closed = %v, reason = {%+v}, err = %s]",
 			a.SID(), a.UID(), closed, reason, err)
 		if closed {
 			a.Close()
@@ -148,7 +161,7 @@ func (a *Agent) Kick(mid uint32, reason interface{}, closed bool) {
 
 	pkg, err := pack(mid, bytes)
 	if err != nil {
-		clog.Warnf("[sid = %s,uid = %d] Kick packet encode error. [closed = %v, reason = %+v, err = %s]",
+		a.Logger().Warnf("[sid = %s,uid = %d] Kick packet encode error. [closed = %v, reason = %+v, err = %s]",
 			a.SID(), a.UID(), closed, reason, err)
 		if closed {
 			a.Close()
@@ -157,7 +170,7 @@ func (a *Agent) Kick(mid uint32, reason interface{}, closed bool) {
 	}
 
 	if clog.PrintLevel(zapcore.DebugLevel) {
-		clog.Debugf("[sid = %s,uid = %d] Kick ok. [closed = %v, reason = %+v]",
+		a.Logger().Debugf("[sid = %s,uid = %d] Kick ok. [closed = %v, reason = %+v]",
 			a.SID(), a.UID(), closed, reason)
 	}
 
@@ -182,7 +195,7 @@ func (a *Agent) Close() {
 func (a *Agent) closeClean() {
 	for _, fn := range a.onCloseFunc {
 		cutils.Try(func() { fn(a) }, func(errString string) {
-			clog.Warnf("[sid = %s,uid = %d] onCloseFunc error = %s",
+			a.Logger().Warnf("[sid = %s,uid = %d] onCloseFunc error = %s",
 				a.SID(), a.UID(), errString)
 		})
 	}
@@ -190,19 +203,19 @@ func (a *Agent) closeClean() {
 	a.Unbind()
 
 	if err := a.conn.Close(); err != nil {
-		clog.Debugf("[sid = %s,uid = %d] Agent connect closed. [error = %s]",
+		a.Logger().Debugf("[sid = %s,uid = %d] Agent connect closed. [error = %s]",
 			a.SID(), a.UID(), err)
 	}
 
 	if clog.PrintLevel(zapcore.DebugLevel) {
-		clog.Debugf("[sid = %s,uid = %d] Agent closed. [count = %d, ip = %s]",
+		a.Logger().Debugf("[sid = %s,uid = %d] Agent closed. [count = %d, ip = %s]",
 			a.SID(), a.UID(), Count(), a.RemoteAddr())
 	}
 }
 
 func (a *Agent) AddOnClose(fn OnCloseFunc) {
 	if a.state.Load() != AgentInit {
-		clog.Warnf("[sid = %s,uid = %d] AddOnClose failed: agent is not in Init state. [state = %d]",
+		a.Logger().Warnf("[sid = %s,uid = %d] AddOnClose failed: agent is not in Init state. [state = %d]",
 			a.SID(), a.UID(), a.State())
 		return
 	}
@@ -219,7 +232,7 @@ func (a *Agent) Run() {
 func (a *Agent) readChan() {
 	defer func() {
 		if clog.PrintLevel(zapcore.DebugLevel) {
-			clog.Debugf("[sid = %s,uid = %d] Agent read chan exit.", a.SID(), a.UID())
+			a.Logger().Debugf("[sid = %s,uid = %d] Agent read chan exit.", a.SID(), a.UID())
 		}
 		a.Close()
 	}()
@@ -244,7 +257,7 @@ func (a *Agent) writeChan() {
 		a.Close()
 		a.closeClean()
 		if clog.PrintLevel(zapcore.DebugLevel) {
-			clog.Debugf("[sid = %s,uid = %d] Agent write chan exit.", a.SID(), a.UID())
+			a.Logger().Debugf("[sid = %s,uid = %d] Agent write chan exit.", a.SID(), a.UID())
 		}
 	}()
 
@@ -256,7 +269,7 @@ func (a *Agent) writeChan() {
 			return
 		case bytes := <-a.chKick:
 			if err := a.write(bytes); err != nil {
-				clog.Warnf("[sid = %s,uid = %d] Kick write error. [error = %v]", a.SID(), a.UID(), err)
+				a.Logger().Warnf("[sid = %s,uid = %d] Kick write error. [error = %v]", a.SID(), a.UID(), err)
 			}
 			return
 		case <-ticker.C:
@@ -264,7 +277,7 @@ func (a *Agent) writeChan() {
 			deadline = time.Now().Add(-heartbeatTime).Unix()
 			if lastAt < deadline {
 				if clog.PrintLevel(zapcore.DebugLevel) {
-					clog.Debugf("[sid = %s,uid = %d] Check heartbeat timeout.", a.SID(), a.UID())
+					a.Logger().Debugf("[sid = %s,uid = %d] Check heartbeat timeout.", a.SID(), a.UID())
 				}
 				return
 			}
@@ -272,7 +285,7 @@ func (a *Agent) writeChan() {
 			a.processPending(pending)
 		case bytes := <-a.chWrite:
 			if err := a.write(bytes); err != nil {
-				clog.Warnf("[sid = %s,uid = %d] Write bytes error. [error = %v]", a.SID(), a.UID(), err)
+				a.Logger().Warnf("[sid = %s,uid = %d] Write bytes error. [error = %v]", a.SID(), a.UID(), err)
 				return
 			}
 		}
@@ -282,39 +295,53 @@ func (a *Agent) writeChan() {
 func (a *Agent) write(bytes []byte) error {
 	if a.IsClosed() {
 		if clog.PrintLevel(zapcore.InfoLevel) {
-			clog.Infof("[sid = %s,uid = %d] Write bytes failed because the connection is closed!", a.SID(), a.UID())
+			a.Logger().Infof("[sid = %s,uid = %d] Write bytes failed because the connection is closed!", a.SID(), a.UID())
 		}
 		return nil
 	}
+	a.packetsOut.Inc(dataType)
 	_, err := a.conn.Write(bytes)
 	return err
 }
 
 func (a *Agent) processPacket(msg *Message) {
+	a.packetsIn.Inc(dataType)
+
 	nodeRoute, found := GetNodeRoute(msg.MID)
 	if !found {
 		if clog.PrintLevel(zapcore.DebugLevel) {
-			clog.Warnf("[sid = %s,uid = %d] Route not found, close connect! [message = %+v]",
+			a.Logger().Warnf("[sid = %s,uid = %d] Route not found, close connect! [message = %+v]",
 				a.SID(), a.UID(), msg)
 		}
 		a.Close()
 		return
 	}
+
+	span := a.tracer.Start("", "", "agent")
+	if span != nil {
+		span.SetAttr("sid", a.SID())
+		span.SetAttr("route", nodeRoute.ActorID+"."+nodeRoute.FuncName)
+		a.session.TraceId, a.session.SpanId = span.TraceID, span.SpanID
+	} else {
+		a.session.TraceId, a.session.SpanId = "", ""
+	}
+
 	onDataRouteFunc(a, msg, nodeRoute)
+	span.Finish(ccode.OK)
 	a.SetLastAt()
 }
 
 func (a *Agent) processPending(pending *pendingMessage) {
 	data, err := a.Serializer().Marshal(pending.payload)
 	if err != nil {
-		clog.Warnf("[sid = %s,uid = %d] Payload marshal error. [data = %s]",
+		a.Logger().Warnf("[sid = %s,uid = %d] Payload marshal error. [data = %s]",
 			a.SID(), a.UID(), pending.String())
 		return
 	}
 
 	pkg, err := pack(pending.mid, data)
 	if err != nil {
-		clog.Warnf("[sid = %s,uid = %d] Pack error. [error = %v]", a.SID(), a.UID(), err)
+		a.Logger().Warnf("[sid = %s,uid = %d] Pack error. [error = %v]", a.SID(), a.UID(), err)
 		return
 	}
 
@@ -323,7 +350,7 @@ func (a *Agent) processPending(pending *pendingMessage) {
 
 func (a *Agent) sendPending(mid uint32, payload interface{}) {
 	if a.IsClosed() {
-		clog.Warnf("[sid = %s,uid = %d] Session is closed. [mid = %d, payload = %+v]",
+		a.Logger().Warnf("[sid = %s,uid = %d] Session is closed. [mid = %d, payload = %+v]",
 			a.SID(), a.UID(), mid, payload)
 		return
 	}
@@ -336,7 +363,7 @@ func (a *Agent) sendPending(mid uint32, payload interface{}) {
 	select {
 	case a.chPending <- pending:
 	default:
-		clog.Warnf("[sid = %s,uid = %d] send buffer exceed. [mid = %d, payload = %+v]",
+		a.Logger().Warnf("[sid = %s,uid = %d] send buffer exceed. [mid = %d, payload = %+v]",
 			a.SID(), a.UID(), mid, payload)
 	}
 }
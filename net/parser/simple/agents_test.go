@@ -5,6 +5,7 @@ import (
 
 	cfacade "github.com/cherry-game/cherry/facade"
 	cproto "github.com/cherry-game/cherry/net/proto"
+	"github.com/nats-io/nuid"
 )
 
 func newTestAgent(sid string) *Agent {
@@ -149,3 +150,21 @@ func TestCountAndForeach(t *testing.T) {
 		t.Fatalf("ForeachAgent saw %d, expected at least %d", seen, before+3)
 	}
 }
+
+func TestForeachNodeAgent(t *testing.T) {
+	for _, agentPath := range []string{"node-1.agent", "node-1.agent", "node-10.agent"} {
+		agent := newTestAgent(nuid.Next())
+		agent.session.AgentPath = agentPath
+		BindSID(agent)
+	}
+
+	if n := CountNode("node-1"); n != 2 {
+		t.Fatalf("expected 2 agents of node-1, got %d", n)
+	}
+
+	ForeachNodeAgent("node-10", func(a *Agent) {
+		if a.session.AgentPath != "node-10.agent" {
+			t.Fatalf("unexpected agent of %s", a.session.AgentPath)
+		}
+	})
+}
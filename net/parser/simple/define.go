@@ -10,6 +10,9 @@ const (
 	ResponseFuncName = "response"
 )
 
+// dataType is the packet type label of simple-protocol messages in metrics.
+const dataType = "Data"
+
 // Package-level configuration shared by all simple-protocol agents.
 var (
 	heartbeatTime                  = time.Second * 60 // heartbeat interval
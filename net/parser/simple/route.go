@@ -1,9 +1,14 @@
 package simple
 
 import (
+	"strconv"
+
 	cfacade "github.com/cherry-game/cherry/facade"
-	clog "github.com/cherry-game/cherry/logger"
+	cbalancer "github.com/cherry-game/cherry/net/balancer"
+	cmetrics "github.com/cherry-game/cherry/net/metrics"
 	cproto "github.com/cherry-game/cherry/net/proto"
+	csharding "github.com/cherry-game/cherry/net/sharding"
+	ctrace "github.com/cherry-game/cherry/net/trace"
 )
 
 // Package-level routing state.
@@ -12,6 +17,28 @@ var (
 	onDataRouteFunc = DefaultDataRoute        // data routing handler
 )
 
+// components are the optional components of the app a parser runs in, shared
+// by the agents of the parser. A nil component disables its feature.
+type components struct {
+	tracer     *ctrace.Tracer       // starts a trace per message
+	sharding   *csharding.Component // routes the messages of sharded entities
+	balancer   *cbalancer.Component // chooses the member receiving forwarded messages, nil picks a random one
+	packetsIn  *cmetrics.Counter    // received messages
+	packetsOut *cmetrics.Counter    // sent messages
+}
+
+func findComponents(app cfacade.IApplication) components {
+	registry := cmetrics.Find(app)
+
+	return components{
+		tracer:     ctrace.Find(app),
+		sharding:   csharding.Find(app),
+		balancer:   cbalancer.Find(app),
+		packetsIn:  registry.Counter("cherry_agent_packets_in_total", "Packets received from clients by type.", "type"),
+		packetsOut: registry.Counter("cherry_agent_packets_out_total", "Packets sent to clients by type.", "type"),
+	}
+}
+
 // NodeRoute describes the target actor and function for a given message id.
 type (
 	NodeRoute struct {
@@ -41,11 +68,18 @@ func GetNodeRoute(mid uint32) (*NodeRoute, bool) {
 
 // DefaultDataRoute is the default message routing handler. It dispatches locally
 // when the target node type matches the agent's node, or forwards to a random
-// member of the target node type via the cluster.
+// member of the target node type (or the one chosen by the balancer) via the cluster. Messages to a sharding region
+// are routed by ShardDataRoute.
 func DefaultDataRoute(agent *Agent, msg *Message, route *NodeRoute) {
 	session := agent.session
 	session.SetMID(msg.MID)
 
+	// sharded entity, the uid is the entity id
+	if region, found := agent.sharding.Region(route.ActorID); found && region.NodeType() == route.NodeType {
+		ShardDataRoute(agent, session, msg, route)
+		return
+	}
+
 	// current node
 	if agent.NodeType() == route.NodeType {
 		targetPath := cfacade.NewChildPath(agent.NodeID(), route.ActorID, session.Sid)
@@ -54,7 +88,7 @@ func DefaultDataRoute(agent *Agent, msg *Message, route *NodeRoute) {
 	}
 
 	if !session.IsBind() {
-		clog.Warnf("[sid = %s,uid = %d] Session is not bind with UID. failed to forward message.[route = %+v]",
+		agent.Logger().Warnf("[sid = %s,uid = %d] Session is not bind with UID. failed to forward message.[route = %+v]",
 			agent.SID(),
 			agent.UID(),
 			route,
@@ -62,7 +96,11 @@ func DefaultDataRoute(agent *Agent, msg *Message, route *NodeRoute) {
 		return
 	}
 
-	member, found := agent.Discovery().Random(route.NodeType)
+	member, found := agent.balancer.Select(route.NodeType, session.BalanceKey())
+	if !found {
+		member, found = agent.Discovery().Random(route.NodeType)
+	}
+
 	if !found {
 		return
 	}
@@ -71,6 +109,39 @@ func DefaultDataRoute(agent *Agent, msg *Message, route *NodeRoute) {
 	ClusterLocalDataRoute(agent, session, msg, route, member.GetNodeID(), targetPath)
 }
 
+// ShardDataRoute routes a message to the entity of the session uid on the node
+// owning its shard, route.ActorID is the region name.
+func ShardDataRoute(agent *Agent, session *cproto.Session, msg *Message, route *NodeRoute) {
+	region, found := agent.sharding.Region(route.ActorID)
+	if !found {
+		return
+	}
+
+	if !session.IsBind() {
+		agent.Logger().Warnf("[sid = %s,uid = %d] Session is not bind with UID. failed to route sharded message.[route = %+v]",
+			agent.SID(),
+			agent.UID(),
+			route,
+		)
+		return
+	}
+
+	entityID := strconv.FormatInt(session.Uid, 10)
+	nodeID, found := region.Owner(entityID)
+	if !found {
+		agent.Logger().Warnf("[sid = %s,uid = %d] shard owner not found. [route = %+v]", agent.SID(), agent.UID(), route)
+		return
+	}
+
+	targetPath := cfacade.NewChildPath(nodeID, region.Name(), entityID)
+	if nodeID == agent.NodeID() {
+		LocalDataRoute(agent, session, msg, route, targetPath)
+		return
+	}
+
+	ClusterLocalDataRoute(agent, session, msg, route, nodeID, targetPath)
+}
+
 // LocalDataRoute posts a message to a local actor on this node.
 func LocalDataRoute(agent *Agent, session *cproto.Session, msg *Message, nodeRoute *NodeRoute, targetPath string) {
 	message := cfacade.GetMessage()
@@ -78,6 +149,8 @@ func LocalDataRoute(agent *Agent, session *cproto.Session, msg *Message, nodeRou
 	message.Target = targetPath
 	message.FuncName = nodeRoute.FuncName
 	message.Session = session
+	message.TraceID = session.TraceId
+	message.SpanID = session.SpanId
 	message.Args = msg.Data
 
 	agent.ActorSystem().PostLocal(message)
@@ -90,6 +163,8 @@ func ClusterLocalDataRoute(agent *Agent, session *cproto.Session, msg *Message,
 	message.Target = targetPath
 	message.FuncName = nodeRoute.FuncName
 	message.Session = session
+	message.TraceID = session.TraceId
+	message.SpanID = session.SpanId
 	message.Args = msg.Data
 
 	return agent.Cluster().PublishLocal(nodeID, message)
@@ -27,5 +27,9 @@ func (x *ClusterPacket) Recycle() {
 	x.FuncName = ""
 	x.ArgBytes = nil
 	x.Session = nil
+	x.Deadline = 0
+	x.TraceId = ""
+	x.SpanId = ""
+	x.Hops = 0
 	clusterPacketPool.Put(x)
 }
@@ -1,9 +1,20 @@
 package cherryProto
 
+import "google.golang.org/protobuf/proto"
+
 func (x *Member) IsTimeout(nowMills int64) bool {
 	return x.LastAt+x.HeartbeatTimeout < nowMills
 }
 
+// Clone returns a deep copy of the member, its settings are never nil.
+func (x *Member) Clone() *Member {
+	member := proto.Clone(x).(*Member)
+	if member.Settings == nil {
+		member.Settings = make(map[string]string)
+	}
+	return member
+}
+
 func (x *Member) UpdateSettings(settings map[string]string) {
 	if settings == nil {
 		return
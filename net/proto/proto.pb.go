@@ -164,6 +164,7 @@ type Member struct {
 	Settings         map[string]string      `protobuf:"bytes,4,rep,name=settings,proto3" json:"settings,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // node settings data
 	LastAt           int64                  `protobuf:"varint,5,opt,name=lastAt,proto3" json:"lastAt,omitempty"`                                                                              // last check time
 	HeartbeatTimeout int64                  `protobuf:"varint,6,opt,name=heartbeatTimeout,proto3" json:"heartbeatTimeout,omitempty"`                                                          // The heartbeat timeout period (in milliseconds) for custom node configuration
+	Status           int32                  `protobuf:"varint,7,opt,name=status,proto3" json:"status,omitempty"`                                                                              // member status 0.serving 1.joining 2.draining 3.leaving
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -240,6 +241,13 @@ func (x *Member) GetHeartbeatTimeout() int64 {
 	return 0
 }
 
+func (x *Member) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
 // member list data
 type MemberList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -288,8 +296,10 @@ func (x *MemberList) GetList() []*Member {
 // cross node response data
 type Response struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"` // message code
-	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`  // message data
+	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`                                                                                // message code
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`                                                                                 // message data
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`                                                                           // error message, set on failure
+	Details       map[string]string      `protobuf:"bytes,4,rep,name=details,proto3" json:"details,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // error details, set on failure
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -338,6 +348,20 @@ func (x *Response) GetData() []byte {
 	return nil
 }
 
+func (x *Response) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Response) GetDetails() map[string]string {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
 type ClusterPacket struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	BuildTime     int64                  `protobuf:"varint,1,opt,name=buildTime,proto3" json:"buildTime,omitempty"`
@@ -346,6 +370,10 @@ type ClusterPacket struct {
 	FuncName      string                 `protobuf:"bytes,4,opt,name=funcName,proto3" json:"funcName,omitempty"`
 	ArgBytes      []byte                 `protobuf:"bytes,5,opt,name=argBytes,proto3" json:"argBytes,omitempty"`
 	Session       *Session               `protobuf:"bytes,6,opt,name=session,proto3" json:"session,omitempty"`
+	Deadline      int64                  `protobuf:"varint,7,opt,name=deadline,proto3" json:"deadline,omitempty"` // call deadline (ms), 0 means no deadline
+	TraceId       string                 `protobuf:"bytes,8,opt,name=traceId,proto3" json:"traceId,omitempty"`    // trace id
+	SpanId        string                 `protobuf:"bytes,9,opt,name=spanId,proto3" json:"spanId,omitempty"`      // parent span id
+	Hops          int32                  `protobuf:"varint,10,opt,name=hops,proto3" json:"hops,omitempty"`        // number of times the message was forwarded
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -422,6 +450,34 @@ func (x *ClusterPacket) GetSession() *Session {
 	return nil
 }
 
+func (x *ClusterPacket) GetDeadline() int64 {
+	if x != nil {
+		return x.Deadline
+	}
+	return 0
+}
+
+func (x *ClusterPacket) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *ClusterPacket) GetSpanId() string {
+	if x != nil {
+		return x.SpanId
+	}
+	return ""
+}
+
+func (x *ClusterPacket) GetHops() int32 {
+	if x != nil {
+		return x.Hops
+	}
+	return 0
+}
+
 type Session struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Sid           string                 `protobuf:"bytes,1,opt,name=sid,proto3" json:"sid,omitempty"`                                                                             // session unique id
@@ -429,6 +485,8 @@ type Session struct {
 	AgentPath     string                 `protobuf:"bytes,3,opt,name=agentPath,proto3" json:"agentPath,omitempty"`                                                                 // frontend actor agent path
 	Ip            string                 `protobuf:"bytes,4,opt,name=ip,proto3" json:"ip,omitempty"`                                                                               // ip address
 	Data          map[string]string      `protobuf:"bytes,7,rep,name=data,proto3" json:"data,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // extend data
+	TraceId       string                 `protobuf:"bytes,8,opt,name=traceId,proto3" json:"traceId,omitempty"`                                                                     // trace id of the current request
+	SpanId        string                 `protobuf:"bytes,9,opt,name=spanId,proto3" json:"spanId,omitempty"`                                                                       // span id of the current request
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -498,12 +556,28 @@ func (x *Session) GetData() map[string]string {
 	return nil
 }
 
+func (x *Session) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *Session) GetSpanId() string {
+	if x != nil {
+		return x.SpanId
+	}
+	return ""
+}
+
 type PomeloResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Sid           string                 `protobuf:"bytes,1,opt,name=sid,proto3" json:"sid,omitempty"`
 	Mid           uint32                 `protobuf:"varint,2,opt,name=mid,proto3" json:"mid,omitempty"` // message id build by client
 	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
 	Code          int32                  `protobuf:"varint,4,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`                                                                           // error message
+	Details       map[string]string      `protobuf:"bytes,6,rep,name=details,proto3" json:"details,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // error details
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -566,6 +640,20 @@ func (x *PomeloResponse) GetCode() int32 {
 	return 0
 }
 
+func (x *PomeloResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *PomeloResponse) GetDetails() map[string]string {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
 type PomeloPush struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Sid           string                 `protobuf:"bytes,1,opt,name=sid,proto3" json:"sid,omitempty"`
@@ -778,23 +866,29 @@ const file_proto_proto_rawDesc = "" +
 	"\x03I32\x12\x14\n" +
 	"\x05value\x18\x01 \x01(\x05R\x05value\"\x1e\n" +
 	"\x06NodeID\x12\x14\n" +
-	"\x05value\x18\x01 \x01(\tR\x05value\"\x96\x02\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\"\xae\x02\n" +
 	"\x06Member\x12\x16\n" +
 	"\x06nodeID\x18\x01 \x01(\tR\x06nodeID\x12\x1a\n" +
 	"\bnodeType\x18\x02 \x01(\tR\bnodeType\x12\x18\n" +
 	"\aaddress\x18\x03 \x01(\tR\aaddress\x12=\n" +
 	"\bsettings\x18\x04 \x03(\v2!.cherryProto.Member.SettingsEntryR\bsettings\x12\x16\n" +
 	"\x06lastAt\x18\x05 \x01(\x03R\x06lastAt\x12*\n" +
-	"\x10heartbeatTimeout\x18\x06 \x01(\x03R\x10heartbeatTimeout\x1a;\n" +
+	"\x10heartbeatTimeout\x18\x06 \x01(\x03R\x10heartbeatTimeout\x12\x16\n" +
+	"\x06status\x18\a \x01(\x05R\x06status\x1a;\n" +
 	"\rSettingsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"5\n" +
 	"\n" +
 	"MemberList\x12'\n" +
-	"\x04list\x18\x01 \x03(\v2\x13.cherryProto.MemberR\x04list\"2\n" +
+	"\x04list\x18\x01 \x03(\v2\x13.cherryProto.MemberR\x04list\"\xc6\x01\n" +
 	"\bResponse\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x12\n" +
-	"\x04data\x18\x02 \x01(\fR\x04data\"\xd5\x01\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12<\n" +
+	"\adetails\x18\x04 \x03(\v2\".cherryProto.Response.DetailsEntryR\adetails\x1a:\n" +
+	"\fDetailsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb7\x02\n" +
 	"\rClusterPacket\x12\x1c\n" +
 	"\tbuildTime\x18\x01 \x01(\x03R\tbuildTime\x12\x1e\n" +
 	"\n" +
@@ -805,21 +899,33 @@ const file_proto_proto_rawDesc = "" +
 	"targetPath\x12\x1a\n" +
 	"\bfuncName\x18\x04 \x01(\tR\bfuncName\x12\x1a\n" +
 	"\bargBytes\x18\x05 \x01(\fR\bargBytes\x12.\n" +
-	"\asession\x18\x06 \x01(\v2\x14.cherryProto.SessionR\asession\"\xc8\x01\n" +
+	"\asession\x18\x06 \x01(\v2\x14.cherryProto.SessionR\asession\x12\x1a\n" +
+	"\bdeadline\x18\a \x01(\x03R\bdeadline\x12\x18\n" +
+	"\atraceId\x18\b \x01(\tR\atraceId\x12\x16\n" +
+	"\x06spanId\x18\t \x01(\tR\x06spanId\x12\x12\n" +
+	"\x04hops\x18\n" +
+	" \x01(\x05R\x04hops\"\xfa\x01\n" +
 	"\aSession\x12\x10\n" +
 	"\x03sid\x18\x01 \x01(\tR\x03sid\x12\x10\n" +
 	"\x03uid\x18\x02 \x01(\x03R\x03uid\x12\x1c\n" +
 	"\tagentPath\x18\x03 \x01(\tR\tagentPath\x12\x0e\n" +
 	"\x02ip\x18\x04 \x01(\tR\x02ip\x122\n" +
-	"\x04data\x18\a \x03(\v2\x1e.cherryProto.Session.DataEntryR\x04data\x1a7\n" +
+	"\x04data\x18\a \x03(\v2\x1e.cherryProto.Session.DataEntryR\x04data\x12\x18\n" +
+	"\atraceId\x18\b \x01(\tR\atraceId\x12\x16\n" +
+	"\x06spanId\x18\t \x01(\tR\x06spanId\x1a7\n" +
 	"\tDataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\\\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xf6\x01\n" +
 	"\x0ePomeloResponse\x12\x10\n" +
 	"\x03sid\x18\x01 \x01(\tR\x03sid\x12\x10\n" +
 	"\x03mid\x18\x02 \x01(\rR\x03mid\x12\x12\n" +
 	"\x04data\x18\x03 \x01(\fR\x04data\x12\x12\n" +
-	"\x04code\x18\x04 \x01(\x05R\x04code\"Z\n" +
+	"\x04code\x18\x04 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\x12B\n" +
+	"\adetails\x18\x06 \x03(\v2(.cherryProto.PomeloResponse.DetailsEntryR\adetails\x1a:\n" +
+	"\fDetailsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"Z\n" +
 	"\n" +
 	"PomeloPush\x12\x10\n" +
 	"\x03sid\x18\x01 \x01(\tR\x03sid\x12\x10\n" +
@@ -855,7 +961,7 @@ func file_proto_proto_rawDescGZIP() []byte {
 }
 
 var file_proto_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_proto_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_proto_proto_goTypes = []any{
 	(PomeloBroadcast_PushType)(0), // 0: cherryProto.PomeloBroadcast.PushType
 	(*I32)(nil),                   // 1: cherryProto.I32
@@ -870,19 +976,23 @@ var file_proto_proto_goTypes = []any{
 	(*PomeloKick)(nil),            // 10: cherryProto.PomeloKick
 	(*PomeloBroadcast)(nil),       // 11: cherryProto.PomeloBroadcast
 	nil,                           // 12: cherryProto.Member.SettingsEntry
-	nil,                           // 13: cherryProto.Session.DataEntry
+	nil,                           // 13: cherryProto.Response.DetailsEntry
+	nil,                           // 14: cherryProto.Session.DataEntry
+	nil,                           // 15: cherryProto.PomeloResponse.DetailsEntry
 }
 var file_proto_proto_depIdxs = []int32{
 	12, // 0: cherryProto.Member.settings:type_name -> cherryProto.Member.SettingsEntry
 	3,  // 1: cherryProto.MemberList.list:type_name -> cherryProto.Member
-	7,  // 2: cherryProto.ClusterPacket.session:type_name -> cherryProto.Session
-	13, // 3: cherryProto.Session.data:type_name -> cherryProto.Session.DataEntry
-	0,  // 4: cherryProto.PomeloBroadcast.pushType:type_name -> cherryProto.PomeloBroadcast.PushType
-	5,  // [5:5] is the sub-list for method output_type
-	5,  // [5:5] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	13, // 2: cherryProto.Response.details:type_name -> cherryProto.Response.DetailsEntry
+	7,  // 3: cherryProto.ClusterPacket.session:type_name -> cherryProto.Session
+	14, // 4: cherryProto.Session.data:type_name -> cherryProto.Session.DataEntry
+	15, // 5: cherryProto.PomeloResponse.details:type_name -> cherryProto.PomeloResponse.DetailsEntry
+	0,  // 6: cherryProto.PomeloBroadcast.pushType:type_name -> cherryProto.PomeloBroadcast.PushType
+	7,  // [7:7] is the sub-list for method output_type
+	7,  // [7:7] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_proto_proto_init() }
@@ -896,7 +1006,7 @@ func file_proto_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_proto_rawDesc), len(file_proto_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   13,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -1,6 +1,8 @@
 package cherryProto
 
 import (
+	"strconv"
+
 	cconst "github.com/cherry-game/cherry/const"
 	cstring "github.com/cherry-game/cherry/extend/string"
 )
@@ -13,6 +15,15 @@ func (x *Session) IsBind() bool {
 	return x.Uid > 0
 }
 
+// BalanceKey returns the key balancing the session across members, the uid
+// once bound, the sid before.
+func (x *Session) BalanceKey() string {
+	if x.IsBind() {
+		return strconv.FormatInt(x.Uid, 10)
+	}
+	return x.Sid
+}
+
 func (x *Session) ActorPath() string {
 	return x.AgentPath + cconst.DOT + x.Sid
 }
@@ -0,0 +1,153 @@
+package cherrySharding
+
+import (
+	"sync"
+
+	cfacade "github.com/cherry-game/cherry/facade"
+	cactor "github.com/cherry-game/cherry/net/actor"
+)
+
+/**
+- A region shards the entity actors (players, guilds, ...) of a node type:
+	- An entity id is hashed into one of a fixed number of shards.
+	- Each shard is owned by one member of the node type, chosen by rendezvous
+	  hashing, so every node computes the same owner from its discovery table.
+	- Entities are children of the region actor, "nodeID.regionName.entityID",
+	  created by the factory on their first message on the owning node.
+- When discovery adds or removes a member, or changes its status, the shards
+  are rebalanced, a member that is not serving (draining, leaving) owns no
+  shard. A settings update does not move shards. The
+  entities of a shard that moved away are passivated on the old owner and
+  activated on the new owner. Their queued messages, and the messages sent to
+  their old path, are forwarded to the new owner.
+- Client messages whose route handler is a region name are routed by the
+  session uid to the owning node (pomelo and simple parsers).
+*/
+
+var (
+	Name = "sharding_component"
+)
+
+type Component struct {
+	cfacade.Component
+	mu       sync.RWMutex
+	regions  map[string]*Region
+	statuses sync.Map // key:nodeID, value:member status seen by the last rebalance
+}
+
+func New() *Component {
+	return &Component{
+		regions: make(map[string]*Region),
+	}
+}
+
+func (c *Component) Name() string {
+	return Name
+}
+
+func (c *Component) Dependencies() []string {
+	return []string{cactor.Name}
+}
+
+// Register adds a region of the given node type, call it before startup.
+// name is the actor id of the region on every node of nodeType.
+func (c *Component) Register(name, nodeType string, shards int32, factory EntityFactory) *Region {
+	if shards < 1 {
+		shards = 1
+	}
+
+	region := &Region{
+		name:     name,
+		nodeType: nodeType,
+		shards:   shards,
+		factory:  factory,
+	}
+
+	c.mu.Lock()
+	c.regions[name] = region
+	c.mu.Unlock()
+
+	return region
+}
+
+// Region returns the region registered with name.
+func (c *Component) Region(name string) (*Region, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	region, found := c.regions[name]
+	return region, found
+}
+
+func (c *Component) OnAfterInit() {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, region := range c.regions {
+		region.app = c.App()
+		region.rebalance()
+
+		if c.App().NodeType() != region.nodeType {
+			continue
+		}
+
+		if _, err := c.App().ActorSystem().CreateActor(region.name, &regionActor{region: region}); err != nil {
+			c.App().Logger().Warnf("[%s] create region actor fail. [region = %s, err = %v]", Name, region.name, err)
+		}
+	}
+
+	// standalone mode has no discovery
+	if c.App().Discovery() == nil {
+		return
+	}
+
+	for _, member := range c.App().Discovery().Map() {
+		c.statuses.Store(member.GetNodeID(), member.GetStatus())
+	}
+
+	c.App().Discovery().OnAddMember(c.onMemberChanged)
+	c.App().Discovery().OnRemoveMember(c.onMemberRemoved)
+	c.App().Discovery().OnUpdateMember(c.onMemberUpdated)
+}
+
+// onMemberUpdated rebalances only when the status of the member changed,
+// the owners do not depend on its settings.
+func (c *Component) onMemberUpdated(member cfacade.IMember) {
+	if status, found := c.statuses.Load(member.GetNodeID()); found && status.(int32) == member.GetStatus() {
+		return
+	}
+
+	c.onMemberChanged(member)
+}
+
+func (c *Component) onMemberRemoved(member cfacade.IMember) {
+	c.statuses.Delete(member.GetNodeID())
+	c.rebalance(member.GetNodeType())
+}
+
+func (c *Component) onMemberChanged(member cfacade.IMember) {
+	c.statuses.Store(member.GetNodeID(), member.GetStatus())
+	c.rebalance(member.GetNodeType())
+}
+
+// rebalance recomputes the shard owners of the regions of nodeType.
+func (c *Component) rebalance(nodeType string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, region := range c.regions {
+		if region.nodeType == nodeType {
+			region.rebalance()
+		}
+	}
+}
+
+// Find returns the sharding component registered in app, or nil.
+func Find(app cfacade.IApplication) *Component {
+	component, _ := app.Find(Name).(*Component)
+	return component
+}
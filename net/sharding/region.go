@@ -0,0 +1,260 @@
+package cherrySharding
+
+import (
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	ccode "github.com/cherry-game/cherry/code"
+	cfacade "github.com/cherry-game/cherry/facade"
+	cactor "github.com/cherry-game/cherry/net/actor"
+	crendezvous "github.com/cherry-game/cherry/net/internal/rendezvous"
+)
+
+const (
+	// ActivateFuncName is called on the new owner to recreate a handed off entity.
+	ActivateFuncName = "activate"
+
+	// maxForwardHops bounds the forwards of an entity message while the
+	// members disagree about the owner of its shard.
+	maxForwardHops = 2
+)
+
+type (
+	// EntityFactory creates the handler of an entity actor.
+	EntityFactory func(entityID string) cfacade.IActorHandler
+
+	// Region shards the entity actors of a node type.
+	Region struct {
+		name     string                       // region actor id
+		nodeType string                       // node type owning the shards
+		shards   int32                        // number of shards
+		factory  EntityFactory                // creates entities on their owner
+		app      cfacade.IApplication         // set before startup completes
+		mu       sync.RWMutex                 // guards owners
+		owners   []string                     // shard → owner nodeID, "" if no member
+		actor    atomic.Pointer[cactor.Actor] // region actor on this node, nil if not running
+	}
+
+	regionActor struct {
+		cactor.Base
+		region *Region
+	}
+)
+
+func (r *Region) Name() string {
+	return r.name
+}
+
+func (r *Region) NodeType() string {
+	return r.nodeType
+}
+
+func (r *Region) Shards() int32 {
+	return r.shards
+}
+
+// ShardOf returns the shard of entityID.
+func (r *Region) ShardOf(entityID string) int32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(entityID))
+	return int32(h.Sum32() % uint32(r.shards))
+}
+
+// Owner returns the nodeID owning the shard of entityID.
+func (r *Region) Owner(entityID string) (string, bool) {
+	shard := r.ShardOf(entityID)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if int(shard) >= len(r.owners) || r.owners[shard] == "" {
+		return "", false
+	}
+
+	return r.owners[shard], true
+}
+
+// Path returns the actor path of entityID on its owner.
+func (r *Region) Path(entityID string) (string, bool) {
+	nodeID, found := r.Owner(entityID)
+	if !found {
+		return "", false
+	}
+
+	return cfacade.NewChildPath(nodeID, r.name, entityID), true
+}
+
+// Call calls funcName of the entity on its owner.
+func (r *Region) Call(source, entityID, funcName string, arg any) int32 {
+	targetPath, found := r.Path(entityID)
+	if !found {
+		r.app.Logger().Warnf("[%s] shard owner not found. [entityID = %s]", r.name, entityID)
+		return ccode.DiscoveryNotFoundNode
+	}
+
+	return r.app.ActorSystem().Call(source, targetPath, funcName, arg)
+}
+
+// CallWait calls funcName of the entity on its owner and waits for reply.
+func (r *Region) CallWait(source, entityID, funcName string, arg, reply any) int32 {
+	targetPath, found := r.Path(entityID)
+	if !found {
+		r.app.Logger().Warnf("[%s] shard owner not found. [entityID = %s]", r.name, entityID)
+		return ccode.DiscoveryNotFoundNode
+	}
+
+	return r.app.ActorSystem().CallWait(source, targetPath, funcName, arg, reply)
+}
+
+// isLocal reports whether this node owns the shard of entityID.
+func (r *Region) isLocal(entityID string) bool {
+	nodeID, found := r.Owner(entityID)
+	return found && nodeID == r.app.NodeID()
+}
+
+// rebalance assigns every shard to the member with the highest rendezvous
+// hash and hands off the local entities of the shards that moved away.
+func (r *Region) rebalance() {
+	nodeIDs := crendezvous.Members(r.app, r.nodeType)
+
+	owners := make([]string, r.shards)
+	for shard := range owners {
+		owners[shard] = crendezvous.Select(strconv.Itoa(shard), nodeIDs)
+	}
+
+	r.mu.Lock()
+	r.owners = owners
+	r.mu.Unlock()
+
+	r.handoff()
+}
+
+// handoff passivates the local entities this node no longer owns, and
+// activates each of them on its new owner.
+func (r *Region) handoff() {
+	thisActor := r.actor.Load()
+	if thisActor == nil {
+		return
+	}
+
+	source := thisActor.PathString()
+	thisActor.Child().Each(func(child cfacade.IActor) {
+		entityID := child.Path().ChildID
+		if r.isLocal(entityID) {
+			return
+		}
+
+		entity, ok := child.(*cactor.Actor)
+		if !ok {
+			return
+		}
+
+		entity.Passivate(func() {
+			if targetPath, found := r.Path(entityID); found {
+				r.app.ActorSystem().Call(source, targetPath, ActivateFuncName, nil)
+			}
+		})
+
+		r.app.Logger().Infof("[%s] hand off entity. [entityID = %s]", r.name, entityID)
+	})
+}
+
+func (p *regionActor) AliasID() string {
+	return p.region.name
+}
+
+func (p *regionActor) OnInit() {
+	p.Child().SetFactory(p.newEntity)
+	p.region.actor.Store(p.Actor)
+}
+
+func (p *regionActor) OnStop() {
+	p.region.actor.CompareAndSwap(p.Actor, nil)
+}
+
+// OnLocalReceived forwards the messages of entities owned by other nodes.
+func (p *regionActor) OnLocalReceived(m *cfacade.Message) (bool, bool) {
+	if p.forward(m, true) {
+		return false, false
+	}
+
+	return true, false
+}
+
+// OnRemoteReceived forwards the messages of entities owned by other nodes,
+// and creates the entity targeted by an activate message.
+func (p *regionActor) OnRemoteReceived(m *cfacade.Message) (bool, bool) {
+	if p.forward(m, false) {
+		return false, false
+	}
+
+	if m.FuncName != ActivateFuncName || !m.TargetPath().IsChild() {
+		return true, false
+	}
+
+	entityID := m.TargetPath().ChildID
+	if _, found := p.Child().Get(entityID); found {
+		return false, false
+	}
+
+	if handler, found := p.newEntity(entityID); found {
+		if _, err := p.Child().Create(entityID, handler); err != nil {
+			p.App().Logger().Warnf("[%s] activate entity fail. [entityID = %s, err = %v]", p.region.name, entityID, err)
+		}
+	}
+
+	return false, false
+}
+
+// forward sends m to the owner of its entity when the entity moved away, e.g.
+// the messages queued by a handed off entity or sent before the sender saw
+// the new owner. It reports whether m was forwarded.
+//
+// A message already forwarded maxForwardHops times is not forwarded again:
+// it is refused like any entity of another shard, so the dead-letter actor
+// receives it and a waiting caller gets ActorChildIDNotFound.
+func (p *regionActor) forward(m *cfacade.Message, local bool) bool {
+	targetPath := m.TargetPath()
+	if !targetPath.IsChild() || p.region.isLocal(targetPath.ChildID) {
+		return false
+	}
+
+	if m.Hops >= maxForwardHops {
+		p.region.app.Logger().Warnf("[%s] forward hop limit reached. [target = %s, hops = %d]", p.region.name, m.Target, m.Hops)
+		return false
+	}
+
+	if _, found := p.Child().Get(targetPath.ChildID); found {
+		return false
+	}
+
+	ownerPath, found := p.region.Path(targetPath.ChildID)
+	if !found {
+		return false
+	}
+
+	var code int32
+	if local {
+		code = p.ForwardLocal(m, ownerPath)
+	} else {
+		code = p.ForwardRemote(m, ownerPath)
+	}
+
+	if ccode.IsFail(code) {
+		p.App().Logger().Warnf("[%s] forward entity message fail. [target = %s -> %s, code = %d]", p.region.name, m.Target, ownerPath, code)
+	}
+
+	return true
+}
+
+// newEntity refuses the entities of the shards owned by other nodes.
+func (p *regionActor) newEntity(entityID string) (cfacade.IActorHandler, bool) {
+	if !p.region.isLocal(entityID) {
+		return nil, false
+	}
+
+	handler := p.region.factory(entityID)
+	return handler, handler != nil
+}
@@ -0,0 +1,129 @@
+package cherrySharding
+
+import (
+	"strconv"
+	"testing"
+
+	cfacade "github.com/cherry-game/cherry/facade"
+	clog "github.com/cherry-game/cherry/logger"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+// testApp implements the parts of cfacade.IApplication used by rebalance and forward.
+type testApp struct {
+	cfacade.IApplication
+	nodeID    string
+	nodeType  string
+	discovery cfacade.IDiscovery
+}
+
+func (a *testApp) NodeID() string                { return a.nodeID }
+func (a *testApp) NodeType() string              { return a.nodeType }
+func (a *testApp) Logger() cfacade.ILogger       { return clog.DefaultLogger }
+func (a *testApp) Discovery() cfacade.IDiscovery { return a.discovery }
+
+// testDiscovery knows a fixed set of members.
+type testDiscovery struct {
+	cfacade.IDiscovery
+	members []*cproto.Member
+}
+
+func (d *testDiscovery) ListByType(nodeType string, filterNodeID ...string) []cfacade.IMember {
+	var list []cfacade.IMember
+	for _, member := range d.members {
+		if member.NodeType != nodeType || member.Status != cfacade.MemberServing {
+			continue
+		}
+		if len(filterNodeID) > 0 && member.NodeID == filterNodeID[0] {
+			continue
+		}
+		list = append(list, member)
+	}
+	return list
+}
+
+func (d *testDiscovery) Map() map[string]cfacade.IMember {
+	members := make(map[string]cfacade.IMember, len(d.members))
+	for _, member := range d.members {
+		members[member.NodeID] = member
+	}
+	return members
+}
+
+func (d *testDiscovery) OnAddMember(cfacade.MemberListener)    {}
+func (d *testDiscovery) OnRemoveMember(cfacade.MemberListener) {}
+func (d *testDiscovery) OnUpdateMember(cfacade.MemberListener) {}
+
+func (d *testDiscovery) GetMember(nodeID string) (cfacade.IMember, bool) {
+	for _, member := range d.members {
+		if member.NodeID == nodeID {
+			return member, true
+		}
+	}
+	return nil, false
+}
+
+// TestRegion_ShardOf verifies that entities map to a stable shard in range.
+func TestRegion_ShardOf(t *testing.T) {
+	region := &Region{shards: 16}
+
+	for i := 0; i < 100; i++ {
+		entityID := strconv.Itoa(i)
+		shard := region.ShardOf(entityID)
+		if shard < 0 || shard >= 16 || shard != region.ShardOf(entityID) {
+			t.Fatalf("entity %s: unexpected shard %d", entityID, shard)
+		}
+	}
+}
+
+// TestRegionActor_ForwardHopLimit verifies that a message already forwarded
+// maxForwardHops times is not forwarded again.
+func TestRegionActor_ForwardHopLimit(t *testing.T) {
+	region := &Region{
+		name:   "room",
+		shards: 1,
+		owners: []string{"game-2"},
+		app:    &testApp{nodeID: "game-1", nodeType: "game"},
+	}
+
+	m := cfacade.GetMessage()
+	defer m.Recycle()
+	m.Target = cfacade.NewChildPath("game-1", "room", "1")
+	m.Hops = maxForwardHops
+
+	if (&regionActor{region: region}).forward(m, false) {
+		t.Fatal("a message at the hop limit should not be forwarded")
+	}
+}
+
+// TestComponent_RebalanceOnStatus verifies that a member update rebalances
+// the shards only when the status of the member changed.
+func TestComponent_RebalanceOnStatus(t *testing.T) {
+	game2 := &cproto.Member{NodeID: "game-2", NodeType: "game", Status: cfacade.MemberServing}
+	discovery := &testDiscovery{members: []*cproto.Member{game2}}
+
+	c := New()
+	c.Set(&testApp{nodeID: "center-1", nodeType: "center", discovery: discovery})
+	region := c.Register("room", "game", 1, nil)
+	c.OnAfterInit()
+
+	if owner, _ := region.Owner("1"); owner != "game-2" {
+		t.Fatalf("expected game-2, got %s", owner)
+	}
+
+	// a settings update keeps the owners, even if the view moved meanwhile
+	discovery.members = append(discovery.members, &cproto.Member{NodeID: "game-3", NodeType: "game"})
+	game2.Settings = map[string]string{"load": "1"}
+	c.onMemberUpdated(game2)
+
+	if owner, _ := region.Owner("1"); owner != "game-2" {
+		t.Fatalf("a settings update should not rebalance, got %s", owner)
+	}
+
+	game2.Status = cfacade.MemberDraining
+	c.onMemberUpdated(game2)
+
+	if owner, _ := region.Owner("1"); owner != "game-3" {
+		t.Fatalf("expected game-3 once game-2 drains, got %s", owner)
+	}
+}
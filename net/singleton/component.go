@@ -0,0 +1,250 @@
+package cherrySingleton
+
+import (
+	"strings"
+	"sync"
+
+	cfacade "github.com/cherry-game/cherry/facade"
+	cactor "github.com/cherry-game/cherry/net/actor"
+	crendezvous "github.com/cherry-game/cherry/net/internal/rendezvous"
+)
+
+/**
+- A singleton actor runs on exactly one member of its node type:
+	- The host is chosen by rendezvous hashing of the actor id over the members
+	  of the node type, so every node computes the same host from its discovery table.
+	- When discovery adds, removes or updates a member the host is chosen again,
+	  a member that is not serving (draining, leaving) hosts nothing. The old
+	  host hands the actor off (IPassivate.OnPassivate saves its state, queued
+	  messages are forwarded to the new host) and the new host creates it
+	  through the factory.
+	- A host elected again before its old instance has stopped creates the
+	  actor once that instance has exited.
+	- Every node elects from its own discovery view. While the views disagree,
+	  e.g. a member joined or left and not every node has seen it yet, two
+	  nodes can both elect themselves and run the actor at the same time, until
+	  the views converge. Callers reach the host of their own view. A singleton
+	  whose effects must not happen twice has to fence them itself, e.g. with a
+	  lease or a version checked by the storage it writes to.
+- Callers reach it through the logical path Path(actorID), "@singleton.actorID",
+  which Call and CallWait resolve to the current host.
+*/
+
+const (
+	// NodeID is the node id of the logical paths of singletons.
+	NodeID = "@singleton"
+)
+
+var (
+	Name = "singleton_component"
+)
+
+type (
+	Component struct {
+		cfacade.Component
+		mu         sync.RWMutex
+		singletons map[string]*Singleton
+	}
+
+	// Factory creates the handler of a singleton actor.
+	Factory func() cfacade.IActorHandler
+
+	// Singleton is an actor hosted by one member of a node type.
+	Singleton struct {
+		actorID  string
+		nodeType string
+		factory  Factory
+		app      cfacade.IApplication
+		mu       sync.Mutex    // guards host and serializes failover
+		host     string        // current host nodeID, "" if no member
+		leaving  *cactor.Actor // instance handed off by this node, until it has exited
+	}
+)
+
+func New() *Component {
+	return &Component{
+		singletons: make(map[string]*Singleton),
+	}
+}
+
+func (c *Component) Name() string {
+	return Name
+}
+
+func (c *Component) Dependencies() []string {
+	return []string{cactor.Name}
+}
+
+// Register declares a singleton actor of the given node type, call it before startup.
+func (c *Component) Register(actorID, nodeType string, factory Factory) *Singleton {
+	singleton := &Singleton{
+		actorID:  actorID,
+		nodeType: nodeType,
+		factory:  factory,
+	}
+
+	c.mu.Lock()
+	c.singletons[actorID] = singleton
+	c.mu.Unlock()
+
+	return singleton
+}
+
+// Get returns the singleton registered with actorID.
+func (c *Component) Get(actorID string) (*Singleton, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	singleton, found := c.singletons[actorID]
+	return singleton, found
+}
+
+func (c *Component) Init() {
+	c.App().ActorSystem().AddResolver(c.resolve)
+}
+
+func (c *Component) OnAfterInit() {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, singleton := range c.singletons {
+		singleton.app = c.App()
+		singleton.elect()
+	}
+
+	// standalone mode has no discovery
+	if c.App().Discovery() == nil {
+		return
+	}
+
+	c.App().Discovery().OnAddMember(c.onMemberChanged)
+	c.App().Discovery().OnRemoveMember(c.onMemberChanged)
+	c.App().Discovery().OnUpdateMember(c.onMemberChanged)
+}
+
+func (c *Component) onMemberChanged(member cfacade.IMember) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, singleton := range c.singletons {
+		if singleton.nodeType == member.GetNodeType() {
+			singleton.elect()
+		}
+	}
+}
+
+// resolve translates "@singleton.actorID" into the path of the actor on its host.
+func (c *Component) resolve(target string) (string, bool) {
+	actorID, found := strings.CutPrefix(target, NodeID+".")
+	if !found {
+		return "", false
+	}
+
+	singleton, found := c.Get(actorID)
+	if !found {
+		return "", false
+	}
+
+	host, found := singleton.Host()
+	if !found {
+		c.App().Logger().Warnf("[%s] singleton host not found. [actorID = %s]", Name, actorID)
+		return "", false
+	}
+
+	return cfacade.NewPath(host, actorID), true
+}
+
+// Path returns the logical path of the singleton actorID.
+func Path(actorID string) string {
+	return cfacade.NewPath(NodeID, actorID)
+}
+
+// Find returns the singleton component registered in app, or nil.
+func Find(app cfacade.IApplication) *Component {
+	component, _ := app.Find(Name).(*Component)
+	return component
+}
+
+func (s *Singleton) ActorID() string {
+	return s.actorID
+}
+
+func (s *Singleton) NodeType() string {
+	return s.nodeType
+}
+
+// Host returns the nodeID currently hosting the singleton.
+func (s *Singleton) Host() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.host, s.host != ""
+}
+
+// elect chooses the host and moves the actor when the host changed.
+func (s *Singleton) elect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	host := crendezvous.Select(s.actorID, crendezvous.Members(s.app, s.nodeType))
+	if host == s.host {
+		return
+	}
+
+	s.app.Logger().Infof("[%s] singleton host changed. [actorID = %s, host = %s -> %s]", Name, s.actorID, s.host, host)
+
+	nodeID := s.app.NodeID()
+	oldHost := s.host
+	s.host = host
+
+	switch {
+	case oldHost == nodeID:
+		if thisActor, found := s.app.ActorSystem().GetIActor(s.actorID); found {
+			if a, ok := thisActor.(*cactor.Actor); ok {
+				// the queued messages follow the singleton to the host resolved when it stops
+				a.Handoff(Path(s.actorID))
+				s.leaving = a
+			}
+		}
+	case host == nodeID:
+		s.create()
+	}
+}
+
+// create creates the actor on this node, once the instance this node handed
+// off has exited. Call it with s.mu held.
+func (s *Singleton) create() {
+	if leaving := s.leaving; leaving != nil {
+		select {
+		case <-leaving.Exited():
+			s.leaving = nil
+		default:
+			go s.createAfter(leaving)
+			return
+		}
+	}
+
+	if _, err := s.app.ActorSystem().CreateActor(s.actorID, s.factory()); err != nil {
+		s.app.Logger().Warnf("[%s] create singleton fail. [actorID = %s, err = %v]", Name, s.actorID, err)
+	}
+}
+
+// createAfter waits for leaving to exit and creates the actor if this node is
+// still the host.
+func (s *Singleton) createAfter(leaving *cactor.Actor) {
+	<-leaving.Exited()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// a later election handed off or created the actor meanwhile
+	if s.host != s.app.NodeID() || s.leaving != leaving {
+		return
+	}
+
+	s.create()
+}
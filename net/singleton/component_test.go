@@ -0,0 +1,110 @@
+package cherrySingleton
+
+import (
+	"testing"
+
+	cfacade "github.com/cherry-game/cherry/facade"
+	clog "github.com/cherry-game/cherry/logger"
+	crendezvous "github.com/cherry-game/cherry/net/internal/rendezvous"
+	cproto "github.com/cherry-game/cherry/net/proto"
+)
+
+// testApp implements the parts of cfacade.IApplication used by elect and
+// resolve. The current node is draining, so it never hosts the singleton.
+type testApp struct {
+	cfacade.IApplication
+	discovery *testDiscovery
+}
+
+func (a *testApp) NodeID() string                { return "game-1" }
+func (a *testApp) NodeType() string              { return "game" }
+func (a *testApp) Logger() cfacade.ILogger       { return clog.DefaultLogger }
+func (a *testApp) Discovery() cfacade.IDiscovery { return a.discovery }
+
+// testDiscovery lists its members as serving, except the current node.
+type testDiscovery struct {
+	cfacade.IDiscovery
+	nodeIDs []string
+}
+
+func (d *testDiscovery) ListByType(nodeType string, filterNodeID ...string) []cfacade.IMember {
+	var list []cfacade.IMember
+	for _, nodeID := range d.nodeIDs {
+		list = append(list, &cproto.Member{NodeID: nodeID, NodeType: nodeType})
+	}
+	return list
+}
+
+func (d *testDiscovery) GetMember(nodeID string) (cfacade.IMember, bool) {
+	return &cproto.Member{NodeID: nodeID, NodeType: "game", Status: cfacade.MemberDraining}, true
+}
+
+func newTestComponent(nodeIDs ...string) (*Component, *Singleton, *testDiscovery) {
+	discovery := &testDiscovery{nodeIDs: nodeIDs}
+
+	c := New()
+	c.Set(&testApp{discovery: discovery})
+
+	singleton := c.Register("boss", "game", nil)
+	singleton.app = c.App()
+	return c, singleton, discovery
+}
+
+// TestSingleton_Elect verifies that the host is the rendezvous of the serving
+// members and moves only when the host leaves.
+func TestSingleton_Elect(t *testing.T) {
+	_, singleton, discovery := newTestComponent("game-2", "game-3", "game-4")
+
+	singleton.elect()
+	host, found := singleton.Host()
+	if !found || host != crendezvous.Select("boss", discovery.nodeIDs) {
+		t.Fatalf("unexpected host %s", host)
+	}
+
+	// removing another member keeps the host
+	var others []string
+	for _, nodeID := range discovery.nodeIDs {
+		if nodeID != host {
+			others = append(others, nodeID)
+		}
+	}
+
+	discovery.nodeIDs = []string{host, others[0]}
+	singleton.elect()
+	if nodeID, _ := singleton.Host(); nodeID != host {
+		t.Fatalf("host moved from %s to %s", host, nodeID)
+	}
+
+	discovery.nodeIDs = others
+	singleton.elect()
+	if nodeID, _ := singleton.Host(); nodeID == host || nodeID != crendezvous.Select("boss", others) {
+		t.Fatalf("unexpected host %s after %s left", nodeID, host)
+	}
+
+	discovery.nodeIDs = nil
+	singleton.elect()
+	if _, found = singleton.Host(); found {
+		t.Fatal("expected no host without members")
+	}
+}
+
+// TestComponent_Resolve verifies that logical paths resolve to the host.
+func TestComponent_Resolve(t *testing.T) {
+	c, singleton, _ := newTestComponent("game-2")
+
+	if _, found := c.resolve(Path("boss")); found {
+		t.Fatal("expected no path before election")
+	}
+
+	singleton.elect()
+
+	if target, found := c.resolve(Path("boss")); !found || target != "game-2.boss" {
+		t.Fatalf("expected game-2.boss, got %s", target)
+	}
+
+	for _, target := range []string{"game-2.boss", Path("unknown")} {
+		if _, found := c.resolve(target); found {
+			t.Fatalf("%s should not resolve", target)
+		}
+	}
+}
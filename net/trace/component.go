@@ -0,0 +1,80 @@
+package cherryTrace
+
+import (
+	cfacade "github.com/cherry-game/cherry/facade"
+	cactor "github.com/cherry-game/cherry/net/actor"
+)
+
+/**
+- A trace is started by the agent for every client request; its ids travel in
+  Session and ClusterPacket (Message.TraceID/SpanID between actors).
+- Spans are recorded around the request at the agent, every actor invocation and
+  every cross-node CallWait.
+- Each span is shipped to the exporter with its parent span id, so the request
+  tree can be rebuilt offline, e.g. from the lines of a FileExporter.
+*/
+
+var (
+	Name = "trace_component"
+)
+
+type Component struct {
+	cfacade.Component
+	tracer *Tracer
+}
+
+// New creates the trace component, spans are shipped to exporter.
+func New(exporter IExporter) *Component {
+	return &Component{
+		tracer: NewTracer("", exporter),
+	}
+}
+
+func (c *Component) Name() string {
+	return Name
+}
+
+func (c *Component) Init() {
+	c.tracer.nodeID = c.App().NodeID()
+}
+
+// OnAfterInit attaches the tracer to the actor system.
+func (c *Component) OnAfterInit() {
+	if system := cactor.Find(c.App()); system != nil {
+		system.SetTracer(actorTracer{c.tracer})
+	}
+}
+
+func (c *Component) OnStop() {
+	if err := c.tracer.exporter.Close(); err != nil {
+		c.App().Logger().Warnf("[%s] close exporter error. err = %v", Name, err)
+	}
+}
+
+// Tracer returns the tracer of the component.
+func (c *Component) Tracer() *Tracer {
+	return c.tracer
+}
+
+// Find returns the tracer of the trace component registered in app,
+// or nil when tracing is disabled.
+func Find(app cfacade.IApplication) *Tracer {
+	if component, ok := app.Find(Name).(*Component); ok {
+		return component.tracer
+	}
+
+	return nil
+}
+
+// actorTracer is the tracer as seen by the actor system.
+type actorTracer struct {
+	tracer *Tracer
+}
+
+func (t actorTracer) Start(traceID, parentID, name string) cactor.ISpan {
+	if span := t.tracer.Start(traceID, parentID, name); span != nil {
+		return span
+	}
+
+	return nil // not sampled, a nil *Span would not compare to nil
+}
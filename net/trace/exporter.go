@@ -0,0 +1,129 @@
+package cherryTrace
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	clog "github.com/cherry-game/cherry/logger"
+	jsoniter "github.com/json-iterator/go"
+)
+
+type (
+	// IExporter ships finished spans, e.g. to a file or a tracing backend.
+	// Export is called concurrently and must not block for long.
+	IExporter interface {
+		Export(span *Span) // ship a finished span
+		Close() error      // flush the pending spans and release resources
+	}
+
+	// FileExporter writes one JSON object per span per line.
+	FileExporter struct {
+		mu     sync.Mutex
+		file   *os.File
+		writer *bufio.Writer
+		done   chan struct{}
+		closed bool
+	}
+
+	// nopExporter discards the spans, used when no exporter is given.
+	nopExporter struct{}
+)
+
+func (nopExporter) Export(*Span) {}
+
+func (nopExporter) Close() error {
+	return nil
+}
+
+// NewFileExporter opens (or creates) the file at path in append mode.
+// Buffered spans are flushed every flushInterval and on Close.
+func NewFileExporter(path string, flushInterval time.Duration) (*FileExporter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter := &FileExporter{
+		file:   file,
+		writer: bufio.NewWriter(file),
+		done:   make(chan struct{}),
+	}
+
+	if flushInterval > 0 {
+		go exporter.flushLoop(flushInterval)
+	}
+
+	return exporter, nil
+}
+
+func (p *FileExporter) Export(span *Span) {
+	line, err := jsoniter.Marshal(span)
+	if err != nil {
+		clog.Warnf("[FileExporter] marshal span error. [traceId = %s, name = %s, err = %v]", span.TraceID, span.Name, err)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return
+	}
+
+	p.writer.Write(line)
+	p.writer.WriteByte('\n')
+}
+
+// Flush writes the buffered spans to the file.
+func (p *FileExporter) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+
+	return p.writer.Flush()
+}
+
+func (p *FileExporter) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+
+	p.closed = true
+	close(p.done)
+
+	if err := p.writer.Flush(); err != nil {
+		p.file.Close()
+		return err
+	}
+
+	return p.file.Close()
+}
+
+func (p *FileExporter) flushLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.Flush(); err != nil {
+				clog.Warnf("[FileExporter] flush error. [file = %s, err = %v]", p.file.Name(), err)
+			}
+		case <-p.done:
+			return
+		}
+	}
+}
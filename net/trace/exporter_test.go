@@ -0,0 +1,89 @@
+package cherryTrace
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// TestFileExporter verifies that finished spans are written as JSON lines
+// linked to their parent.
+func TestFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace", "spans.log")
+	exporter, err := NewFileExporter(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracer := NewTracer("game-1", exporter)
+	root := tracer.Start("", "", "agent")
+	child := tracer.Start(root.TraceID, root.SpanID, "remote:login")
+	child.SetAttr("actor", "game-1.player")
+	child.Finish(21)
+	root.Finish(0)
+
+	if err = exporter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var spans []Span
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var span Span
+		if err = jsoniter.Unmarshal(scanner.Bytes(), &span); err != nil {
+			t.Fatal(err)
+		}
+		spans = append(spans, span)
+	}
+
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	if spans[0].ParentID != spans[1].SpanID || spans[0].TraceID != spans[1].TraceID {
+		t.Fatalf("child not linked to root. %+v", spans)
+	}
+
+	if spans[0].Code != 21 || spans[0].NodeID != "game-1" || spans[0].Attrs["actor"] != "game-1.player" {
+		t.Fatalf("unexpected child span %+v", spans[0])
+	}
+}
+
+// TestTracer_Sample verifies that sampling only drops new traces.
+func TestTracer_Sample(t *testing.T) {
+	var tracer *Tracer
+	if span := tracer.Start("", "", "agent"); span != nil {
+		t.Fatal("expected nil span from a nil tracer")
+	}
+
+	tracer = NewTracer("game-1", nil)
+	tracer.SetSampleRate(0)
+
+	if span := tracer.Start("", "", "agent"); span != nil {
+		t.Fatal("expected new trace to be dropped")
+	}
+
+	span := tracer.Start("0123456789abcdef0123456789abcdef", "0123456789abcdef", "remote:login")
+	if span == nil {
+		t.Fatal("expected span of an existing trace")
+	}
+
+	// without exporter the span is discarded
+	span.Finish(0)
+}
+
+// TestComponent_NilExporter verifies that a component without exporter stops cleanly.
+func TestComponent_NilExporter(t *testing.T) {
+	component := New(nil)
+	component.Tracer().Start("", "", "agent").Finish(0)
+	component.OnStop()
+}
@@ -0,0 +1,54 @@
+package cherryTrace
+
+import (
+	"time"
+)
+
+// Span is a timed operation of a request. Spans of the same request share the
+// TraceID and are linked by ParentID, so the request tree can be rebuilt offline.
+type Span struct {
+	TraceID  string            `json:"traceId"`            // id shared by all spans of a request
+	SpanID   string            `json:"spanId"`             // id of this span
+	ParentID string            `json:"parentId,omitempty"` // id of the parent span, empty for the root span
+	Name     string            `json:"name"`               // operation name
+	NodeID   string            `json:"nodeId"`             // node that recorded the span
+	Start    int64             `json:"start"`              // start time (unix µs)
+	Duration int64             `json:"duration"`           // duration (µs)
+	Code     int32             `json:"code"`               // cherryCode of the operation
+	Attrs    map[string]string `json:"attrs,omitempty"`    // extra attributes
+	tracer   *Tracer
+}
+
+// GetSpanID returns the id of the span, "" on a nil span.
+func (s *Span) GetSpanID() string {
+	if s == nil {
+		return ""
+	}
+
+	return s.SpanID
+}
+
+// SetAttr sets an attribute of the span. It is a no-op on a nil span.
+func (s *Span) SetAttr(key, value string) {
+	if s == nil {
+		return
+	}
+
+	if s.Attrs == nil {
+		s.Attrs = make(map[string]string)
+	}
+
+	s.Attrs[key] = value
+}
+
+// Finish ends the span with code and hands it to the exporter.
+// It is a no-op on a nil span.
+func (s *Span) Finish(code int32) {
+	if s == nil {
+		return
+	}
+
+	s.Duration = time.Now().UnixMicro() - s.Start
+	s.Code = code
+	s.tracer.exporter.Export(s)
+}
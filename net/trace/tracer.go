@@ -0,0 +1,71 @@
+package cherryTrace
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand/v2"
+	"time"
+)
+
+// Tracer creates the spans of a node. A nil *Tracer is valid and records nothing.
+type Tracer struct {
+	nodeID     string
+	exporter   IExporter
+	sampleRate float64 // ratio of new traces that are recorded
+}
+
+// NewTracer creates a tracer that ships its spans to exporter.
+// A nil exporter discards the spans.
+func NewTracer(nodeID string, exporter IExporter) *Tracer {
+	if exporter == nil {
+		exporter = nopExporter{}
+	}
+
+	return &Tracer{
+		nodeID:     nodeID,
+		exporter:   exporter,
+		sampleRate: 1,
+	}
+}
+
+// SetSampleRate sets the ratio (0~1) of new traces that are recorded.
+// Spans of a trace started elsewhere are always recorded.
+func (t *Tracer) SetSampleRate(rate float64) {
+	t.sampleRate = rate
+}
+
+// Start starts a span named name, child of parentID in trace traceID.
+// An empty traceID starts a new trace, which may be dropped by the sampler.
+// It returns nil when the tracer is nil or the trace is not sampled.
+func (t *Tracer) Start(traceID, parentID, name string) *Span {
+	if t == nil {
+		return nil
+	}
+
+	if traceID == "" {
+		if t.sampleRate < 1 && rand.Float64() >= t.sampleRate {
+			return nil
+		}
+
+		traceID = newID(16)
+		parentID = ""
+	}
+
+	return &Span{
+		TraceID:  traceID,
+		SpanID:   newID(8),
+		ParentID: parentID,
+		Name:     name,
+		NodeID:   t.nodeID,
+		Start:    time.Now().UnixMicro(),
+		tracer:   t,
+	}
+}
+
+// newID returns a random hex id of size bytes (size is 8 or 16).
+func newID(size int) string {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], rand.Uint64())
+	binary.BigEndian.PutUint64(b[8:], rand.Uint64())
+	return hex.EncodeToString(b[:size])
+}
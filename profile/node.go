@@ -97,10 +97,10 @@ func GetNodeWithConfig(config *Config, nodeID string) (cfacade.INode, error) {
 	return nil, cerr.Errorf("nodeID %s not found", nodeID)
 }
 
-// LoadNode looks up a node from the already-loaded global config.
-// Must be called after Init(); panics if cfg.jsonConfig is nil.
+// LoadNode looks up a node from the default profile.
+// Must be called after Init(); panics if no profile is loaded.
 func LoadNode(nodeID string) (cfacade.INode, error) {
-	return GetNodeWithConfig(cfg.jsonConfig, nodeID)
+	return cfg.LoadNode(nodeID)
 }
 
 // findNodeID checks whether the given nodeID matches the node_id config entry.
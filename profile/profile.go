@@ -6,13 +6,15 @@
 //   - Resolve per-node configuration (node identity, address, settings)
 //   - Provide type-safe config reading via the ProfileJSON interface
 //
-// Note: this package uses package-level global state (cfg). Only one Application
-// instance per process is supported. Multiple instances will overwrite each other's
-// global config.
+// A Profile holds one loaded profile file. Load returns a new Profile for each
+// call, so several Application instances can run in one process, each with its
+// own config. Init additionally installs the loaded Profile as the package
+// default read by Path, Name, Env, Debug, PrintLevel and GetConfig.
 package cherryProfile
 
 import (
 	"path/filepath"
+	"time"
 
 	cerror "github.com/cherry-game/cherry/error"
 	cfile "github.com/cherry-game/cherry/extend/file"
@@ -21,47 +23,134 @@ import (
 	cfacade "github.com/cherry-game/cherry/facade"
 )
 
-// cfg is the package-level global config singleton.
-// Written once by Init(), then read-only by the getter functions.
-// Not goroutine-safe: Init() must complete during startup before any reads.
-var (
-	cfg = &struct {
+type (
+	// Profile is a loaded profile config file.
+	// Written once by Load(), then read-only by the getter methods.
+	Profile struct {
 		profilePath string  // absolute path to the profile config directory
 		profileName string  // profile config filename (e.g. "dev.json")
 		jsonConfig  *Config // merged JSON config tree (main + includes)
 		env         string  // environment name (e.g. "dev", "test", "prod")
 		debug       bool    // debug mode flag, defaults to true
 		printLevel  string  // cherry log output level, defaults to "debug"
-	}{}
+		shutdown    int64   // shutdown drain deadline in seconds, 0 disables the drain phase
+	}
+)
+
+// cfg is the package default profile, installed by Init() or SetDefault().
+// Not goroutine-safe: Init() must complete during startup before any reads.
+var (
+	cfg = &Profile{}
 )
 
 // Path returns the absolute path to the profile config directory.
-func Path() string {
-	return cfg.profilePath
+func (p *Profile) Path() string {
+	return p.profilePath
 }
 
 // Name returns the profile config filename (e.g. "dev.json").
-func Name() string {
-	return cfg.profileName
+func (p *Profile) Name() string {
+	return p.profileName
 }
 
 // Env returns the current environment name (e.g. "dev", "test", "prod").
-func Env() string {
-	return cfg.env
+func (p *Profile) Env() string {
+	return p.env
 }
 
 // Debug returns whether debug mode is enabled.
-func Debug() bool {
-	return cfg.debug
+func (p *Profile) Debug() bool {
+	return p.debug
 }
 
 // PrintLevel returns the cherry log output level (e.g. "debug", "info", "warn", "error").
+func (p *Profile) PrintLevel() string {
+	return p.printLevel
+}
+
+// ShutdownTimeout returns the deadline of the shutdown drain phase, read from
+// the "shutdown_timeout" property in seconds. Returns 0 (drain disabled) if unset.
+func (p *Profile) ShutdownTimeout() time.Duration {
+	return time.Duration(p.shutdown) * time.Second
+}
+
+// GetConfig reads a sub-config from the config tree at the given path.
+// Path semantics match jsoniter.Get. On a Profile that is not loaded the
+// returned config reports an error through LastError().
+func (p *Profile) GetConfig(path ...any) cfacade.ProfileJSON {
+	if p.jsonConfig == nil {
+		return Wrap(nil).GetConfig(path...)
+	}
+	return p.jsonConfig.GetConfig(path...)
+}
+
+// LoadNode looks up a node from the loaded config.
+func (p *Profile) LoadNode(nodeID string) (cfacade.INode, error) {
+	return GetNodeWithConfig(p.jsonConfig, nodeID)
+}
+
+// Loaded returns true once a profile file has been loaded into p.
+func (p *Profile) Loaded() bool {
+	return p.jsonConfig != nil
+}
+
+// Default returns the package default profile.
+func Default() *Profile {
+	return cfg
+}
+
+// SetDefault replaces the package default profile.
+func SetDefault(profile *Profile) {
+	if profile != nil {
+		cfg = profile
+	}
+}
+
+// Path returns the profile config directory of the default profile.
+func Path() string {
+	return cfg.Path()
+}
+
+// Name returns the profile config filename of the default profile.
+func Name() string {
+	return cfg.Name()
+}
+
+// Env returns the environment name of the default profile.
+func Env() string {
+	return cfg.Env()
+}
+
+// Debug returns whether debug mode is enabled in the default profile.
+func Debug() bool {
+	return cfg.Debug()
+}
+
+// PrintLevel returns the cherry log output level of the default profile.
 func PrintLevel() string {
-	return cfg.printLevel
+	return cfg.PrintLevel()
+}
+
+// ShutdownTimeout returns the shutdown drain deadline of the default profile.
+func ShutdownTimeout() time.Duration {
+	return cfg.ShutdownTimeout()
 }
 
-// Init loads the profile config file and returns the configuration for the
-// specified node.
+// Init loads the profile config file, installs it as the package default and
+// returns the configuration for the specified node. See Load.
+func Init(filePath, nodeID string) (cfacade.INode, error) {
+	profile, node, err := Load(filePath, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg = profile
+
+	return node, nil
+}
+
+// Load loads the profile config file and returns it together with the
+// configuration for the specified node. The package default is left untouched.
 //
 // filePath is the path to the profile JSON file, nodeID is the target node
 // identifier.
@@ -73,49 +162,48 @@ func PrintLevel() string {
 //  3. Search the "node" section for a node matching nodeID
 //
 // The returned INode provides the node's address, type, settings, etc.
-// Also initializes the package-level global config (cfg) for use by Path, Name,
-// Env, Debug, PrintLevel, and GetConfig.
-func Init(filePath, nodeID string) (cfacade.INode, error) {
+func Load(filePath, nodeID string) (*Profile, cfacade.INode, error) {
 	if filePath == "" {
-		return nil, cerror.Error("file path is empty")
+		return nil, nil, cerror.Error("file path is empty")
 	}
 
 	if nodeID == "" {
-		return nil, cerror.Error("nodeID is empty")
+		return nil, nil, cerror.Error("nodeID is empty")
 	}
 
 	judgePath, ok := cfile.JudgeFile(filePath)
 	if !ok {
-		return nil, cerror.Errorf("invalid file path: %s", filePath)
+		return nil, nil, cerror.Errorf("invalid file path: %s", filePath)
 	}
 
 	p, f := filepath.Split(judgePath)
 	jsonConfig, err := LoadFile(p, f)
 	if err != nil || jsonConfig.Any == nil || jsonConfig.LastError() != nil {
-		return nil, cerror.Errorf("failed to load profile file: %v", err)
+		return nil, nil, cerror.Errorf("failed to load profile file: %v", err)
 	}
 
 	node, err := GetNodeWithConfig(jsonConfig, nodeID)
 	if err != nil {
-		return nil, cerror.Errorf("node config not found in profile file: %v", err)
+		return nil, nil, cerror.Errorf("node config not found in profile file: %v", err)
 	}
 
-	// init cfg
-	cfg.profilePath = p
-	cfg.profileName = f
-	cfg.jsonConfig = jsonConfig
-	cfg.env = jsonConfig.GetString("env", "default")
-	cfg.debug = jsonConfig.GetBool("debug", true)
-	cfg.printLevel = jsonConfig.GetString("print_level", "debug")
+	profile := &Profile{
+		profilePath: p,
+		profileName: f,
+		jsonConfig:  jsonConfig,
+		env:         jsonConfig.GetString("env", "default"),
+		debug:       jsonConfig.GetBool("debug", true),
+		printLevel:  jsonConfig.GetString("print_level", "debug"),
+		shutdown:    jsonConfig.GetInt64("shutdown_timeout", 0),
+	}
 
-	return node, nil
+	return profile, node, nil
 }
 
-// GetConfig reads a sub-config from the global config tree at the given path.
-// Path semantics match jsoniter.Get. Must be called after Init(), otherwise
-// cfg.jsonConfig is nil and this will panic.
+// GetConfig reads a sub-config from the default profile at the given path.
+// Before Init() the returned config reports an error through LastError().
 func GetConfig(path ...any) cfacade.ProfileJSON {
-	return cfg.jsonConfig.GetConfig(path...)
+	return cfg.GetConfig(path...)
 }
 
 // LoadFile loads and merges profile config files.